@@ -10,10 +10,18 @@ import (
 )
 
 var (
+	// TaskTypeAggregate is the identifier for the Aggregate adapter.
+	TaskTypeAggregate = models.MustNewTaskType("aggregate")
+	// TaskTypeConditional is the identifier for the Conditional adapter.
+	TaskTypeConditional = models.MustNewTaskType("conditional")
 	// TaskTypeCopy is the identifier for the Copy adapter.
 	TaskTypeCopy = models.MustNewTaskType("copy")
+	// TaskTypeEthABIDecode is the identifier for the EthABIDecode adapter.
+	TaskTypeEthABIDecode = models.MustNewTaskType("ethabidecode")
 	// TaskTypeEthBool is the identifier for the EthBool adapter.
 	TaskTypeEthBool = models.MustNewTaskType("ethbool")
+	// TaskTypeEthCall is the identifier for the EthCall adapter.
+	TaskTypeEthCall = models.MustNewTaskType("ethcall")
 	// TaskTypeEthBytes32 is the identifier for the EthBytes32 adapter.
 	TaskTypeEthBytes32 = models.MustNewTaskType("ethbytes32")
 	// TaskTypeEthInt256 is the identifier for the EthInt256 adapter.
@@ -44,6 +52,8 @@ var (
 	TaskTypeRandom = models.MustNewTaskType("random")
 	// TaskTypeCompare is the identifier for the Compare adapter.
 	TaskTypeCompare = models.MustNewTaskType("compare")
+	// TaskTypeTransform is the identifier for the Transform adapter.
+	TaskTypeTransform = models.MustNewTaskType("transform")
 )
 
 // BaseAdapter is the minimum interface required to create an adapter. Only core
@@ -77,12 +87,27 @@ func For(task models.TaskSpec, store *store.Store) (*PipelineAdapter, error) {
 	mcp := assets.NewLink(0)
 
 	switch task.Type {
+	case TaskTypeAggregate:
+		aggregate := &Aggregate{}
+		ba = aggregate
+		if err = unmarshalParams(task.Params, aggregate); err == nil {
+			err = aggregate.validate(store)
+		}
+	case TaskTypeConditional:
+		ba = &Conditional{}
+		err = unmarshalParams(task.Params, ba)
 	case TaskTypeCopy:
 		ba = &Copy{}
 		err = unmarshalParams(task.Params, ba)
+	case TaskTypeEthABIDecode:
+		ba = &EthABIDecode{}
+		err = unmarshalParams(task.Params, ba)
 	case TaskTypeEthBool:
 		ba = &EthBool{}
 		err = unmarshalParams(task.Params, ba)
+	case TaskTypeEthCall:
+		ba = &EthCall{}
+		err = unmarshalParams(task.Params, ba)
 	case TaskTypeEthBytes32:
 		ba = &EthBytes32{}
 		err = unmarshalParams(task.Params, ba)
@@ -130,6 +155,9 @@ func For(task models.TaskSpec, store *store.Store) (*PipelineAdapter, error) {
 	case TaskTypeCompare:
 		ba = &Compare{}
 		err = unmarshalParams(task.Params, ba)
+	case TaskTypeTransform:
+		ba = &Transform{}
+		err = unmarshalParams(task.Params, ba)
 	default:
 		bt, err := store.FindBridge(task.Type)
 		if err != nil {
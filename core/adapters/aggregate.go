@@ -0,0 +1,275 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// aggregatePrecision is the precision, in bits, of the arithmetic used to
+// aggregate source results.
+const aggregatePrecision = 128
+
+// AggregateMethod is the function used to combine the results of the sources
+// of an Aggregate task into a single value.
+type AggregateMethod string
+
+const (
+	// AggregateMedian takes the middle value, or the mean of the two middle
+	// values when there is an even number of results.
+	AggregateMedian = AggregateMethod("median")
+	// AggregateMean takes the arithmetic mean of the results.
+	AggregateMean = AggregateMethod("mean")
+	// AggregateTrimmedMean takes the mean after discarding the Trim fraction
+	// of the lowest and of the highest results.
+	AggregateTrimmedMean = AggregateMethod("trimmedmean")
+	// AggregateMode takes the most frequent result, preferring the lowest
+	// value when several are equally frequent.
+	AggregateMode = AggregateMethod("mode")
+)
+
+// AggregateSource is a sub-pipeline of tasks whose final result is one of
+// the values to aggregate.
+type AggregateSource struct {
+	Name  string            `json:"name,omitempty"`
+	Tasks []models.TaskSpec `json:"tasks"`
+}
+
+// AggregateSourceResult records the outcome of a single source.
+type AggregateSourceResult struct {
+	Name     string `json:"name,omitempty"`
+	Result   string `json:"result,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Included bool   `json:"included"`
+}
+
+// Aggregate runs each of its sources in parallel and combines their numeric
+// results using the configured method.
+type Aggregate struct {
+	Sources      []AggregateSource `json:"sources"`
+	Method       AggregateMethod   `json:"method"`
+	Trim         float64           `json:"trim"`
+	MinResponses int               `json:"minResponses"`
+	MaxDeviation float64           `json:"maxDeviation"`
+}
+
+// validate checks the parameters and that every task of every source is
+// supported.
+func (a *Aggregate) validate(store *store.Store) error {
+	if len(a.Sources) == 0 {
+		return errors.New("aggregate requires at least one source")
+	}
+	switch a.method() {
+	case AggregateMedian, AggregateMean, AggregateTrimmedMean, AggregateMode:
+	default:
+		return fmt.Errorf("aggregate method %s is not one of median, mean, trimmedmean or mode", a.Method)
+	}
+	if a.Trim < 0 || a.Trim >= 0.5 {
+		return errors.New("aggregate trim must be at least 0 and less than 0.5")
+	}
+	if a.MinResponses > len(a.Sources) {
+		return fmt.Errorf("aggregate minResponses %d exceeds the %d sources", a.MinResponses, len(a.Sources))
+	}
+	if a.MaxDeviation < 0 {
+		return errors.New("aggregate maxDeviation cannot be negative")
+	}
+	for i, source := range a.Sources {
+		if len(source.Tasks) == 0 {
+			return fmt.Errorf("aggregate source %d has no tasks", i)
+		}
+		for _, task := range source.Tasks {
+			if _, err := For(task, store); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Aggregate) method() AggregateMethod {
+	if a.Method == "" {
+		return AggregateMedian
+	}
+	return a.Method
+}
+
+func (a *Aggregate) minResponses() int {
+	if a.MinResponses < 1 {
+		return 1
+	}
+	return a.MinResponses
+}
+
+// Perform runs every source's tasks against the input and aggregates their
+// results. The outcome of every source is recorded under "sources", and the
+// aggregate under "result".
+//
+// For example, three sources returning "100", "101" and "250", with a
+// maxDeviation of 0.1, discard "250" and result in a median of "100.5".
+func (a *Aggregate) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	results := make([]AggregateSourceResult, len(a.Sources))
+	values := make([]*big.Float, len(a.Sources))
+	var wg sync.WaitGroup
+	for i, source := range a.Sources {
+		wg.Add(1)
+		go func(i int, source AggregateSource) {
+			defer wg.Done()
+			results[i].Name = source.Name
+			value, err := performSource(source, input, store)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			values[i] = value
+			results[i].Result = value.Text('f', -1)
+			results[i].Included = true
+		}(i, source)
+	}
+	wg.Wait()
+
+	a.filterDeviations(results, values)
+
+	included := []*big.Float{}
+	for i, value := range values {
+		if results[i].Included {
+			included = append(included, value)
+		}
+	}
+
+	var output models.RunResult
+	output.Add("sources", results)
+	if len(included) < a.minResponses() {
+		output.SetError(fmt.Errorf("aggregate received %d valid responses, needs at least %d", len(included), a.minResponses()))
+		return output
+	}
+	output.CompleteWithResult(a.aggregate(included).Text('f', -1))
+	return output
+}
+
+// performSource runs the source's tasks in order, feeding each task the data
+// accumulated so far, and parses the final result as a number.
+func performSource(source AggregateSource, input models.RunResult, store *store.Store) (*big.Float, error) {
+	current := models.RunResult{CachedJobRunID: input.CachedJobRunID, Data: input.Data}
+	for _, task := range source.Tasks {
+		adapter, err := For(task, store)
+		if err != nil {
+			return nil, err
+		}
+		result := adapter.Perform(current, store)
+		if result.HasError() {
+			return nil, result.GetError()
+		}
+		if !result.Status.Completed() {
+			return nil, fmt.Errorf("%s task did not complete, status %s", task.Type, result.Status)
+		}
+		data, err := current.Data.Merge(result.Data)
+		if err != nil {
+			return nil, err
+		}
+		current.Data = data
+	}
+
+	raw := current.Result().String()
+	value, _, err := big.ParseFloat(raw, 10, aggregatePrecision, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse result %q as a number", raw)
+	}
+	return value, nil
+}
+
+// filterDeviations excludes the values further than MaxDeviation, as a
+// fraction, from the median of all values.
+func (a *Aggregate) filterDeviations(results []AggregateSourceResult, values []*big.Float) {
+	if a.MaxDeviation == 0 {
+		return
+	}
+	all := []*big.Float{}
+	for i, value := range values {
+		if results[i].Included {
+			all = append(all, value)
+		}
+	}
+	if len(all) == 0 {
+		return
+	}
+
+	median := aggregateMedian(all)
+	threshold := newAggregateFloat().Abs(median)
+	threshold.Mul(threshold, newAggregateFloat().SetFloat64(a.MaxDeviation))
+	for i, value := range values {
+		if !results[i].Included {
+			continue
+		}
+		deviation := newAggregateFloat().Sub(value, median)
+		if deviation.Abs(deviation).Cmp(threshold) > 0 {
+			results[i].Included = false
+			results[i].Error = fmt.Sprintf("deviates from the median %s by more than %v", median.Text('f', -1), a.MaxDeviation)
+		}
+	}
+}
+
+func (a *Aggregate) aggregate(values []*big.Float) *big.Float {
+	switch a.method() {
+	case AggregateMean:
+		return aggregateMean(values)
+	case AggregateTrimmedMean:
+		sorted := sortedFloats(values)
+		trim := int(float64(len(sorted)) * a.Trim)
+		return aggregateMean(sorted[trim : len(sorted)-trim])
+	case AggregateMode:
+		return aggregateMode(values)
+	default:
+		return aggregateMedian(values)
+	}
+}
+
+func newAggregateFloat() *big.Float {
+	return new(big.Float).SetPrec(aggregatePrecision)
+}
+
+func sortedFloats(values []*big.Float) []*big.Float {
+	sorted := make([]*big.Float, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+	return sorted
+}
+
+func aggregateMedian(values []*big.Float) *big.Float {
+	sorted := sortedFloats(values)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return aggregateMean(sorted[middle-1 : middle+1])
+}
+
+func aggregateMean(values []*big.Float) *big.Float {
+	sum := newAggregateFloat()
+	for _, value := range values {
+		sum.Add(sum, value)
+	}
+	return sum.Quo(sum, newAggregateFloat().SetInt64(int64(len(values))))
+}
+
+func aggregateMode(values []*big.Float) *big.Float {
+	sorted := sortedFloats(values)
+	mode, best := sorted[0], 0
+	for i := 0; i < len(sorted); {
+		j := i
+		for j < len(sorted) && sorted[j].Cmp(sorted[i]) == 0 {
+			j++
+		}
+		if j-i > best {
+			mode, best = sorted[i], j-i
+		}
+		i = j
+	}
+	return mode
+}
@@ -0,0 +1,141 @@
+package adapters_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func copySources(keys ...string) string {
+	sources := ""
+	for i, key := range keys {
+		if i > 0 {
+			sources += ","
+		}
+		sources += fmt.Sprintf(`{"name":"%s","tasks":[{"type":"copy","params":{"copyPath":["%s"]}}]}`, key, key)
+	}
+	return "[" + sources + "]"
+}
+
+func TestAggregate_Perform(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	input := cltest.RunResultWithData(`{"a":"100","b":"101","c":"250","d":"101","e":"oops"}`)
+
+	tests := []struct {
+		name       string
+		params     string
+		wantResult string
+		wantError  string
+	}{
+		{"median", `{"sources":` + copySources("a", "b", "c") + `}`, "101", ""},
+		{"median of even count", `{"sources":` + copySources("a", "b") + `}`, "100.5", ""},
+		{"mean", `{"method":"mean","sources":` + copySources("a", "b", "c", "d") + `}`, "138", ""},
+		{"trimmed mean", `{"method":"trimmedmean","trim":0.25,"sources":` + copySources("a", "b", "c", "d") + `}`, "101", ""},
+		{"mode", `{"method":"mode","sources":` + copySources("a", "b", "c", "d") + `}`, "101", ""},
+		{"max deviation", `{"maxDeviation":0.1,"sources":` + copySources("a", "b", "c") + `}`, "100.5", ""},
+		{"failed source ignored", `{"minResponses":2,"sources":` + copySources("a", "e", "c") + `}`, "175", ""},
+		{"too few responses", `{"minResponses":3,"sources":` + copySources("a", "e", "c") + `}`, "", "aggregate received 2 valid responses, needs at least 3"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			task := models.TaskSpec{Type: adapters.TaskTypeAggregate, Params: cltest.JSONFromString(t, test.params)}
+			adapter, err := adapters.For(task, store)
+			require.NoError(t, err)
+
+			result := adapter.Perform(input, store)
+			if test.wantError != "" {
+				assert.Equal(t, models.RunStatusErrored, result.Status)
+				assert.Equal(t, test.wantError, result.Error())
+			} else {
+				require.NoError(t, result.GetError())
+				assert.Equal(t, models.RunStatusCompleted, result.Status)
+				assert.Equal(t, test.wantResult, result.Result().String())
+			}
+		})
+	}
+}
+
+func TestAggregate_Perform_RecordsSources(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	input := cltest.RunResultWithData(`{"a":"100","b":"101","c":"250"}`)
+	task := models.TaskSpec{
+		Type:   adapters.TaskTypeAggregate,
+		Params: cltest.JSONFromString(t, `{"maxDeviation":0.1,"sources":`+copySources("a", "b", "c")+`}`),
+	}
+	adapter, err := adapters.For(task, store)
+	require.NoError(t, err)
+
+	result := adapter.Perform(input, store)
+	require.NoError(t, result.GetError())
+	assert.JSONEq(t, `[
+		{"name":"a","result":"100","included":true},
+		{"name":"b","result":"101","included":true},
+		{"name":"c","result":"250","error":"deviates from the median 101 by more than 0.1","included":false}
+	]`, result.Get("sources").Raw)
+}
+
+func TestAggregate_Perform_HTTPSources(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	first, cleanupFirst := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", `{"last":"10.5"}`)
+	defer cleanupFirst()
+	second, cleanupSecond := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", `{"last":11.5}`)
+	defer cleanupSecond()
+	failing, cleanupFailing := cltest.NewHTTPMockServer(t, http.StatusInternalServerError, "GET", `down`)
+	defer cleanupFailing()
+
+	source := `{"tasks":[{"type":"httpget","params":{"get":"%s"}},{"type":"jsonparse","params":{"path":["last"]}}]}`
+	params := fmt.Sprintf(`{"minResponses":2,"sources":[`+source+`,`+source+`,`+source+`]}`, first.URL, second.URL, failing.URL)
+	task := models.TaskSpec{Type: adapters.TaskTypeAggregate, Params: cltest.JSONFromString(t, params)}
+	adapter, err := adapters.For(task, store)
+	require.NoError(t, err)
+
+	result := adapter.Perform(models.RunResult{}, store)
+	require.NoError(t, result.GetError())
+	assert.Equal(t, "11", result.Result().String())
+	assert.Equal(t, "down", result.Get("sources.2.error").String())
+}
+
+func TestAggregate_Validation(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{"no sources", `{"sources":[]}`, "aggregate requires at least one source"},
+		{"unknown method", `{"method":"max","sources":` + copySources("a") + `}`, "aggregate method max is not one of median, mean, trimmedmean or mode"},
+		{"trim too large", `{"method":"trimmedmean","trim":0.5,"sources":` + copySources("a") + `}`, "aggregate trim must be at least 0 and less than 0.5"},
+		{"too many responses", `{"minResponses":2,"sources":` + copySources("a") + `}`, "aggregate minResponses 2 exceeds the 1 sources"},
+		{"empty source", `{"sources":[{"tasks":[]}]}`, "aggregate source 0 has no tasks"},
+		{"unknown task", `{"sources":[{"tasks":[{"type":"idonotexist"}]}]}`, "idonotexist is not a supported adapter type"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			task := models.TaskSpec{Type: adapters.TaskTypeAggregate, Params: cltest.JSONFromString(t, test.params)}
+			_, err := adapters.For(task, store)
+			assert.EqualError(t, err, test.want)
+		})
+	}
+}
@@ -7,11 +7,21 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 )
 
+var promBridgeLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "bridge_latency_seconds",
+	Help:    "Bridge latency in seconds, from sending the request until the response was received",
+	Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+}, []string{"name"})
+
 // Bridge adapter is responsible for connecting the task pipeline to external
 // adapters, allowing for custom computations to be executed and included in runs.
 type Bridge struct {
@@ -101,23 +111,28 @@ func (ba *Bridge) postToExternalAdapter(input models.RunResult, bridgeResponseUR
 	request.Header.Set("Content-Type", "application/json")
 
 	client := http.Client{}
+	start := time.Now()
 	resp, err := client.Do(request)
+	promBridgeLatency.WithLabelValues(ba.Name.String()).Observe(time.Since(start).Seconds())
 	if err != nil {
-		return nil, fmt.Errorf("POST request: %v", err)
+		return nil, errors.Wrap(err, "POST request")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		b, _ := ioutil.ReadAll(resp.Body)
-		err = fmt.Errorf("%v %v", resp.StatusCode, string(b))
-		return nil, fmt.Errorf("POST response: %v", err)
+		err = models.HTTPResponseError{
+			StatusCode: resp.StatusCode,
+			Body:       fmt.Sprintf("%v %v", resp.StatusCode, string(b)),
+		}
+		return nil, errors.Wrap(err, "POST response")
 	}
 
 	return ioutil.ReadAll(resp.Body)
 }
 
 func baRunResultError(str string, err error) error {
-	return fmt.Errorf("ExternalBridge %v: %v", str, err)
+	return errors.Wrapf(err, "ExternalBridge %v", str)
 }
 
 type bridgeOutgoing struct {
@@ -0,0 +1,171 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/tidwall/gjson"
+)
+
+// ConditionalAction is what the run does after a Conditional task.
+type ConditionalAction string
+
+const (
+	// ConditionalContinue carries on with the next task.
+	ConditionalContinue = ConditionalAction("continue")
+	// ConditionalHalt completes the run successfully, skipping every
+	// remaining task.
+	ConditionalHalt = ConditionalAction("halt")
+	// ConditionalSkip skips the next Count tasks.
+	ConditionalSkip = ConditionalAction("skip")
+	// ConditionalJump skips every task up to, but not including, the task at
+	// index To of the job's tasks.
+	ConditionalJump = ConditionalAction("jump")
+	// ConditionalBranchTasks runs the tasks at the indexes listed in Tasks,
+	// and skips the tasks listed only by the other branch, so that both
+	// branches of an if/else can be laid out one after the other, followed
+	// by the tasks that run after either of them.
+	ConditionalBranchTasks = ConditionalAction("branch")
+)
+
+// ConditionalBranch is the action taken for one outcome of the predicate.
+type ConditionalBranch struct {
+	Action ConditionalAction `json:"action"`
+	Count  int               `json:"count,omitempty"`
+	To     int               `json:"to,omitempty"`
+	Tasks  []int             `json:"tasks,omitempty"`
+}
+
+// Validate checks that the branch of the conditional task at the given
+// index stays within the job's tasks.
+func (b ConditionalBranch) Validate(index, taskCount int) error {
+	if len(b.Tasks) > 0 && b.Action != ConditionalBranchTasks {
+		return fmt.Errorf("conditional action %s does not take tasks, only branch does", b.Action)
+	}
+
+	switch b.Action {
+	case ConditionalContinue, ConditionalHalt:
+	case ConditionalSkip:
+		if b.Count < 1 || index+b.Count >= taskCount {
+			return fmt.Errorf("conditional task %d cannot skip %d tasks out of %d", index, b.Count, taskCount)
+		}
+	case ConditionalJump:
+		if b.To <= index || b.To >= taskCount {
+			return fmt.Errorf("conditional task %d cannot jump to task %d, it must be a later task", index, b.To)
+		}
+	case ConditionalBranchTasks:
+		if len(b.Tasks) == 0 {
+			return fmt.Errorf("conditional task %d has a branch without tasks", index)
+		}
+		previous := index
+		for _, task := range b.Tasks {
+			if task <= previous || task >= taskCount {
+				return fmt.Errorf("conditional task %d cannot branch to task %d, branch tasks must be later tasks in order", index, task)
+			}
+			previous = task
+		}
+	default:
+		return fmt.Errorf("conditional action %s is not one of continue, halt, skip, jump or branch", b.Action)
+	}
+	return nil
+}
+
+// exclusiveTasks returns the tasks of the branch that the other branch does
+// not also run.
+func (b ConditionalBranch) exclusiveTasks(other ConditionalBranch) []int {
+	var tasks []int
+	for _, task := range b.Tasks {
+		shared := false
+		for _, otherTask := range other.Tasks {
+			shared = shared || otherTask == task
+		}
+		if !shared {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// ConditionalResult records the outcome of a Conditional task, along with
+// the tasks of the branch not taken that are to be skipped.
+type ConditionalResult struct {
+	Predicate bool `json:"predicate"`
+	ConditionalBranch
+	Skip []int `json:"skip,omitempty"`
+}
+
+// Conditional evaluates a predicate, and records which branch the run should
+// take so that the job runner can skip tasks accordingly.
+type Conditional struct {
+	Predicate *TransformExpression `json:"predicate"`
+	OnTrue    *ConditionalBranch   `json:"onTrue"`
+	OnFalse   *ConditionalBranch   `json:"onFalse"`
+}
+
+// Branches returns the branches taken when the predicate is true and false.
+// By default the run continues when the predicate holds and halts otherwise.
+func (c *Conditional) Branches() (onTrue ConditionalBranch, onFalse ConditionalBranch) {
+	onTrue = ConditionalBranch{Action: ConditionalContinue}
+	onFalse = ConditionalBranch{Action: ConditionalHalt}
+	if c.OnTrue != nil {
+		onTrue = *c.OnTrue
+	}
+	if c.OnFalse != nil {
+		onFalse = *c.OnFalse
+	}
+	return onTrue, onFalse
+}
+
+// Perform evaluates the predicate, or when none is given uses the input's
+// boolean result, such as one produced by Compare. The input is passed
+// through unchanged, with the outcome recorded under "conditional".
+//
+// For example, to only submit a transaction when the price moved by more
+// than 0.5%:
+//  {"predicate": "abs(number(result) - number(latest)) / number(latest) > 0.005"}
+//
+// Or, for a conditional task followed by two tasks for each outcome and then
+// the tasks common to both:
+//  {"predicate": "ok", "onTrue": {"action": "branch", "tasks": [1, 2]},
+//   "onFalse": {"action": "branch", "tasks": [3, 4]}}
+func (c *Conditional) Perform(input models.RunResult, _ *store.Store) models.RunResult {
+	predicate, err := c.evaluate(input)
+	if err != nil {
+		return models.RunResultError(err)
+	}
+
+	onTrue, onFalse := c.Branches()
+	outcome := ConditionalResult{Predicate: predicate, ConditionalBranch: onFalse, Skip: onTrue.exclusiveTasks(onFalse)}
+	if predicate {
+		outcome.ConditionalBranch = onTrue
+		outcome.Skip = onFalse.exclusiveTasks(onTrue)
+	}
+
+	input.Add("conditional", outcome)
+	if !input.HasError() {
+		input.Status = models.RunStatusCompleted
+	}
+	return input
+}
+
+func (c *Conditional) evaluate(input models.RunResult) (bool, error) {
+	if c.Predicate == nil {
+		result := input.Result()
+		if result.Type != gjson.True && result.Type != gjson.False {
+			return false, errors.New("conditional requires a predicate or a boolean result")
+		}
+		return result.Bool(), nil
+	}
+
+	value, err := c.Predicate.root.eval(input.Data)
+	if err != nil {
+		return false, fmt.Errorf("conditional predicate: %v", err)
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("conditional predicate: expected a boolean but got %s", transformTypeName(value))
+	}
+	return b, nil
+}
@@ -0,0 +1,115 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditional_Perform(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		params      string
+		data        string
+		wantOutcome string
+	}{
+		{"boolean result true", `{}`, `{"result":true}`, `{"predicate":true,"action":"continue"}`},
+		{"boolean result false", `{}`, `{"result":false}`, `{"predicate":false,"action":"halt"}`},
+		{"predicate true", `{"predicate":"number(result) > 100"}`, `{"result":"101"}`, `{"predicate":true,"action":"continue"}`},
+		{"predicate false skips", `{"predicate":"number(result) > 100","onFalse":{"action":"skip","count":2}}`,
+			`{"result":"99"}`, `{"predicate":false,"action":"skip","count":2}`},
+		{"predicate true jumps", `{"predicate":"ok","onTrue":{"action":"jump","to":4}}`,
+			`{"ok":true}`, `{"predicate":true,"action":"jump","to":4}`},
+		{"predicate false skips other branch", `{"predicate":"ok","onTrue":{"action":"branch","tasks":[1,2,5]},"onFalse":{"action":"branch","tasks":[3,4,5]}}`,
+			`{"ok":false}`, `{"predicate":false,"action":"branch","tasks":[3,4,5],"skip":[1,2]}`},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var adapter adapters.Conditional
+			require.NoError(t, json.Unmarshal([]byte(test.params), &adapter))
+
+			input := cltest.RunResultWithData(test.data)
+			result := adapter.Perform(input, nil)
+			require.NoError(t, result.GetError())
+			assert.Equal(t, models.RunStatusCompleted, result.Status)
+			assert.JSONEq(t, test.wantOutcome, result.Get("conditional").Raw)
+			assert.Equal(t, input.Result().Raw, result.Result().Raw)
+		})
+	}
+}
+
+func TestConditional_Perform_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		params string
+		data   string
+		want   string
+	}{
+		{"non boolean result", `{}`, `{"result":"true"}`, "conditional requires a predicate or a boolean result"},
+		{"non boolean predicate", `{"predicate":"result"}`, `{"result":"1"}`, "conditional predicate: expected a boolean but got string"},
+		{"failing predicate", `{"predicate":"1 / 0 > 1"}`, `{}`, "conditional predicate: division by zero"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var adapter adapters.Conditional
+			require.NoError(t, json.Unmarshal([]byte(test.params), &adapter))
+
+			result := adapter.Perform(cltest.RunResultWithData(test.data), nil)
+			assert.Equal(t, models.RunStatusErrored, result.Status)
+			assert.Equal(t, test.want, result.Error())
+		})
+	}
+}
+
+func TestConditionalBranch_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		branch adapters.ConditionalBranch
+		want   string
+	}{
+		{"continue", adapters.ConditionalBranch{Action: adapters.ConditionalContinue}, ""},
+		{"halt", adapters.ConditionalBranch{Action: adapters.ConditionalHalt}, ""},
+		{"skip", adapters.ConditionalBranch{Action: adapters.ConditionalSkip, Count: 2}, ""},
+		{"skip too many", adapters.ConditionalBranch{Action: adapters.ConditionalSkip, Count: 3}, "conditional task 1 cannot skip 3 tasks out of 4"},
+		{"skip none", adapters.ConditionalBranch{Action: adapters.ConditionalSkip}, "conditional task 1 cannot skip 0 tasks out of 4"},
+		{"jump", adapters.ConditionalBranch{Action: adapters.ConditionalJump, To: 3}, ""},
+		{"jump backwards", adapters.ConditionalBranch{Action: adapters.ConditionalJump, To: 0}, "conditional task 1 cannot jump to task 0, it must be a later task"},
+		{"jump past the end", adapters.ConditionalBranch{Action: adapters.ConditionalJump, To: 4}, "conditional task 1 cannot jump to task 4, it must be a later task"},
+		{"branch", adapters.ConditionalBranch{Action: adapters.ConditionalBranchTasks, Tasks: []int{2, 3}}, ""},
+		{"branch without tasks", adapters.ConditionalBranch{Action: adapters.ConditionalBranchTasks}, "conditional task 1 has a branch without tasks"},
+		{"branch backwards", adapters.ConditionalBranch{Action: adapters.ConditionalBranchTasks, Tasks: []int{1}}, "conditional task 1 cannot branch to task 1, branch tasks must be later tasks in order"},
+		{"branch out of order", adapters.ConditionalBranch{Action: adapters.ConditionalBranchTasks, Tasks: []int{3, 2}}, "conditional task 1 cannot branch to task 2, branch tasks must be later tasks in order"},
+		{"branch past the end", adapters.ConditionalBranch{Action: adapters.ConditionalBranchTasks, Tasks: []int{4}}, "conditional task 1 cannot branch to task 4, branch tasks must be later tasks in order"},
+		{"tasks without branch", adapters.ConditionalBranch{Action: adapters.ConditionalJump, To: 3, Tasks: []int{3}}, "conditional action jump does not take tasks, only branch does"},
+		{"unknown", adapters.ConditionalBranch{Action: "loop"}, "conditional action loop is not one of continue, halt, skip, jump or branch"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			err := test.branch.Validate(1, 4)
+			if test.want == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,16 @@
 // Package adapters contain the core adapters used by the Chainlink node.
 //
+// Aggregate
+//
+// The Aggregate adapter runs several sub-pipelines of tasks in parallel and
+// combines their numeric results using the median, mean, trimmedmean or mode.
+// Results deviating from the median by more than maxDeviation are discarded,
+// and the task errors if fewer than minResponses results remain.
+//  { "type": "Aggregate", "params": {"method": "median", "minResponses": 2, "sources": [
+//    {"name": "bitstamp", "tasks": [{"type": "HTTPGet", "params": {"get": "https://bitstamp.net/api/ticker/"}}, {"type": "JSONParse", "params": {"path": ["last"]}}]},
+//    {"name": "coinbase", "tasks": [{"type": "HTTPGet", "params": {"get": "https://api.pro.coinbase.com/products/ETH-USD/ticker"}}, {"type": "JSONParse", "params": {"path": ["price"]}}]}
+//  ]}}
+//
 // Bridge
 //
 // The Bridge adapter is used to send and receive data to and from external adapters.
@@ -15,6 +26,16 @@
 // adapter will save `true` or `false` in the task run's result.
 //  { "type": "Compare", "params": {"operator": "eq", "value": "Hello" }}
 //
+// Conditional
+//
+// The Conditional adapter evaluates a Transform predicate, or the previous
+// task's boolean result when no predicate is given, and tells the job runner
+// which branch to take: continue, halt the run successfully, skip the next
+// count tasks, or jump to a later task by its index. The run continues when
+// the predicate holds and halts otherwise, unless onTrue or onFalse are given.
+// Tasks that are branched past are marked as skipped.
+//  { "type": "Conditional", "params": {"predicate": "number(result) > 100", "onFalse": {"action": "skip", "count": 2}}}
+//
 // HTTPGet
 //
 // The HTTPGet adapter is used to grab the JSON data from the given URL.
@@ -29,6 +50,24 @@
 //
 // The JSONParse adapter will obtain the value(s) for the given field(s).
 //  { "type": "JSONParse", "params": {"path": ["someField"] }}
+// Instead of a path, a JSONPath query can be given, with wildcards, slices,
+// filters, recursive descent and the functions length(), min(), max(), sum()
+// and avg(). Queries which can match several values return an array.
+//  { "type": "JSONParse", "params": {"query": "$.data[?(@.symbol=='ETH')].price" }}
+//
+// EthABIDecode
+//
+// The EthABIDecode adapter decodes the ABI-encoded hex bytes of the previous
+// task's result, such as a bridge result, a log's data or an eth_call output,
+// according to a list of types. Types are strings, optionally followed by a
+// name, with tuples in parentheses, or arguments of a contract's JSON ABI. A
+// single value becomes the result, several become an object by name, and
+// tuples become objects by component name.
+//   {
+//     "type": "EthABIDecode", "params": {
+//       "types": ["int256 answer", "(address oracle, uint256 updatedAt)[] reports"]
+//     }
+//   }
 //
 // EthBool
 //
@@ -54,6 +93,27 @@
 // in hex for the Ethereum blockchain.
 //  { "type": "EthUint256" }
 //
+// EthCall
+//
+// The EthCall adapter calls a contract function without sending a
+// transaction, at the latest block unless a block number or tag is given, and
+// decodes its return values according to the function ABI. Arguments are
+// encoded like those of EthTxABIEncode, and a string argument of the form
+// "{{path}}" takes the value at that path in the run's data. A single return
+// value becomes the result, several become an object by output name.
+//   {
+//     "type": "EthCall", "params": {
+//       "address": "0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+//       "functionABI": {
+//         "name": "balanceOf",
+//         "inputs": [{"name": "owner", "type": "address"}],
+//         "outputs": [{"name": "balance", "type": "uint256"}]
+//       },
+//       "args": {"owner": "{{result}}"},
+//       "block": "latest"
+//     }
+//   }
+//
 // EthTx
 //
 // The EthTx adapter will write the data to the given address and functionSelector.
@@ -83,6 +143,18 @@
 // which is similarly unverifiable and has additional possible points of failure.
 //  { "type": "Random" }
 //
+// Transform
+//
+// The Transform adapter evaluates an expression over the run's data, with
+// arbitrary precision decimal arithmetic (+ - * / %), comparisons, && || !,
+// conditionals (cond ? a : b), string functions (concat, len, lower, upper,
+// trim, contains, startsWith, endsWith, replace, substr), numeric functions
+// (abs, ceil, floor, round, pow, min, max, number, string) and timestamp
+// helpers (timestamp, formatTime, duration). Fields of the data are referenced
+// by path, e.g. prices.usd or prices[0]. Numbers are returned as strings, and
+// any number larger than 1024 bits, about 308 digits, is an error.
+//  { "type": "Transform", "params": {"expression": "round((bid + ask) / 2, 8)" }}
+//
 // EthTxABIEncode
 //
 // The EthTxABIEncode adapter serializes the contents of a json object as
@@ -0,0 +1,289 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/pkg/errors"
+
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+var (
+	abiArgumentNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	abiArraySuffixRegexp  = regexp.MustCompile(`^(\[[0-9]*\])*`)
+)
+
+// EthABIDecode decodes the ABI-encoded hex bytes of the previous task's
+// result, such as a bridge result, a log's data or the output of eth_call,
+// into JSON according to a list of ABI types.
+type EthABIDecode struct {
+	// Types of the encoded values, in order
+	Types abi.Arguments `json:"types"`
+}
+
+// UnmarshalJSON parses the types, each given either as a string such as
+// "uint256", "string label" or "(address oracle, int256 answer)[] reports",
+// or as an argument of a contract's JSON ABI, with components for tuples.
+func (ead *EthABIDecode) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Types []json.RawMessage
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fields); err != nil {
+		return err
+	}
+	if len(fields.Types) == 0 {
+		return errors.New("types must list at least one ABI type")
+	}
+
+	types := make(abi.Arguments, len(fields.Types))
+	names := map[string]bool{}
+	for i, raw := range fields.Types {
+		arg, err := unmarshalABIArgument(raw)
+		if err != nil {
+			return errors.Wrapf(err, "type %d", i)
+		}
+		name := models.ABIArgumentName(arg, i)
+		if names[name] {
+			return errors.Errorf("type %d: duplicate name %s", i, name)
+		}
+		names[name] = true
+		types[i] = arg
+	}
+	ead.Types = types
+	return nil
+}
+
+// Perform decodes the hex bytes of the previous task's result. A single value
+// becomes the result itself, several become an object by name, with unnamed
+// values named after their position, as in "arg0".
+func (ead *EthABIDecode) Perform(input models.RunResult, _ *strpkg.Store) models.RunResult {
+	result, err := ead.decode(input.Result().String())
+	if err != nil {
+		input.SetError(errors.Wrap(err, "while decoding ABI data"))
+		return input
+	}
+	input.CompleteWithResult(result)
+	return input
+}
+
+func (ead *EthABIDecode) decode(hexData string) (result interface{}, err error) {
+	data, err := hex.DecodeString(utils.RemoveHexPrefix(strings.TrimSpace(hexData)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "result %q is not a hex string", hexData)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("result has no data to decode")
+	}
+	if len(data)%evmWordSize != 0 {
+		return nil, errors.Errorf("result has %d bytes, which is not a whole number of %d byte words", len(data), evmWordSize)
+	}
+
+	// The abi package can panic on offsets and lengths pointing outside of
+	// the data
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, errors.Errorf("malformed ABI data: %v", r)
+		}
+	}()
+	values, err := ead.Types.UnpackValues(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed ABI data")
+	}
+
+	if len(values) == 1 {
+		return models.ABIValueToJSON(values[0]), nil
+	}
+	decoded := map[string]interface{}{}
+	for i, arg := range ead.Types {
+		decoded[models.ABIArgumentName(arg, i)] = models.ABIValueToJSON(values[i])
+	}
+	return decoded, nil
+}
+
+// unmarshalABIArgument parses an argument given as a string or as an
+// argument of a JSON ABI.
+func unmarshalABIArgument(raw json.RawMessage) (arg abi.Argument, err error) {
+	// The abi package panics on tuple components it can't turn into struct
+	// fields, such as duplicate names
+	defer func() {
+		if r := recover(); r != nil {
+			arg, err = abi.Argument{}, errors.Errorf("invalid ABI type: %v", r)
+		}
+	}()
+
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		var m abi.ArgumentMarshaling
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return abi.Argument{}, err
+		}
+		if err := validateABIComponentNames(m.Components); err != nil {
+			return abi.Argument{}, err
+		}
+		return newABIArgument(m)
+	}
+
+	m, err := parseABIArgument(s)
+	if err != nil {
+		return abi.Argument{}, err
+	}
+	return newABIArgument(m)
+}
+
+func newABIArgument(m abi.ArgumentMarshaling) (abi.Argument, error) {
+	typ, err := abi.NewType(m.Type, m.Components)
+	if err != nil {
+		return abi.Argument{}, err
+	}
+	if err := validateABITypeSize(&typ); err != nil {
+		return abi.Argument{}, err
+	}
+	return abi.Argument{Name: m.Name, Type: typ}, nil
+}
+
+// validateABITypeSize checks the sizes of integer and fixed bytes types,
+// which the abi package accepts as given.
+func validateABITypeSize(typ *abi.Type) error {
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		if typ.Size%8 != 0 || typ.Size <= 0 || typ.Size > 8*evmWordSize {
+			return errors.Errorf("invalid ABI type %s", typ)
+		}
+	case abi.FixedBytesTy:
+		if typ.Size <= 0 || typ.Size > evmWordSize {
+			return errors.Errorf("invalid ABI type %s", typ)
+		}
+	case abi.ArrayTy, abi.SliceTy:
+		return validateABITypeSize(typ.Elem)
+	case abi.TupleTy:
+		for _, elem := range typ.TupleElems {
+			if err := validateABITypeSize(elem); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseABIArgument parses an argument such as "uint256", "string label" or
+// "(address oracle, int256 answer)[] reports". Unnamed tuple components are
+// named after their position, as in "arg0".
+func parseABIArgument(s string) (abi.ArgumentMarshaling, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "tuple(") {
+		s = strings.TrimPrefix(s, "tuple")
+	}
+
+	var m abi.ArgumentMarshaling
+	var rest string
+	if strings.HasPrefix(s, "(") {
+		end := matchingParenthesis(s)
+		if end < 0 {
+			return m, errors.Errorf("unbalanced parentheses in %q", s)
+		}
+		components := splitTopLevel(s[1:end])
+		if len(components) == 0 {
+			return m, errors.Errorf("empty tuple in %q", s)
+		}
+		for i, component := range components {
+			c, err := parseABIArgument(component)
+			if err != nil {
+				return m, err
+			}
+			if c.Name == "" {
+				c.Name = fmt.Sprintf("arg%d", i)
+			}
+			m.Components = append(m.Components, c)
+		}
+		suffix := abiArraySuffixRegexp.FindString(s[end+1:])
+		m.Type = "tuple" + suffix
+		rest = s[end+1+len(suffix):]
+	} else {
+		fields := strings.Fields(s)
+		if len(fields) == 0 {
+			return m, errors.New("missing ABI type")
+		}
+		m.Type = fields[0]
+		rest = strings.TrimPrefix(s, fields[0])
+	}
+
+	switch name := strings.Fields(rest); len(name) {
+	case 0:
+	case 1:
+		if !abiArgumentNameRegexp.MatchString(name[0]) {
+			return m, errors.Errorf("invalid name %q", name[0])
+		}
+		m.Name = name[0]
+	default:
+		return m, errors.Errorf("invalid ABI type %q", s)
+	}
+	if rest != "" && strings.TrimLeft(rest, " \t") == rest {
+		return m, errors.Errorf("invalid ABI type %q", s)
+	}
+	return m, validateABIComponentNames(m.Components)
+}
+
+// validateABIComponentNames checks that tuple components have names which
+// can be decoded to.
+func validateABIComponentNames(components []abi.ArgumentMarshaling) error {
+	for _, c := range components {
+		if !abiArgumentNameRegexp.MatchString(c.Name) {
+			return errors.Errorf("tuple component %q must have a name starting with a letter", c.Name)
+		}
+		if err := validateABIComponentNames(c.Components); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchingParenthesis returns the index of the parenthesis closing the one
+// s starts with, or -1 if there is none.
+func matchingParenthesis(s string) int {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s at the commas outside of parentheses.
+func splitTopLevel(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,158 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthABIDecode_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{"elementary", `{"types": ["uint256", "bytes data", "string[]"]}`, false},
+		{"tuple", `{"types": ["(address oracle, (uint8, bool) flags)[2] reports"]}`, false},
+		{"tuple keyword", `{"types": ["tuple(int256 answer)"]}`, false},
+		{"json abi", `{"types": [{"name": "report", "type": "tuple[]", "components": [{"name": "oracle", "type": "address"}]}]}`, false},
+		{"no types", `{"types": []}`, true},
+		{"spurious field", `{"types": ["uint256"], "path": "result"}`, true},
+		{"unknown type", `{"types": ["float"]}`, true},
+		{"invalid size", `{"types": ["(bool, uint7[])"]}`, true},
+		{"invalid bytes size", `{"types": ["bytes33"]}`, true},
+		{"unbalanced", `{"types": ["(address,uint256"]}`, true},
+		{"empty tuple", `{"types": ["()"]}`, true},
+		{"trailing garbage", `{"types": ["(address)x"]}`, true},
+		{"invalid name", `{"types": ["uint256 1st"]}`, true},
+		{"duplicate names", `{"types": ["uint256 x", "bool x"]}`, true},
+		{"duplicate components", `{"types": ["(uint256 x, bool x)"]}`, true},
+		{"unnamed json component", `{"types": [{"type": "tuple", "components": [{"type": "address"}]}]}`, true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var ead adapters.EthABIDecode
+			err := json.Unmarshal([]byte(test.params), &ead)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEthABIDecode_Perform(t *testing.T) {
+	t.Parallel()
+
+	var ead adapters.EthABIDecode
+	require.NoError(t, json.Unmarshal([]byte(`{"types": [
+		"int256 answer",
+		"bytes",
+		"string label",
+		"uint256[] history",
+		"(address oracle, uint64 updatedAt)[] reports"
+	]}`), &ead))
+
+	answer, _ := new(big.Int).SetString("-57896044618658097711785492504343953926634992332820282019728792003956564819968", 10)
+	oracles := []common.Address{cltest.NewAddress(), cltest.NewAddress()}
+	type report struct {
+		Oracle    common.Address
+		UpdatedAt uint64
+	}
+	// Packing converts the answer to two's complement in place
+	data, err := ead.Types.Pack(
+		new(big.Int).Set(answer),
+		[]byte{0xca, 0xfe},
+		"ETH/USD",
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[]report{{oracles[0], 100}, {oracles[1], 200}},
+	)
+	require.NoError(t, err)
+
+	input := models.RunResult{
+		Data:   cltest.JSONFromString(t, `{"result": %q}`, hexutil.Encode(data)),
+		Status: models.RunStatusInProgress,
+	}
+	result := ead.Perform(input, nil)
+	require.NoError(t, result.GetError())
+	assert.Equal(t, models.RunStatusCompleted, result.Status)
+	assert.Equal(t, answer.String(), result.Get("result.answer").String())
+	assert.Equal(t, "0xcafe", result.Get("result.arg1").String())
+	assert.Equal(t, "ETH/USD", result.Get("result.label").String())
+	assert.Equal(t, `["1","2"]`, result.Get("result.history").Raw)
+	assert.Equal(t, oracles[1].Hex(), result.Get("result.reports.1.oracle").String())
+	assert.Equal(t, "200", result.Get("result.reports.1.updatedAt").String())
+}
+
+func TestEthABIDecode_Perform_SingleValue(t *testing.T) {
+	t.Parallel()
+
+	var ead adapters.EthABIDecode
+	require.NoError(t, json.Unmarshal([]byte(`{"types": ["uint256"]}`), &ead))
+
+	input := models.RunResult{
+		Data: cltest.JSONFromString(t, `{"result": "000000000000000000000000000000000000000000000000000000000000002a"}`),
+	}
+	result := ead.Perform(input, nil)
+	require.NoError(t, result.GetError())
+	assert.Equal(t, "42", result.Result().String())
+}
+
+func TestEthABIDecode_Perform_MalformedInput(t *testing.T) {
+	t.Parallel()
+
+	var ead adapters.EthABIDecode
+	require.NoError(t, json.Unmarshal([]byte(`{"types": ["uint256", "string"]}`), &ead))
+
+	word := "000000000000000000000000000000000000000000000000000000000000002a"
+	tests := []struct {
+		name   string
+		result string
+		want   string
+	}{
+		{"not hex", `"0xzz"`, "not a hex string"},
+		{"empty", `"0x"`, "no data"},
+		{"missing result", `null`, "no data"},
+		{"partial word", `"0x2a"`, "whole number"},
+		{"too short", `"0x` + word + `"`, "malformed ABI data"},
+		{"offset out of bounds", `"0x` + word + `00000000000000000000000000000000000000000000000000000000ffffffff"`, "malformed ABI data"},
+		{"length out of bounds", `"0x` + word + `0000000000000000000000000000000000000000000000000000000000000040` + `00000000000000000000000000000000000000000000000000000000ffffffff"`, "malformed ABI data"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			input := models.RunResult{Data: cltest.JSONFromString(t, `{"result": %s}`, test.result)}
+			result := ead.Perform(input, nil)
+			assert.Equal(t, models.RunStatusErrored, result.Status)
+			assert.Contains(t, result.Error(), test.want)
+		})
+	}
+}
+
+func TestAdapterFor_EthABIDecode(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	task := models.TaskSpec{}
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "EthABIDecode", "params": {"types": ["int256"]}}`), &task))
+	adapter, err := adapters.For(task, store)
+	require.NoError(t, err)
+	_, ok := adapter.BaseAdapter.(*adapters.EthABIDecode)
+	assert.True(t, ok)
+}
@@ -0,0 +1,197 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+var ethCallArgTemplateRegexp = regexp.MustCompile(`^\{\{\s*([^{}\s]+)\s*\}\}$`)
+
+// EthCall holds the Address and FunctionABI of the contract function to
+// call, without sending a transaction, and the Block to call it at.
+type EthCall struct {
+	// Ethereum address of the contract this task calls
+	Address common.Address `json:"address"`
+	// ABI of contract function this task calls, with the outputs to decode
+	FunctionABI abi.Method `json:"functionABI"`
+	// Arguments of the function by name. A string of the form "{{path}}" is
+	// replaced by the value at that path in the run's data.
+	Args map[string]interface{} `json:"args,omitempty"`
+	// Block number, or one of "latest", "pending" and "earliest". Defaults to
+	// "latest".
+	Block string `json:"block,omitempty"`
+}
+
+// UnmarshalJSON for custom JSON unmarshal that is strict, i.e. doesn't
+// accept spurious fields, in particular in the FunctionABI.
+func (ec *EthCall) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Address     common.Address
+		FunctionABI struct {
+			Name    string
+			Inputs  abi.Arguments
+			Outputs abi.Arguments
+		}
+		Args  map[string]interface{}
+		Block string
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fields); err != nil {
+		return err
+	}
+	if fields.FunctionABI.Name == "" {
+		return errors.New("functionABI must have a name")
+	}
+	if len(fields.FunctionABI.Outputs) == 0 {
+		return errors.New("functionABI must have outputs to decode")
+	}
+	if _, err := ethCallBlock(fields.Block); err != nil {
+		return err
+	}
+
+	ec.Address = fields.Address
+	ec.FunctionABI.Name = fields.FunctionABI.Name
+	ec.FunctionABI.RawName = fields.FunctionABI.Name
+	ec.FunctionABI.Const = true
+	ec.FunctionABI.Inputs = fields.FunctionABI.Inputs
+	ec.FunctionABI.Outputs = fields.FunctionABI.Outputs
+	ec.Args = fields.Args
+	ec.Block = fields.Block
+	return nil
+}
+
+// Perform calls the contract function at the block and returns its decoded
+// return values. A single return value is the result itself, several are
+// returned as an object by name.
+func (ec *EthCall) Perform(input models.RunResult, store *strpkg.Store) models.RunResult {
+	if !store.TxManager.Connected() {
+		input.MarkPendingConnection()
+		return input
+	}
+
+	result, err := ec.call(input.Data, store)
+	if err != nil {
+		input.SetError(errors.Wrapf(err, "while calling %s at %s", ec.FunctionABI.Sig(), ec.Address.Hex()))
+		return input
+	}
+	input.CompleteWithResult(result)
+	return input
+}
+
+func (ec *EthCall) call(data models.JSON, store *strpkg.Store) (interface{}, error) {
+	args, err := templateEthCallArgs(ec.Args, data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := abiEncode(&ec.FunctionABI, args)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ethCallBlock(ec.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	returned, err := store.TxManager.CallContractAt(strpkg.CallMsg{To: ec.Address, Data: encoded}, block)
+	if err != nil {
+		return nil, err
+	}
+	if len(returned) == 0 {
+		return nil, errors.New("call returned no data, the contract may not exist at that block")
+	}
+	return ec.decode(returned)
+}
+
+// decode returns the JSON representation of the values the function returned.
+func (ec *EthCall) decode(returned []byte) (interface{}, error) {
+	values, err := ec.FunctionABI.Outputs.UnpackValues(returned)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding return values")
+	}
+	if len(values) == 1 {
+		return models.ABIValueToJSON(values[0]), nil
+	}
+	outputs := map[string]interface{}{}
+	for i, output := range ec.FunctionABI.Outputs {
+		outputs[models.ABIArgumentName(output, i)] = models.ABIValueToJSON(values[i])
+	}
+	return outputs, nil
+}
+
+// templateEthCallArgs returns the arguments with each "{{path}}" string
+// replaced by the value at that path in data.
+func templateEthCallArgs(args map[string]interface{}, data models.JSON) (map[string]interface{}, error) {
+	templated := make(map[string]interface{}, len(args))
+	for name, arg := range args {
+		value, err := templateEthCallArg(arg, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %s", name)
+		}
+		templated[name] = value
+	}
+	return templated, nil
+}
+
+func templateEthCallArg(arg interface{}, data models.JSON) (interface{}, error) {
+	switch v := arg.(type) {
+	case string:
+		matches := ethCallArgTemplateRegexp.FindStringSubmatch(v)
+		if matches == nil {
+			return v, nil
+		}
+		value := data.Get(matches[1])
+		if !value.Exists() {
+			return nil, errors.Errorf("no value at %s", matches[1])
+		}
+		return value.Value(), nil
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, elem := range v {
+			value, err := templateEthCallArg(elem, data)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	default:
+		return v, nil
+	}
+}
+
+// ethCallBlock returns the block parameter of eth_call for the block given
+// as a tag or as a decimal or hex number.
+func ethCallBlock(block string) (string, error) {
+	switch block = strings.TrimSpace(block); block {
+	case "":
+		return "latest", nil
+	case "latest", "pending", "earliest":
+		return block, nil
+	}
+
+	var n *big.Int
+	var ok bool
+	if utils.HasHexPrefix(block) {
+		n, ok = new(big.Int).SetString(block[2:], 16)
+	} else {
+		n, ok = new(big.Int).SetString(block, 10)
+	}
+	if !ok || n.Sign() < 0 {
+		return "", errors.Errorf("block %q is not a block number or one of latest, pending and earliest", block)
+	}
+	return hexutil.EncodeBig(n), nil
+}
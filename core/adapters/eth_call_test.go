@@ -0,0 +1,187 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthCall_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{"valid", `{"address": "0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef", "functionABI": {"name": "balanceOf", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}]}, "args": {"owner": "{{result}}"}, "block": "0x10"}`, false},
+		{"no block", `{"functionABI": {"name": "latestAnswer", "outputs": [{"name": "", "type": "int256"}]}}`, false},
+		{"no outputs", `{"functionABI": {"name": "latestAnswer", "inputs": []}}`, true},
+		{"no name", `{"functionABI": {"outputs": [{"name": "", "type": "int256"}]}}`, true},
+		{"spurious field", `{"functionABI": {"name": "latestAnswer", "outputs": [{"name": "", "type": "int256"}], "payable": false}}`, true},
+		{"invalid block", `{"functionABI": {"name": "latestAnswer", "outputs": [{"name": "", "type": "int256"}]}, "block": "finalized"}`, true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var ec adapters.EthCall
+			err := json.Unmarshal([]byte(test.params), &ec)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEthCall_Perform(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	txm := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txm
+
+	var ec adapters.EthCall
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"address": "0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+		"functionABI": {
+			"name": "balanceOf",
+			"inputs": [{"name": "owner", "type": "address"}],
+			"outputs": [{"name": "balance", "type": "uint256"}]
+		},
+		"args": {"owner": "{{ account.address }}"},
+		"block": "16"
+	}`), &ec))
+
+	owner := cltest.NewAddress()
+	data, err := ec.FunctionABI.Inputs.Pack(owner)
+	require.NoError(t, err)
+	returned, err := ec.FunctionABI.Outputs.Pack(big.NewInt(12345))
+	require.NoError(t, err)
+
+	txm.EXPECT().Connected().Return(true)
+	txm.EXPECT().CallContractAt(strpkg.CallMsg{
+		To:   common.HexToAddress("0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"),
+		Data: append(ec.FunctionABI.ID(), data...),
+	}, "0x10").Return(returned, nil)
+
+	input := models.RunResult{
+		Data:   cltest.JSONFromString(t, `{"account": {"address": %q}}`, owner.Hex()),
+		Status: models.RunStatusInProgress,
+	}
+	result := ec.Perform(input, store)
+	require.NoError(t, result.GetError())
+	assert.Equal(t, models.RunStatusCompleted, result.Status)
+	assert.Equal(t, "12345", result.Result().String())
+}
+
+func TestEthCall_Perform_MultipleOutputs(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	txm := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txm
+
+	var ec adapters.EthCall
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"functionABI": {
+			"name": "latestRoundData",
+			"outputs": [
+				{"name": "answer", "type": "int256"},
+				{"name": "", "type": "bytes32"},
+				{"name": "oracles", "type": "address[]"}
+			]
+		}
+	}`), &ec))
+
+	oracles := []common.Address{cltest.NewAddress(), cltest.NewAddress()}
+	hash := cltest.NewHash()
+	returned, err := ec.FunctionABI.Outputs.Pack(big.NewInt(-5), [32]byte(hash), oracles)
+	require.NoError(t, err)
+
+	txm.EXPECT().Connected().Return(true)
+	txm.EXPECT().CallContractAt(gomock.Any(), "latest").Return(returned, nil)
+
+	result := ec.Perform(models.RunResult{Status: models.RunStatusInProgress}, store)
+	require.NoError(t, result.GetError())
+	assert.Equal(t, "-5", result.Get("result.answer").String())
+	assert.Equal(t, hash.Hex(), result.Get("result.arg1").String())
+	assert.Equal(t, oracles[1].Hex(), result.Get("result.oracles.1").String())
+}
+
+func TestEthCall_Perform_Errors(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	txm := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txm
+
+	var ec adapters.EthCall
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"functionABI": {
+			"name": "balanceOf",
+			"inputs": [{"name": "owner", "type": "address"}],
+			"outputs": [{"name": "", "type": "uint256"}]
+		},
+		"args": {"owner": "{{result}}"}
+	}`), &ec))
+	input := models.RunResult{
+		Data:   cltest.JSONFromString(t, `{"result": %q}`, cltest.NewAddress().Hex()),
+		Status: models.RunStatusInProgress,
+	}
+
+	txm.EXPECT().Connected().Return(false)
+	result := ec.Perform(input, store)
+	assert.Equal(t, models.RunStatusPendingConnection, result.Status)
+
+	txm.EXPECT().Connected().Return(true).AnyTimes()
+	result = ec.Perform(models.RunResult{Status: models.RunStatusInProgress}, store)
+	assert.Error(t, result.GetError(), "missing templated value")
+
+	txm.EXPECT().CallContractAt(gomock.Any(), "latest").Return(nil, errors.New("execution reverted"))
+	result = ec.Perform(input, store)
+	assert.Contains(t, result.Error(), "execution reverted")
+
+	txm.EXPECT().CallContractAt(gomock.Any(), "latest").Return([]byte{}, nil)
+	result = ec.Perform(input, store)
+	assert.Contains(t, result.Error(), "no data")
+}
+
+func TestAdapterFor_EthCall(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	task := models.TaskSpec{}
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "ethcall", "params": {"functionABI": {"name": "latestAnswer", "outputs": [{"name": "", "type": "int256"}]}}}`), &task))
+	adapter, err := adapters.For(task, store)
+	require.NoError(t, err)
+	_, ok := adapter.BaseAdapter.(*adapters.EthCall)
+	assert.True(t, ok)
+}
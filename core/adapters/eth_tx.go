@@ -24,14 +24,17 @@ const (
 )
 
 // EthTx holds the Address to send the result to and the FunctionSelector
-// to execute.
+// to execute. Setting either MaxFeePerGas or MaxPriorityFeePerGas sends an
+// EIP-1559 dynamic fee transaction within those caps.
 type EthTx struct {
-	Address          common.Address          `json:"address"`
-	FunctionSelector models.FunctionSelector `json:"functionSelector"`
-	DataPrefix       hexutil.Bytes           `json:"dataPrefix"`
-	DataFormat       string                  `json:"format"`
-	GasPrice         *models.Big             `json:"gasPrice" gorm:"type:numeric"`
-	GasLimit         uint64                  `json:"gasLimit"`
+	Address              common.Address          `json:"address"`
+	FunctionSelector     models.FunctionSelector `json:"functionSelector"`
+	DataPrefix           hexutil.Bytes           `json:"dataPrefix"`
+	DataFormat           string                  `json:"format"`
+	GasPrice             *models.Big             `json:"gasPrice" gorm:"type:numeric"`
+	GasLimit             uint64                  `json:"gasLimit"`
+	MaxFeePerGas         *models.Big             `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *models.Big             `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 // Perform creates the run result for the transaction if the existing run result
@@ -52,7 +55,8 @@ func (etx *EthTx) Perform(input models.RunResult, store *strpkg.Store) models.Ru
 			return input
 		}
 		data := utils.ConcatBytes(etx.FunctionSelector.Bytes(), etx.DataPrefix, value)
-		return createTxRunResult(etx.Address, etx.GasPrice, etx.GasLimit, data, input, store)
+		fees := txFeeParams{etx.GasPrice, etx.MaxFeePerGas, etx.MaxPriorityFeePerGas}
+		return createTxRunResult(etx.Address, fees, etx.GasLimit, data, input, store)
 	}
 	return ensureTxRunResult(input, store)
 }
@@ -76,9 +80,21 @@ func getTxData(e *EthTx, input models.RunResult) ([]byte, error) {
 	return utils.ConcatBytes(payloadOffset, output), nil
 }
 
+// txFeeParams are the fee parameters of a transaction task.
+type txFeeParams struct {
+	GasPrice             *models.Big
+	MaxFeePerGas         *models.Big
+	MaxPriorityFeePerGas *models.Big
+}
+
+// dynamic returns true if the task asks for a dynamic fee transaction.
+func (p txFeeParams) dynamic() bool {
+	return p.MaxFeePerGas != nil || p.MaxPriorityFeePerGas != nil
+}
+
 func createTxRunResult(
 	address common.Address,
-	gasPrice *models.Big,
+	fees txFeeParams,
 	gasLimit uint64,
 	data []byte,
 	input models.RunResult,
@@ -89,13 +105,26 @@ func createTxRunResult(
 		jobRunID = null.StringFrom(input.CachedJobRunID.String())
 	}
 
-	tx, err := store.TxManager.CreateTxWithGas(
-		jobRunID,
-		address,
-		data,
-		gasPrice.ToInt(),
-		gasLimit,
-	)
+	var tx *models.Tx
+	var err error
+	if fees.dynamic() {
+		tx, err = store.TxManager.CreateTxWithFees(
+			jobRunID,
+			address,
+			data,
+			fees.MaxFeePerGas.ToInt(),
+			fees.MaxPriorityFeePerGas.ToInt(),
+			gasLimit,
+		)
+	} else {
+		tx, err = store.TxManager.CreateTxWithGas(
+			jobRunID,
+			address,
+			data,
+			fees.GasPrice.ToInt(),
+			gasLimit,
+		)
+	}
 	if IsClientRetriable(err) {
 		var output models.RunResult
 		output.MarkPendingConnection()
@@ -153,7 +182,9 @@ func ensureTxRunResult(input models.RunResult, str *strpkg.Store) models.RunResu
 
 	receipt, state, err := str.TxManager.BumpGasUntilSafe(hash)
 	if err != nil {
-		if IsClientEmptyError(err) {
+		if err == strpkg.ErrTxCancelled {
+			return models.RunResultError(fmt.Errorf("Tx %v was cancelled", hash.Hex()))
+		} else if IsClientEmptyError(err) {
 			var output models.RunResult
 			output.MarkPendingConfirmations()
 			return output
@@ -214,7 +245,7 @@ func IsClientRetriable(err error) bool {
 		return false
 	}
 
-	if err, ok := err.(net.Error); ok {
+	if err, ok := errors.Cause(err).(net.Error); ok {
 		return err.Timeout() || err.Temporary()
 	} else if errors.Cause(err) == store.ErrPendingConnection {
 		return true
@@ -29,6 +29,9 @@ type EthTxABIEncode struct {
 	FunctionABI abi.Method  `json:"functionABI"`
 	GasPrice    *models.Big `json:"gasPrice" gorm:"type:numeric"`
 	GasLimit    uint64      `json:"gasLimit"`
+	// Setting either fee cap sends an EIP-1559 dynamic fee transaction
+	MaxFeePerGas         *models.Big `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *models.Big `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 // UnmarshalJSON for custom JSON unmarshal that is strict, i.e. doesn't
@@ -42,8 +45,10 @@ func (etx *EthTxABIEncode) UnmarshalJSON(data []byte) error {
 			Name   string
 			Inputs abi.Arguments
 		}
-		GasPrice *models.Big
-		GasLimit uint64
+		GasPrice             *models.Big
+		GasLimit             uint64
+		MaxFeePerGas         *models.Big
+		MaxPriorityFeePerGas *models.Big
 	}
 
 	decoder := json.NewDecoder(bytes.NewReader(data))
@@ -57,6 +62,8 @@ func (etx *EthTxABIEncode) UnmarshalJSON(data []byte) error {
 	etx.FunctionABI.Inputs = fields.FunctionABI.Inputs
 	etx.GasPrice = fields.GasPrice
 	etx.GasLimit = fields.GasLimit
+	etx.MaxFeePerGas = fields.MaxFeePerGas
+	etx.MaxPriorityFeePerGas = fields.MaxPriorityFeePerGas
 	return nil
 }
 
@@ -75,7 +82,8 @@ func (etx *EthTxABIEncode) Perform(
 			input.SetError(errors.Wrap(err, "while constructing EthTxABIEncode data"))
 			return input
 		}
-		return createTxRunResult(etx.Address, etx.GasPrice, etx.GasLimit, data, input, store)
+		fees := txFeeParams{etx.GasPrice, etx.MaxFeePerGas, etx.MaxPriorityFeePerGas}
+		return createTxRunResult(etx.Address, fees, etx.GasLimit, data, input, store)
 	}
 	return ensureTxRunResult(input, store)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/golang/mock/gomock"
 	"github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/internal/mocks"
@@ -75,6 +76,93 @@ func TestEthTxAdapter_Perform_Confirmed(t *testing.T) {
 	ethMock.EventuallyAllCalled(t)
 }
 
+func TestEthTxAdapter_Perform_SimulationReverts(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+	store := app.Store
+	store.Config.Set("ETH_TX_SIMULATION", true)
+
+	ethMock, err := app.MockStartAndConnect()
+	require.NoError(t, err)
+
+	// Error(string) encoding of "Must have a valid requestId"
+	revert := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000001b" +
+		"4d757374206861766520612076616c6964207265717565737449640000000000")
+	ethMock.Register("eth_call", hexutil.Bytes(revert))
+
+	adapter := adapters.EthTx{
+		Address:          cltest.NewAddress(),
+		FunctionSelector: models.HexToFunctionSelector("b3f98adc"),
+	}
+	output := adapter.Perform(cltest.RunResultWithResult("0x9786856756"), store)
+
+	assert.Equal(t, models.RunStatusErrored, output.Status)
+	assert.Equal(t, "Transaction reverted in simulation: Must have a valid requestId", output.Error())
+
+	from := cltest.GetAccountAddress(t, store)
+	txs, err := store.TxFrom(from)
+	require.NoError(t, err)
+	assert.Len(t, txs, 0)
+	ethMock.EventuallyAllCalled(t)
+}
+
+func TestEthTxAdapter_Perform_SimulationTimeoutTreatsAsNotConnected(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		callErr     error
+		estimateErr error
+	}{
+		{"eth_call times out", syscall.ETIMEDOUT, nil},
+		{"eth_estimateGas times out", nil, syscall.ETIMEDOUT},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app, cleanup := cltest.NewApplicationWithKey(t)
+			defer cleanup()
+			store := app.Store
+			store.Config.Set("ETH_TX_SIMULATION", true)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			eth := mocks.NewMockEthClient(ctrl)
+			txm := strpkg.NewEthTxManager(eth, store.Config, store.KeyStore, store.ORM)
+			txm.Register(store.KeyStore.Accounts())
+			eth.EXPECT().GetNonce(gomock.Any()).Return(uint64(0), nil)
+			require.NoError(t, txm.Connect(cltest.Head(1)))
+			store.TxManager = txm
+
+			eth.EXPECT().CallContract(gomock.Any()).Return([]byte{}, test.callErr)
+			if test.estimateErr != nil {
+				eth.EXPECT().EstimateGas(gomock.Any()).Return(uint64(0), test.estimateErr)
+			}
+
+			adapter := adapters.EthTx{
+				Address:          cltest.NewAddress(),
+				FunctionSelector: models.HexToFunctionSelector("b3f98adc"),
+			}
+			output := adapter.Perform(cltest.RunResultWithResult("0x9786856756"), store)
+
+			assert.NoError(t, output.GetError())
+			assert.Equal(t, models.RunStatusPendingConnection, output.Status)
+
+			txs, err := store.TxFrom(cltest.GetAccountAddress(t, store))
+			require.NoError(t, err)
+			assert.Len(t, txs, 0)
+		})
+	}
+}
+
 func TestEthTxAdapter_Perform_ConfirmedWithBytes(t *testing.T) {
 	t.Parallel()
 
@@ -556,6 +644,44 @@ func TestEthTxAdapter_Perform_CustomGas(t *testing.T) {
 	assert.False(t, result.HasError())
 }
 
+func TestEthTxAdapter_Perform_DynamicFees(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txmMock := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txmMock
+	txmMock.EXPECT().Connected().Return(true)
+	txmMock.EXPECT().CreateTxWithFees(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		big.NewInt(300),
+		gomock.Nil(),
+		uint64(0),
+	).Return(&models.Tx{
+		Attempts: []*models.TxAttempt{&models.TxAttempt{}},
+	}, nil)
+	txmMock.EXPECT().CheckAttempt(gomock.Any(), gomock.Any()).Return(&models.TxReceipt{}, strpkg.Unconfirmed, nil)
+
+	var adapter adapters.EthTx
+	params := `{"address": "0x0000000000000000000000000000000000000001", "functionSelector": "0xb3f98adc", "maxFeePerGas": 300}`
+	require.NoError(t, json.Unmarshal([]byte(params), &adapter))
+
+	input := models.RunResult{
+		Data:   cltest.JSONFromString(t, `{"result": "hello world"}`),
+		Status: models.RunStatusInProgress,
+	}
+
+	result := adapter.Perform(input, store)
+	assert.False(t, result.HasError())
+	assert.Equal(t, models.RunStatusPendingConfirmations, result.Status)
+}
+
 func TestEthTxAdapter_Perform_NotConnected(t *testing.T) {
 	t.Parallel()
 
@@ -678,6 +804,29 @@ func TestEthTxAdapter_Perform_CreateTxWithEmptyResponseErrorTreatsAsPendingConfi
 	assert.Equal(t, models.RunStatusPendingConfirmations, input.Status)
 }
 
+func TestEthTxAdapter_Perform_CancelledTx(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txmMock := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txmMock
+	hash := cltest.NewHash()
+	txmMock.EXPECT().Connected().Return(true)
+	txmMock.EXPECT().BumpGasUntilSafe(hash).Return(&models.TxReceipt{}, strpkg.Safe, strpkg.ErrTxCancelled)
+
+	input := cltest.RunResultWithResult(hash.Hex())
+	input.Status = models.RunStatusPendingConfirmations
+	output := (&adapters.EthTx{}).Perform(input, store)
+
+	assert.Equal(t, models.RunStatusErrored, output.Status)
+	assert.Equal(t, "Tx "+hash.Hex()+" was cancelled", output.Error())
+}
+
 func TestEthTxAdapter_Perform_NoDoubleSpendOnSendTransactionFail(t *testing.T) {
 	t.Parallel()
 
@@ -836,6 +985,7 @@ func TestEthTxAdapter_IsClientRetriable(t *testing.T) {
 		{"syscall.ECONNABORTED", syscall.ECONNABORTED, false},
 		{"syscall.EWOULDBLOCK", syscall.EWOULDBLOCK, true},
 		{"syscall.ETIMEDOUT", syscall.ETIMEDOUT, true},
+		{"wrapped syscall.ETIMEDOUT", pkgerrors.Wrap(syscall.ETIMEDOUT, "TxManager#simulateTx CallContract"), true},
 	}
 
 	for _, tt := range tests {
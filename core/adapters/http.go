@@ -3,7 +3,6 @@ package adapters
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -152,7 +151,10 @@ func sendRequest(input models.RunResult, request *http.Request, limit int64) mod
 
 	responseBody := string(bytes)
 	if response.StatusCode >= 400 {
-		return models.RunResultError(errors.New(responseBody))
+		return models.RunResultError(models.HTTPResponseError{
+			StatusCode: response.StatusCode,
+			Body:       responseBody,
+		})
 	}
 
 	return models.RunResultComplete(responseBody)
@@ -3,6 +3,7 @@ package adapters
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -13,9 +14,50 @@ import (
 )
 
 // JSONParse holds a path to the desired field in a JSON object,
-// made up of an array of strings.
+// made up of an array of strings, or a JSONPath query.
 type JSONParse struct {
-	Path JSONPath `json:"path"`
+	Path  JSONPath       `json:"path"`
+	Query *JSONPathQuery `json:"query"`
+}
+
+// UnmarshalJSON reads either the path, an array of keys or a string of dot
+// delimited keys, or the JSONPath query, such as
+// "$.data[?(@.symbol=='ETH')].price".
+func (jpa *JSONParse) UnmarshalJSON(b []byte) error {
+	var fields struct {
+		Path  *JSONPath
+		Query *string
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	if fields.Query == nil {
+		var path JSONPath
+		if fields.Path != nil {
+			path = *fields.Path
+		}
+		*jpa = JSONParse{Path: path}
+		return nil
+	}
+
+	if fields.Path != nil {
+		return errors.New("JSONParse takes either a path or a query, not both")
+	}
+	query, err := ParseJSONPathQuery(*fields.Query)
+	if err != nil {
+		return fmt.Errorf("JSONParse query: %v", err)
+	}
+	*jpa = JSONParse{Query: query}
+	return nil
+}
+
+// MarshalJSON returns the path, or the query if there is one.
+func (jpa JSONParse) MarshalJSON() ([]byte, error) {
+	if jpa.Query != nil {
+		return json.Marshal(map[string]interface{}{"query": jpa.Query})
+	}
+	return json.Marshal(map[string]interface{}{"path": jpa.Path})
 }
 
 // Perform returns the value associated to the desired field for a
@@ -29,13 +71,23 @@ type JSONParse struct {
 //     ]
 //   }
 //
-// Then ["0","last"] would be the path, and "111" would be the returned value
+// Then ["0","last"] would be the path, and "111" would be the returned value.
+// So would the query "$.data[0].last", while "$.data[*].last" would return
+// ["1111","2222"].
 func (jpa *JSONParse) Perform(input models.RunResult, _ *store.Store) models.RunResult {
 	val, err := input.ResultString()
 	if err != nil {
 		return models.RunResultError(err)
 	}
 
+	if jpa.Query != nil {
+		result, err := jpa.Query.Evaluate(val)
+		if err != nil {
+			return models.RunResultError(err)
+		}
+		return models.RunResultComplete(result)
+	}
+
 	js, err := simplejson.NewJson([]byte(val))
 	if err != nil {
 		return models.RunResultError(err)
@@ -8,6 +8,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJsonParse_Perform(t *testing.T) {
@@ -30,6 +31,8 @@ func TestJsonParse_Perform(t *testing.T) {
 			`{"result":"0.99991"}`, models.RunStatusCompleted, false},
 		{"float result", `{"availability":0.99991}`, []string{"availability"},
 			`{"result":0.99991}`, models.RunStatusCompleted, false},
+		{"key starting with $", `{"$price":"11779.99","price":"1"}`, []string{"$price"},
+			`{"result":"11779.99"}`, models.RunStatusCompleted, false},
 		{
 			"index array",
 			`{"data": [0, 1]}`,
@@ -157,3 +160,118 @@ func TestJSON_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestJsonParse_Perform_Query(t *testing.T) {
+	t.Parallel()
+
+	const tickers = `{"data":[
+		{"symbol":"BTC","price":"11779.99","volume":10},
+		{"symbol":"ETH","price":"185.20","volume":250},
+		{"symbol":"LINK","price":"2.51","volume":1000,"tags":{"oracle":true}}
+	]}`
+	tests := []struct {
+		name            string
+		result          string
+		query           string
+		wantData        string
+		wantResultError bool
+	}{
+		{"child", `{"last":"11779.99"}`, `$.last`, `{"result":"11779.99"}`, false},
+		{"bracket child", `{"a b":{"c":1}}`, `$['a b'].c`, `{"result":1}`, false},
+		{"index", tickers, `$.data[1].symbol`, `{"result":"ETH"}`, false},
+		{"negative index", tickers, `$.data[-1].symbol`, `{"result":"LINK"}`, false},
+		{"dot index", tickers, `$.data.0.symbol`, `{"result":"BTC"}`, false},
+		{"nonexistent", tickers, `$.data[7].symbol`, `{"result":null}`, false},
+		{"large number", `{"n":115792089237316195423570985008687907853269984665640564039457584007913129639935}`, `$.n`,
+			`{"result":115792089237316195423570985008687907853269984665640564039457584007913129639935}`, false},
+		{"wildcard", tickers, `$.data[*].symbol`, `{"result":["BTC","ETH","LINK"]}`, false},
+		{"object wildcard", `{"a":1,"b":{"c":2},"d":3}`, `$.*`, `{"result":[1,{"c":2},3]}`, false},
+		{"slice", tickers, `$.data[:2].symbol`, `{"result":["BTC","ETH"]}`, false},
+		{"reverse slice", `[0,1,2,3,4]`, `$[::-2]`, `{"result":[4,2,0]}`, false},
+		{"union", tickers, `$.data[0,2].symbol`, `{"result":["BTC","LINK"]}`, false},
+		{"filter", tickers, `$.data[?(@.symbol=='ETH')].price`, `{"result":["185.20"]}`, false},
+		{"filter comparing numbers", tickers, `$.data[?(@.volume >= 250 && @.symbol != "LINK")].symbol`, `{"result":["ETH"]}`, false},
+		{"filter by existence", tickers, `$.data[?(@.tags.oracle)].symbol`, `{"result":["LINK"]}`, false},
+		{"filter negated", tickers, `$.data[?(!(@.volume < 100 || @.tags))].symbol`, `{"result":["ETH"]}`, false},
+		{"filter against root", `{"min":5,"values":[3,5,8]}`, `$.values[?(@ >= $.min)]`, `{"result":[5,8]}`, false},
+		{"filter no match", tickers, `$.data[?(@.symbol=='DOGE')].price`, `{"result":[]}`, false},
+		{"recursive descent", `{"a":{"price":1,"b":[{"price":2}]},"price":3}`, `$..price`, `{"result":[3,1,2]}`, false},
+		{"length", tickers, `$.data.length()`, `{"result":3}`, false},
+		{"length of matches", tickers, `$.data[?(@.volume > 100)].length()`, `{"result":2}`, false},
+		{"sum", tickers, `$.data[*].volume.sum()`, `{"result":1260}`, false},
+		{"min of strings", tickers, `$.data[*].price.min()`, `{"result":2.51}`, false},
+		{"max", tickers, `$.data[*].price.max()`, `{"result":11779.99}`, false},
+		{"avg", `{"values":[1,2,2]}`, `$.values.avg()`, `{"result":1.666666666666666667}`, false},
+		{"function on non-number", tickers, `$.data[*].symbol.sum()`, ``, true},
+		{"function on nothing", tickers, `$.missing.max()`, ``, true},
+		{"invalid JSON", `{"data":`, `$.data`, ``, true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			var adapter adapters.JSONParse
+			params, err := json.Marshal(map[string]string{"query": test.query})
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(params, &adapter))
+
+			input := models.RunResult{}
+			input.Add("result", test.result)
+			result := adapter.Perform(input, nil)
+			if test.wantResultError {
+				assert.Error(t, result.GetError())
+				assert.Equal(t, models.RunStatusErrored, result.Status)
+				return
+			}
+			require.NoError(t, result.GetError())
+			assert.Equal(t, models.RunStatusCompleted, result.Status)
+			assert.JSONEq(t, test.wantData, result.Data.String())
+		})
+	}
+}
+
+func TestJSONParse_UnmarshalJSON_Query(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		input     string
+		wantPath  []string
+		wantQuery string
+		wantError bool
+	}{
+		{"query", `{"query":"$.data[?(@.symbol=='ETH')].price"}`, nil, "$.data[?(@.symbol=='ETH')].price", false},
+		{"path array starting with $", `{"path":["$.data"]}`, []string{"$.data"}, "", false},
+		{"path string starting with $", `{"path":"$price"}`, []string{"$price"}, "", false},
+		{"path string", `{"path":"data.0"}`, []string{"data", "0"}, "", false},
+		{"path and query", `{"path":"data","query":"$.data"}`, nil, "", true},
+		{"query not starting with $", `{"query":"data"}`, nil, "", true},
+		{"unknown function", `{"query":"$.data.median()"}`, nil, "", true},
+		{"unclosed bracket", `{"query":"$.data[0"}`, nil, "", true},
+		{"unclosed filter", `{"query":"$.data[?(@.a == 1]"}`, nil, "", true},
+		{"comparing many values", `{"query":"$.data[?(@.a[*] == 1)]"}`, nil, "", true},
+		{"trailing garbage", `{"query":"$.data)"}`, nil, "", true},
+		{"segment after function", `{"query":"$.data.length().x"}`, nil, "", true},
+	}
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			var a adapters.JSONParse
+			err := json.Unmarshal([]byte(test.input), &a)
+			cltest.AssertError(t, test.wantError, err)
+			if test.wantError {
+				return
+			}
+			assert.Equal(t, test.wantPath, []string(a.Path))
+			if test.wantQuery == "" {
+				assert.Nil(t, a.Query)
+			} else {
+				require.NotNil(t, a.Query)
+				assert.Equal(t, test.wantQuery, a.Query.String())
+
+				b, err := json.Marshal(a)
+				require.NoError(t, err)
+				assert.JSONEq(t, test.input, string(b))
+			}
+		})
+	}
+}
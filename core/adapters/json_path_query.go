@@ -0,0 +1,883 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// jsonPathMaxLength is the longest query accepted by a JSONParse.
+	jsonPathMaxLength = 4096
+	// jsonPathMaxDepth is the deepest nesting of filters and parentheses.
+	jsonPathMaxDepth = 64
+)
+
+// JSONPathQuery is a JSONPath query evaluated by the JSONParse adapter,
+// parsed when the task is loaded so that syntax errors surface early.
+//
+// Queries start at the root with "$" and support child names (.name and
+// ['name']), wildcards (* and [*]), array indexes and slices ([0], [-1],
+// [1:3], [::2]), unions ([0,2] and ['a','b']), recursive descent (..name),
+// filters ([?(@.symbol == 'ETH' && @.price > 100)]) and, at the end of the
+// query, one of the functions length(), min(), max(), sum() and avg().
+type JSONPathQuery struct {
+	source string
+	path   *jsonPathExpr
+}
+
+// ParseJSONPathQuery parses a query such as "$.data[?(@.symbol=='ETH')].price".
+func ParseJSONPathQuery(source string) (*JSONPathQuery, error) {
+	if len(source) > jsonPathMaxLength {
+		return nil, fmt.Errorf("query is longer than %d characters", jsonPathMaxLength)
+	}
+	p := &jsonPathParser{input: strings.TrimSpace(source)}
+	if p.peek() != '$' {
+		return nil, errors.New("query must start with '$'")
+	}
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return &JSONPathQuery{source: source, path: path}, nil
+}
+
+// MarshalJSON returns the query as a JSON string.
+func (q JSONPathQuery) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.source)
+}
+
+// String returns the source of the query.
+func (q JSONPathQuery) String() string {
+	return q.source
+}
+
+// Evaluate returns the JSON the query selects in the document. A query that
+// can only match a single value, such as "$.data[0].price", returns it, or
+// null if it doesn't exist. Other queries return an array of all matches, in
+// document order.
+func (q *JSONPathQuery) Evaluate(document string) (json.RawMessage, error) {
+	document = strings.TrimSpace(document)
+	if !gjson.Valid(document) {
+		return nil, errors.New("result is not valid JSON")
+	}
+	root := gjson.Parse(document)
+	nodes := q.path.selectNodes(root, root)
+
+	if q.path.function != "" {
+		value, err := applyJSONPathFunction(q.path.function, q.path.definite(), nodes)
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(formatTransformNumber(value)), nil
+	}
+	if q.path.definite() {
+		if len(nodes) == 0 {
+			return json.RawMessage("null"), nil
+		}
+		return json.RawMessage(nodes[0].Raw), nil
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('[')
+	for i, node := range nodes {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteString(node.Raw)
+	}
+	buffer.WriteByte(']')
+	return json.RawMessage(buffer.Bytes()), nil
+}
+
+// jsonPathExpr is a path from the root ($) or, in filters, from the current
+// node (@), optionally followed by a function.
+type jsonPathExpr struct {
+	relative bool
+	segments []jsonPathSegment
+	function string
+}
+
+type jsonPathSegment struct {
+	recursive bool
+	selectors []jsonPathSelector
+}
+
+// definite returns true if the path can match at most one value.
+func (e *jsonPathExpr) definite() bool {
+	for _, segment := range e.segments {
+		if segment.recursive || len(segment.selectors) != 1 {
+			return false
+		}
+		switch segment.selectors[0].(type) {
+		case jsonPathName, jsonPathIndex:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (e *jsonPathExpr) selectNodes(root, current gjson.Result) []gjson.Result {
+	start := root
+	if e.relative {
+		start = current
+	}
+	nodes := []gjson.Result{start}
+	for _, segment := range e.segments {
+		var next []gjson.Result
+		for _, node := range nodes {
+			candidates := []gjson.Result{node}
+			if segment.recursive {
+				candidates = jsonPathDescendants(node, nil)
+			}
+			for _, candidate := range candidates {
+				for _, selector := range segment.selectors {
+					next = selector.selectFrom(candidate, root, next)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// jsonPathDescendants appends the node and all of its descendants.
+func jsonPathDescendants(node gjson.Result, out []gjson.Result) []gjson.Result {
+	out = append(out, node)
+	if node.IsArray() || node.IsObject() {
+		node.ForEach(func(_, child gjson.Result) bool {
+			out = jsonPathDescendants(child, out)
+			return true
+		})
+	}
+	return out
+}
+
+type jsonPathSelector interface {
+	selectFrom(node, root gjson.Result, out []gjson.Result) []gjson.Result
+}
+
+// jsonPathName selects the member of an object with the name. Integer names
+// also index arrays, as in the legacy dot delimited paths.
+type jsonPathName string
+
+func (s jsonPathName) selectFrom(node, root gjson.Result, out []gjson.Result) []gjson.Result {
+	if node.IsArray() {
+		if i, err := strconv.Atoi(string(s)); err == nil {
+			return jsonPathIndex(i).selectFrom(node, root, out)
+		}
+		return out
+	}
+	if !node.IsObject() {
+		return out
+	}
+	node.ForEach(func(key, value gjson.Result) bool {
+		if key.String() == string(s) {
+			out = append(out, value)
+			return false
+		}
+		return true
+	})
+	return out
+}
+
+// jsonPathWildcard selects all members of an object or elements of an array.
+type jsonPathWildcard struct{}
+
+func (jsonPathWildcard) selectFrom(node, _ gjson.Result, out []gjson.Result) []gjson.Result {
+	if node.IsArray() || node.IsObject() {
+		node.ForEach(func(_, value gjson.Result) bool {
+			out = append(out, value)
+			return true
+		})
+	}
+	return out
+}
+
+// jsonPathIndex selects an element of an array, counting from the end if it
+// is negative.
+type jsonPathIndex int
+
+func (s jsonPathIndex) selectFrom(node, _ gjson.Result, out []gjson.Result) []gjson.Result {
+	if !node.IsArray() {
+		return out
+	}
+	elements := node.Array()
+	i := int(s)
+	if i < 0 {
+		i += len(elements)
+	}
+	if i < 0 || i >= len(elements) {
+		return out
+	}
+	return append(out, elements[i])
+}
+
+// jsonPathSlice selects the elements of an array from start up to, but not
+// including, end, every step elements.
+type jsonPathSlice struct {
+	start, end *int
+	step       int
+}
+
+func (s jsonPathSlice) selectFrom(node, _ gjson.Result, out []gjson.Result) []gjson.Result {
+	if !node.IsArray() || s.step == 0 {
+		return out
+	}
+	elements := node.Array()
+	length := len(elements)
+	bound := func(i *int, def int) int {
+		if i == nil {
+			return def
+		}
+		n := *i
+		if n < 0 {
+			n += length
+		}
+		if s.step > 0 {
+			return clampInt(n, 0, length)
+		}
+		return clampInt(n, -1, length-1)
+	}
+
+	if s.step > 0 {
+		for i := bound(s.start, 0); i < bound(s.end, length); i += s.step {
+			out = append(out, elements[i])
+		}
+	} else {
+		for i := bound(s.start, length-1); i > bound(s.end, -1); i += s.step {
+			out = append(out, elements[i])
+		}
+	}
+	return out
+}
+
+func clampInt(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// jsonPathFilter selects the members of an object or elements of an array
+// for which the condition holds.
+type jsonPathFilter struct {
+	condition jsonPathCondition
+}
+
+func (s jsonPathFilter) selectFrom(node, root gjson.Result, out []gjson.Result) []gjson.Result {
+	if node.IsArray() || node.IsObject() {
+		node.ForEach(func(_, value gjson.Result) bool {
+			if s.condition.test(root, value) {
+				out = append(out, value)
+			}
+			return true
+		})
+	}
+	return out
+}
+
+// jsonPathCondition is a boolean expression of a filter.
+type jsonPathCondition interface {
+	test(root, current gjson.Result) bool
+}
+
+// jsonPathOperand is a value compared in a filter. Values are one of
+// *big.Rat, string, bool, jsonPathNull, gjson.Result for arrays and objects,
+// or nil when a path matches nothing.
+type jsonPathOperand interface {
+	value(root, current gjson.Result) interface{}
+}
+
+type jsonPathNull struct{}
+
+type jsonPathOr struct{ left, right jsonPathCondition }
+
+func (c jsonPathOr) test(root, current gjson.Result) bool {
+	return c.left.test(root, current) || c.right.test(root, current)
+}
+
+type jsonPathAnd struct{ left, right jsonPathCondition }
+
+func (c jsonPathAnd) test(root, current gjson.Result) bool {
+	return c.left.test(root, current) && c.right.test(root, current)
+}
+
+type jsonPathNot struct{ condition jsonPathCondition }
+
+func (c jsonPathNot) test(root, current gjson.Result) bool {
+	return !c.condition.test(root, current)
+}
+
+type jsonPathComparison struct {
+	op          string
+	left, right jsonPathOperand
+}
+
+func (c jsonPathComparison) test(root, current gjson.Result) bool {
+	left, right := c.left.value(root, current), c.right.value(root, current)
+	switch c.op {
+	case "==":
+		return jsonPathEqual(left, right)
+	case "!=":
+		return !jsonPathEqual(left, right)
+	}
+
+	var cmp int
+	switch l := left.(type) {
+	case *big.Rat:
+		r, ok := right.(*big.Rat)
+		if !ok {
+			return false
+		}
+		cmp = l.Cmp(r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return false
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func jsonPathEqual(left, right interface{}) bool {
+	switch l := left.(type) {
+	case *big.Rat:
+		r, ok := right.(*big.Rat)
+		return ok && l.Cmp(r) == 0
+	case gjson.Result:
+		r, ok := right.(gjson.Result)
+		return ok && l.Raw == r.Raw
+	default:
+		return left == right
+	}
+}
+
+// jsonPathLiteral is a number, string, boolean or null in a filter.
+type jsonPathLiteral struct {
+	v interface{}
+}
+
+func (l jsonPathLiteral) value(gjson.Result, gjson.Result) interface{} {
+	return l.v
+}
+
+func (l jsonPathLiteral) test(gjson.Result, gjson.Result) bool {
+	return l.v == true
+}
+
+// jsonPathOperandPath is a path in a filter. On its own, it tests that the
+// path matches something.
+type jsonPathOperandPath struct {
+	path *jsonPathExpr
+}
+
+func (o jsonPathOperandPath) value(root, current gjson.Result) interface{} {
+	nodes := o.path.selectNodes(root, current)
+	if o.path.function != "" {
+		value, err := applyJSONPathFunction(o.path.function, o.path.definite(), nodes)
+		if err != nil {
+			return nil
+		}
+		return value
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return jsonPathValue(nodes[0])
+}
+
+func (o jsonPathOperandPath) test(root, current gjson.Result) bool {
+	return o.value(root, current) != nil
+}
+
+// jsonPathValue converts a JSON value to a value that can be compared.
+func jsonPathValue(node gjson.Result) interface{} {
+	switch node.Type {
+	case gjson.Number:
+		if r, ok := new(big.Rat).SetString(node.Raw); ok {
+			return r
+		}
+		return node.Raw
+	case gjson.String:
+		return node.Str
+	case gjson.True:
+		return true
+	case gjson.False:
+		return false
+	case gjson.Null:
+		return jsonPathNull{}
+	default:
+		return node
+	}
+}
+
+// applyJSONPathFunction applies the function to the elements of the array a
+// definite path matched, or to all the values an indefinite path matched.
+func applyJSONPathFunction(name string, definite bool, nodes []gjson.Result) (*big.Rat, error) {
+	items := nodes
+	if definite {
+		if len(nodes) == 0 {
+			return nil, fmt.Errorf("no value to apply %s() to", name)
+		}
+		node := nodes[0]
+		switch {
+		case node.IsArray():
+			items = node.Array()
+		case name == "length" && node.IsObject():
+			return big.NewRat(int64(len(node.Map())), 1), nil
+		case name == "length" && node.Type == gjson.String:
+			return big.NewRat(int64(utf8.RuneCountInString(node.Str)), 1), nil
+		default:
+			return nil, fmt.Errorf("%s() requires an array but got %s", name, node.Raw)
+		}
+	}
+	if name == "length" {
+		return big.NewRat(int64(len(items)), 1), nil
+	}
+
+	numbers := make([]*big.Rat, len(items))
+	for i, item := range items {
+		n, ok := jsonPathNumber(item)
+		if !ok {
+			return nil, fmt.Errorf("%s() requires numbers but got %s", name, item.Raw)
+		}
+		numbers[i] = n
+	}
+	if len(numbers) == 0 && name != "sum" {
+		return nil, fmt.Errorf("%s() requires at least one number", name)
+	}
+
+	result := new(big.Rat)
+	switch name {
+	case "min", "max":
+		result.Set(numbers[0])
+		for _, n := range numbers[1:] {
+			if (name == "min") == (n.Cmp(result) < 0) {
+				result.Set(n)
+			}
+		}
+	case "sum", "avg":
+		for _, n := range numbers {
+			result.Add(result, n)
+		}
+		if name == "avg" {
+			result.Quo(result, big.NewRat(int64(len(numbers)), 1))
+		}
+	}
+	return result, nil
+}
+
+// jsonPathNumber returns the number in a JSON number or numeric string.
+func jsonPathNumber(node gjson.Result) (*big.Rat, bool) {
+	switch node.Type {
+	case gjson.Number:
+		return new(big.Rat).SetString(node.Raw)
+	case gjson.String:
+		if strings.Contains(node.Str, "/") {
+			return nil, false
+		}
+		return new(big.Rat).SetString(strings.TrimSpace(node.Str))
+	default:
+		return nil, false
+	}
+}
+
+var jsonPathFunctions = map[string]bool{
+	"length": true,
+	"min":    true,
+	"max":    true,
+	"sum":    true,
+	"avg":    true,
+}
+
+type jsonPathParser struct {
+	input string
+	pos   int
+	depth int
+}
+
+func (p *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *jsonPathParser) accept(s string) bool {
+	if strings.HasPrefix(p.input[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *jsonPathParser) enter() error {
+	p.depth++
+	if p.depth > jsonPathMaxDepth {
+		return p.errorf("query is nested deeper than %d levels", jsonPathMaxDepth)
+	}
+	return nil
+}
+
+func (p *jsonPathParser) leave() {
+	p.depth--
+}
+
+func (p *jsonPathParser) parsePath() (*jsonPathExpr, error) {
+	expr := &jsonPathExpr{}
+	switch p.peek() {
+	case '$':
+	case '@':
+		expr.relative = true
+	default:
+		return nil, p.errorf("expected '$' or '@'")
+	}
+	p.pos++
+
+	for {
+		var segment jsonPathSegment
+		switch {
+		case p.accept(".."):
+			segment.recursive = true
+			switch {
+			case p.accept("*"):
+				segment.selectors = []jsonPathSelector{jsonPathWildcard{}}
+			case p.peek() == '[':
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = selectors
+			default:
+				name := p.parseName()
+				if name == "" {
+					return nil, p.errorf("expected a name after '..'")
+				}
+				segment.selectors = []jsonPathSelector{jsonPathName(name)}
+			}
+		case p.accept("."):
+			if p.accept("*") {
+				segment.selectors = []jsonPathSelector{jsonPathWildcard{}}
+				break
+			}
+			name := p.parseName()
+			if name == "" {
+				return nil, p.errorf("expected a name after '.'")
+			}
+			if p.accept("(") {
+				if !jsonPathFunctions[name] {
+					return nil, p.errorf("unknown function %s()", name)
+				}
+				p.skipSpace()
+				if !p.accept(")") {
+					return nil, p.errorf("%s() takes no arguments", name)
+				}
+				// Functions end the path
+				expr.function = name
+				return expr, nil
+			}
+			segment.selectors = []jsonPathSelector{jsonPathName(name)}
+		case p.peek() == '[':
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = selectors
+		default:
+			return expr, nil
+		}
+		expr.segments = append(expr.segments, segment)
+	}
+}
+
+// parseName reads a name in dot notation, which can't contain the characters
+// used by the rest of the syntax.
+func (p *jsonPathParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+		if r != '_' && r != '-' && r != '$' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		p.pos += size
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *jsonPathParser) parseBracket() ([]jsonPathSelector, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	p.pos++ // [
+	var selectors []jsonPathSelector
+	for {
+		p.skipSpace()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.skipSpace()
+		switch {
+		case p.accept(","):
+		case p.accept("]"):
+			return selectors, nil
+		default:
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return jsonPathWildcard{}, nil
+	case c == '\'' || c == '"':
+		name, length, err := lexTransformString(p.input[p.pos:], c)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		p.pos += length
+		return jsonPathName(name), nil
+	case c == '?':
+		p.pos++
+		condition, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathFilter{condition}, nil
+	case c == '-' || c == ':' || isDigit(c):
+		return p.parseIndexOrSlice()
+	default:
+		return nil, p.errorf("expected a name, index, slice, '*' or filter")
+	}
+}
+
+func (p *jsonPathParser) parseIndexOrSlice() (jsonPathSelector, error) {
+	var bounds [3]*int
+	for i := range bounds {
+		p.skipSpace()
+		if p.peek() == '-' || isDigit(p.peek()) {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[i] = &n
+		}
+		p.skipSpace()
+		if i == 0 && p.peek() != ':' {
+			if bounds[0] == nil {
+				return nil, p.errorf("expected an index")
+			}
+			return jsonPathIndex(*bounds[0]), nil
+		}
+		if i == 2 || !p.accept(":") {
+			break
+		}
+	}
+
+	slice := jsonPathSlice{start: bounds[0], end: bounds[1], step: 1}
+	if bounds[2] != nil {
+		slice.step = *bounds[2]
+	}
+	return slice, nil
+}
+
+func (p *jsonPathParser) parseInt() (int, error) {
+	start := p.pos
+	p.accept("-")
+	for isDigit(p.peek()) {
+		p.pos++
+	}
+	text := p.input[start:p.pos]
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid index %q", text)
+	}
+	return n, nil
+}
+
+func (p *jsonPathParser) parseOr() (jsonPathCondition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.accept("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = jsonPathOr{left, right}
+	}
+}
+
+func (p *jsonPathParser) parseAnd() (jsonPathCondition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.accept("&&") {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = jsonPathAnd{left, right}
+	}
+}
+
+func (p *jsonPathParser) parseNot() (jsonPathCondition, error) {
+	p.skipSpace()
+	if p.peek() == '!' && !strings.HasPrefix(p.input[p.pos:], "!=") {
+		p.pos++
+		condition, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathNot{condition}, nil
+	}
+	return p.parseComparison()
+}
+
+var jsonPathComparisonOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *jsonPathParser) parseComparison() (jsonPathCondition, error) {
+	p.skipSpace()
+	if p.accept("(") {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		condition, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.accept(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return condition, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	op := ""
+	for _, candidate := range jsonPathComparisonOperators {
+		if p.accept(candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		if condition, ok := left.(jsonPathCondition); ok {
+			return condition, nil
+		}
+		return nil, p.errorf("expected a comparison")
+	}
+
+	p.skipSpace()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, operand := range []jsonPathOperand{left, right} {
+		if path, ok := operand.(jsonPathOperandPath); ok && path.path.function == "" && !path.path.definite() {
+			return nil, p.errorf("only paths matching a single value can be compared")
+		}
+	}
+	return jsonPathComparison{op, left, right}, nil
+}
+
+func (p *jsonPathParser) parseOperand() (jsonPathOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathOperandPath{path}, nil
+	case c == '\'' || c == '"':
+		s, length, err := lexTransformString(p.input[p.pos:], c)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		p.pos += length
+		return jsonPathLiteral{s}, nil
+	case c == '-' || isDigit(c):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.input) && strings.IndexByte("0123456789.eE+-", p.input[p.pos]) >= 0 {
+			p.pos++
+		}
+		n, ok := new(big.Rat).SetString(p.input[start:p.pos])
+		if !ok {
+			p.pos = start
+			return nil, p.errorf("invalid number")
+		}
+		return jsonPathLiteral{n}, nil
+	case p.accept("true"):
+		return jsonPathLiteral{true}, nil
+	case p.accept("false"):
+		return jsonPathLiteral{false}, nil
+	case p.accept("null"):
+		return jsonPathLiteral{jsonPathNull{}}, nil
+	default:
+		return nil, p.errorf("expected a path, string, number, boolean or null")
+	}
+}
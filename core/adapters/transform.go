@@ -0,0 +1,68 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// TransformExpression is an expression evaluated by the Transform adapter,
+// parsed when the task is loaded so that syntax errors surface early.
+type TransformExpression struct {
+	source string
+	root   transformNode
+}
+
+// UnmarshalJSON parses the expression from a JSON string.
+func (e *TransformExpression) UnmarshalJSON(input []byte) error {
+	var source string
+	if err := json.Unmarshal(input, &source); err != nil {
+		return fmt.Errorf("transform expression: %v", err)
+	}
+	root, err := parseTransformExpression(source)
+	if err != nil {
+		return fmt.Errorf("transform expression: %v", err)
+	}
+	*e = TransformExpression{source: source, root: root}
+	return nil
+}
+
+// MarshalJSON returns the expression as a JSON string.
+func (e TransformExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.source)
+}
+
+// String returns the source of the expression.
+func (e TransformExpression) String() string {
+	return e.source
+}
+
+// Transform evaluates an expression over the run's data.
+type Transform struct {
+	Expression *TransformExpression `json:"expression"`
+}
+
+// Perform evaluates the adapter's expression against the input's data and
+// returns its value as the result.
+//
+// Numbers are decimals of arbitrary precision, and are returned as strings
+// so that no precision is lost before formatting with the eth* adapters. For
+// example, with the input data {"a": "1.5", "b": 2.25}, the expression
+// "round((number(a) + b) / 2, 1)" results in "1.9".
+func (t *Transform) Perform(input models.RunResult, _ *store.Store) models.RunResult {
+	if t.Expression == nil {
+		return models.RunResultError(errors.New("transform requires an expression"))
+	}
+	value, err := t.Expression.root.eval(input.Data)
+	if err != nil {
+		return models.RunResultError(fmt.Errorf("transform: %v", err))
+	}
+	if r, ok := value.(*big.Rat); ok {
+		return models.RunResultComplete(formatTransformNumber(r))
+	}
+	return models.RunResultComplete(value)
+}
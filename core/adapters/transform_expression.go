@@ -0,0 +1,961 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/araddon/dateparse"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// transformMaxLength is the longest expression accepted by a Transform.
+	transformMaxLength = 4096
+	// transformMaxDepth is the deepest nesting of sub-expressions accepted.
+	transformMaxDepth = 64
+	// transformMaxExponent bounds pow() so that results stay a reasonable size.
+	transformMaxExponent = 256
+	// transformMaxBits bounds the numerator and denominator of every number
+	// read or computed, about 308 decimal digits, so that an expression
+	// cannot build arbitrarily large values.
+	transformMaxBits = 1024
+	// transformMaxPlaces bounds the decimal places of round().
+	transformMaxPlaces = 78
+	// transformRepeatingPlaces is the number of decimal places a number is
+	// rendered with when it has no finite decimal representation, e.g. 1/3.
+	transformRepeatingPlaces = 18
+)
+
+// transformNode is a parsed sub-expression. Evaluating a node yields one of
+// *big.Rat, string, bool or nil.
+type transformNode interface {
+	eval(data models.JSON) (interface{}, error)
+}
+
+// parseTransformExpression parses an expression into a tree that can be
+// evaluated any number of times.
+func parseTransformExpression(input string) (transformNode, error) {
+	if len(input) > transformMaxLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", transformMaxLength)
+	}
+	tokens, err := lexTransformExpression(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &transformParser{tokens: tokens}
+	node, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+	}
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type transformToken struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t transformToken) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("'%s'", t.value)
+	}
+}
+
+var transformOperators = []string{
+	"&&", "||", "==", "!=", "<=", ">=",
+	"+", "-", "*", "/", "%", "<", ">", "!", "?", ":", "(", ")", "[", "]", ",", ".",
+}
+
+func lexTransformExpression(input string) ([]transformToken, error) {
+	tokens := []transformToken{}
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9':
+			start := i
+			for i < len(input) && isDigit(input[i]) {
+				i++
+			}
+			if i+1 < len(input) && input[i] == '.' && isDigit(input[i+1]) {
+				i++
+				for i < len(input) && isDigit(input[i]) {
+					i++
+				}
+			}
+			if i < len(input) && (input[i] == 'e' || input[i] == 'E') {
+				i++
+				if i < len(input) && (input[i] == '+' || input[i] == '-') {
+					i++
+				}
+				for i < len(input) && isDigit(input[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, transformToken{tokenNumber, input[start:i], start})
+		case r == '"' || r == '\'':
+			start := i
+			value, length, err := lexTransformString(input[i:], byte(r))
+			if err != nil {
+				return nil, fmt.Errorf("%v at position %d", err, start)
+			}
+			i += length
+			tokens = append(tokens, transformToken{tokenString, value, start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(input) {
+				r, size = utf8.DecodeRuneInString(input[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, transformToken{tokenIdent, input[start:i], start})
+		default:
+			op := ""
+			for _, candidate := range transformOperators {
+				if strings.HasPrefix(input[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, transformToken{tokenOperator, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, transformToken{tokenEOF, "", len(input)}), nil
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// lexTransformString reads a quoted string, returning its unescaped value and
+// the number of bytes consumed including the quotes.
+func lexTransformString(input string, quote byte) (string, int, error) {
+	var sb strings.Builder
+	for i := 1; i < len(input); i++ {
+		switch c := input[i]; c {
+		case quote:
+			return sb.String(), i + 1, nil
+		case '\\':
+			i++
+			if i >= len(input) {
+				return "", 0, errors.New("unterminated string")
+			}
+			switch input[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(input[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+type transformParser struct {
+	tokens []transformToken
+	pos    int
+	depth  int
+}
+
+func (p *transformParser) peek() transformToken {
+	return p.tokens[p.pos]
+}
+
+func (p *transformParser) next() transformToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *transformParser) acceptOperator(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.value == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *transformParser) expectOperator(op string) error {
+	if _, ok := p.acceptOperator(op); !ok {
+		tok := p.peek()
+		return fmt.Errorf("expected '%s' but found %s at position %d", op, tok, tok.pos)
+	}
+	return nil
+}
+
+func (p *transformParser) parseExpression() (transformNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > transformMaxDepth {
+		return nil, fmt.Errorf("expression is nested deeper than %d levels", transformMaxDepth)
+	}
+
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.acceptOperator("?"); !ok {
+		return cond, nil
+	}
+	then, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectOperator(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return &conditionalNode{cond, then, otherwise}, nil
+}
+
+// transformPrecedence lists the binary operators from loosest to tightest
+// binding.
+var transformPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *transformParser) parseBinary(level int) (transformNode, error) {
+	if level == len(transformPrecedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator(transformPrecedence[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op, left, right}
+	}
+}
+
+func (p *transformParser) parseUnary() (transformNode, error) {
+	if op, ok := p.acceptOperator("-", "!"); ok {
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > transformMaxDepth {
+			return nil, fmt.Errorf("expression is nested deeper than %d levels", transformMaxDepth)
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op, operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *transformParser) parsePrimary() (transformNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		r, ok := new(big.Rat).SetString(tok.value)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %s at position %d", tok.value, tok.pos)
+		}
+		if err := checkTransformNumber(r); err != nil {
+			return nil, fmt.Errorf("%v at position %d", err, tok.pos)
+		}
+		return &literalNode{r}, nil
+	case tokenString:
+		return &literalNode{tok.value}, nil
+	case tokenIdent:
+		switch tok.value {
+		case "true":
+			return &literalNode{true}, nil
+		case "false":
+			return &literalNode{false}, nil
+		case "null":
+			return &literalNode{nil}, nil
+		}
+		if _, ok := p.acceptOperator("("); ok {
+			return p.parseCall(tok)
+		}
+		return p.parsePath(tok)
+	case tokenOperator:
+		if tok.value == "(" {
+			node, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expectOperator(")")
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+}
+
+func (p *transformParser) parseCall(name transformToken) (transformNode, error) {
+	fn, ok := transformFunctions[name.value]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s at position %d", name.value, name.pos)
+	}
+	args := []transformNode{}
+	if _, ok := p.acceptOperator(")"); !ok {
+		for {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.acceptOperator(")"); ok {
+				break
+			}
+			if err := p.expectOperator(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments to %s at position %d", name.value, name.pos)
+	}
+	return &callNode{name.value, fn.call, args}, nil
+}
+
+// parsePath reads a reference to the data, such as prices.usd or
+// prices[0], into a gjson path.
+func (p *transformParser) parsePath(first transformToken) (transformNode, error) {
+	segments := []string{first.value}
+	for {
+		if _, ok := p.acceptOperator("."); ok {
+			// a.0.1 lexes as a, ., 0.1 so numbers may hold several indexes
+			tok := p.next()
+			if tok.kind != tokenIdent && !isIndexPath(tok) {
+				return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+			}
+			segments = append(segments, tok.value)
+		} else if _, ok := p.acceptOperator("["); ok {
+			tok := p.next()
+			if tok.kind == tokenString {
+				segments = append(segments, escapeGJSONKey(tok.value))
+			} else if _, err := strconv.ParseUint(tok.value, 10, 32); tok.kind == tokenNumber && err == nil {
+				segments = append(segments, tok.value)
+			} else {
+				return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+			}
+			if err := p.expectOperator("]"); err != nil {
+				return nil, err
+			}
+		} else {
+			return &pathNode{strings.Join(segments, ".")}, nil
+		}
+	}
+}
+
+func isIndexPath(tok transformToken) bool {
+	if tok.kind != tokenNumber {
+		return false
+	}
+	for _, index := range strings.Split(tok.value, ".") {
+		if _, err := strconv.ParseUint(index, 10, 32); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func escapeGJSONKey(key string) string {
+	var sb strings.Builder
+	for _, r := range key {
+		if r == '.' || r == '*' || r == '?' || r == '\\' || r == '|' || r == '#' || r == '@' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(models.JSON) (interface{}, error) {
+	return n.value, nil
+}
+
+type pathNode struct {
+	path string
+}
+
+func (n *pathNode) eval(data models.JSON) (interface{}, error) {
+	return fromGJSON(n.path, data.Get(n.path))
+}
+
+func fromGJSON(path string, value gjson.Result) (interface{}, error) {
+	switch value.Type {
+	case gjson.Null:
+		return nil, nil
+	case gjson.False:
+		return false, nil
+	case gjson.True:
+		return true, nil
+	case gjson.String:
+		return value.Str, nil
+	case gjson.Number:
+		r, ok := new(big.Rat).SetString(value.Raw)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a valid number", path)
+		}
+		if err := checkTransformNumber(r); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return r, nil
+	default:
+		return nil, fmt.Errorf("%s is an object or array, not a value", path)
+	}
+}
+
+type conditionalNode struct {
+	cond, then, otherwise transformNode
+}
+
+func (n *conditionalNode) eval(data models.JSON) (interface{}, error) {
+	cond, err := evalBool(n.cond, data)
+	if err != nil {
+		return nil, err
+	}
+	if cond {
+		return n.then.eval(data)
+	}
+	return n.otherwise.eval(data)
+}
+
+type unaryNode struct {
+	op      string
+	operand transformNode
+}
+
+func (n *unaryNode) eval(data models.JSON) (interface{}, error) {
+	if n.op == "!" {
+		value, err := evalBool(n.operand, data)
+		return !value, err
+	}
+	value, err := evalNumber(n.operand, data)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).Neg(value), nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right transformNode
+}
+
+func (n *binaryNode) eval(data models.JSON) (interface{}, error) {
+	switch n.op {
+	case "&&", "||":
+		left, err := evalBool(n.left, data)
+		if err != nil || left == (n.op == "||") {
+			return left, err
+		}
+		return evalBool(n.right, data)
+	}
+
+	left, err := n.left.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return transformEqual(left, right), nil
+	case "!=":
+		return !transformEqual(left, right), nil
+	}
+
+	if ls, ok := left.(string); ok {
+		rs, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot apply %s to %s and %s", n.op, transformTypeName(left), transformTypeName(right))
+		}
+		switch n.op {
+		case "+":
+			return ls + rs, nil
+		case "<":
+			return ls < rs, nil
+		case "<=":
+			return ls <= rs, nil
+		case ">":
+			return ls > rs, nil
+		case ">=":
+			return ls >= rs, nil
+		}
+	}
+
+	lr, lok := left.(*big.Rat)
+	rr, rok := right.(*big.Rat)
+	if !lok || !rok {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", n.op, transformTypeName(left), transformTypeName(right))
+	}
+	switch n.op {
+	case "+":
+		return checkedTransformNumber(new(big.Rat).Add(lr, rr))
+	case "-":
+		return checkedTransformNumber(new(big.Rat).Sub(lr, rr))
+	case "*":
+		return checkedTransformNumber(new(big.Rat).Mul(lr, rr))
+	case "/":
+		if rr.Sign() == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return checkedTransformNumber(new(big.Rat).Quo(lr, rr))
+	case "%":
+		if rr.Sign() == 0 {
+			return nil, errors.New("division by zero")
+		}
+		quotient := truncateRat(new(big.Rat).Quo(lr, rr))
+		return checkedTransformNumber(new(big.Rat).Sub(lr, quotient.Mul(quotient, rr)))
+	case "<":
+		return lr.Cmp(rr) < 0, nil
+	case "<=":
+		return lr.Cmp(rr) <= 0, nil
+	case ">":
+		return lr.Cmp(rr) > 0, nil
+	default:
+		return lr.Cmp(rr) >= 0, nil
+	}
+}
+
+type callNode struct {
+	name string
+	call func([]interface{}) (interface{}, error)
+	args []transformNode
+}
+
+func (n *callNode) eval(data models.JSON) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(data)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	value, err := n.call(args)
+	if err == nil {
+		if r, ok := value.(*big.Rat); ok {
+			err = checkTransformNumber(r)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", n.name, err)
+	}
+	return value, nil
+}
+
+func evalBool(node transformNode, data models.JSON) (bool, error) {
+	value, err := node.eval(data)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean but got %s", transformTypeName(value))
+	}
+	return b, nil
+}
+
+func evalNumber(node transformNode, data models.JSON) (*big.Rat, error) {
+	value, err := node.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := value.(*big.Rat)
+	if !ok {
+		return nil, fmt.Errorf("expected a number but got %s", transformTypeName(value))
+	}
+	return r, nil
+}
+
+// checkTransformNumber rejects numbers whose numerator or denominator is
+// larger than transformMaxBits.
+func checkTransformNumber(r *big.Rat) error {
+	if r.Num().BitLen() > transformMaxBits || r.Denom().BitLen() > transformMaxBits {
+		return fmt.Errorf("number exceeds %d bits", transformMaxBits)
+	}
+	return nil
+}
+
+func checkedTransformNumber(r *big.Rat) (interface{}, error) {
+	if err := checkTransformNumber(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func transformEqual(left, right interface{}) bool {
+	lr, lok := left.(*big.Rat)
+	rr, rok := right.(*big.Rat)
+	if lok && rok {
+		return lr.Cmp(rr) == 0
+	}
+	return left == right
+}
+
+func transformTypeName(value interface{}) string {
+	switch value.(type) {
+	case *big.Rat:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// formatTransformNumber renders a number as a plain decimal string, exactly
+// when it has a finite decimal representation.
+func formatTransformNumber(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	denom := new(big.Int).Set(r.Denom())
+	places := 0
+	for _, factor := range []int64{2, 5} {
+		count := 0
+		f := big.NewInt(factor)
+		mod := new(big.Int)
+		for {
+			quotient, m := new(big.Int).QuoRem(denom, f, mod)
+			if m.Sign() != 0 {
+				break
+			}
+			denom = quotient
+			count++
+		}
+		if count > places {
+			places = count
+		}
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		s := r.FloatString(transformRepeatingPlaces)
+		return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return r.FloatString(places)
+}
+
+func formatTransformValue(value interface{}) string {
+	switch v := value.(type) {
+	case *big.Rat:
+		return formatTransformNumber(v)
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+func truncateRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Quo(r.Num(), r.Denom()))
+}
+
+func floorRat(r *big.Rat) *big.Rat {
+	t := truncateRat(r)
+	if r.Sign() < 0 && t.Cmp(r) != 0 {
+		t.Sub(t, big.NewRat(1, 1))
+	}
+	return t
+}
+
+// roundRat rounds half away from zero to the given number of decimal places.
+func roundRat(r *big.Rat, places int64) *big.Rat {
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(places), nil))
+	scaled := new(big.Rat).Mul(r, scale)
+	half := big.NewRat(1, 2)
+	if scaled.Sign() < 0 {
+		scaled.Sub(scaled, half)
+	} else {
+		scaled.Add(scaled, half)
+	}
+	rounded := truncateRat(scaled)
+	return rounded.Quo(rounded, scale)
+}
+
+type transformFunction struct {
+	minArgs, maxArgs int
+	call             func([]interface{}) (interface{}, error)
+}
+
+var transformFunctions map[string]transformFunction
+
+func init() {
+	transformFunctions = map[string]transformFunction{
+		"abs": {1, 1, numbers(func(n []*big.Rat) (interface{}, error) {
+			return new(big.Rat).Abs(n[0]), nil
+		})},
+		"ceil": {1, 1, numbers(func(n []*big.Rat) (interface{}, error) {
+			return new(big.Rat).Neg(floorRat(new(big.Rat).Neg(n[0]))), nil
+		})},
+		"floor": {1, 1, numbers(func(n []*big.Rat) (interface{}, error) {
+			return floorRat(n[0]), nil
+		})},
+		"round": {1, 2, numbers(func(n []*big.Rat) (interface{}, error) {
+			places := int64(0)
+			if len(n) == 2 {
+				if !n[1].IsInt() || n[1].Sign() < 0 || n[1].Num().Int64() > transformMaxPlaces {
+					return nil, fmt.Errorf("places must be an integer from 0 to %d", transformMaxPlaces)
+				}
+				places = n[1].Num().Int64()
+			}
+			return roundRat(n[0], places), nil
+		})},
+		"pow": {2, 2, numbers(func(n []*big.Rat) (interface{}, error) {
+			if !n[1].IsInt() || n[1].Num().CmpAbs(big.NewInt(transformMaxExponent)) > 0 {
+				return nil, fmt.Errorf("exponent must be an integer from -%d to %d", transformMaxExponent, transformMaxExponent)
+			}
+			exp := n[1].Num()
+			if exp.Sign() < 0 && n[0].Sign() == 0 {
+				return nil, errors.New("division by zero")
+			}
+			abs := new(big.Int).Abs(exp)
+			bits := n[0].Num().BitLen()
+			if n[0].Denom().BitLen() > bits {
+				bits = n[0].Denom().BitLen()
+			}
+			if (bits-1)*int(abs.Int64()) > transformMaxBits {
+				return nil, fmt.Errorf("number exceeds %d bits", transformMaxBits)
+			}
+			num := new(big.Int).Exp(n[0].Num(), abs, nil)
+			denom := new(big.Int).Exp(n[0].Denom(), abs, nil)
+			if exp.Sign() < 0 {
+				num, denom = denom, num
+			}
+			return new(big.Rat).SetFrac(num, denom), nil
+		})},
+		"min": {1, -1, numbers(func(n []*big.Rat) (interface{}, error) {
+			min := n[0]
+			for _, r := range n[1:] {
+				if r.Cmp(min) < 0 {
+					min = r
+				}
+			}
+			return min, nil
+		})},
+		"max": {1, -1, numbers(func(n []*big.Rat) (interface{}, error) {
+			max := n[0]
+			for _, r := range n[1:] {
+				if r.Cmp(max) > 0 {
+					max = r
+				}
+			}
+			return max, nil
+		})},
+		"number": {1, 1, func(args []interface{}) (interface{}, error) {
+			switch v := args[0].(type) {
+			case *big.Rat:
+				return v, nil
+			case string:
+				r, ok := new(big.Rat).SetString(strings.TrimSpace(v))
+				if !ok {
+					return nil, fmt.Errorf("cannot parse %q as a number", v)
+				}
+				return r, nil
+			default:
+				return nil, fmt.Errorf("cannot convert %s to a number", transformTypeName(v))
+			}
+		}},
+		"string": {1, 1, func(args []interface{}) (interface{}, error) {
+			return formatTransformValue(args[0]), nil
+		}},
+		"concat": {0, -1, func(args []interface{}) (interface{}, error) {
+			var sb strings.Builder
+			for _, arg := range args {
+				sb.WriteString(formatTransformValue(arg))
+			}
+			return sb.String(), nil
+		}},
+		"len": {1, 1, strs(func(s []string) (interface{}, error) {
+			return big.NewRat(int64(utf8.RuneCountInString(s[0])), 1), nil
+		})},
+		"lower": {1, 1, strs(func(s []string) (interface{}, error) {
+			return strings.ToLower(s[0]), nil
+		})},
+		"upper": {1, 1, strs(func(s []string) (interface{}, error) {
+			return strings.ToUpper(s[0]), nil
+		})},
+		"trim": {1, 1, strs(func(s []string) (interface{}, error) {
+			return strings.TrimSpace(s[0]), nil
+		})},
+		"contains": {2, 2, strs(func(s []string) (interface{}, error) {
+			return strings.Contains(s[0], s[1]), nil
+		})},
+		"startsWith": {2, 2, strs(func(s []string) (interface{}, error) {
+			return strings.HasPrefix(s[0], s[1]), nil
+		})},
+		"endsWith": {2, 2, strs(func(s []string) (interface{}, error) {
+			return strings.HasSuffix(s[0], s[1]), nil
+		})},
+		"replace": {3, 3, strs(func(s []string) (interface{}, error) {
+			return strings.Replace(s[0], s[1], s[2], -1), nil
+		})},
+		"substr": {2, 3, substr},
+		"if": {3, 3, func(args []interface{}) (interface{}, error) {
+			cond, ok := args[0].(bool)
+			if !ok {
+				return nil, fmt.Errorf("expected a boolean but got %s", transformTypeName(args[0]))
+			}
+			if cond {
+				return args[1], nil
+			}
+			return args[2], nil
+		}},
+		"timestamp": {1, 1, strs(func(s []string) (interface{}, error) {
+			t, err := dateparse.ParseIn(s[0], time.UTC)
+			if err != nil {
+				return nil, err
+			}
+			return big.NewRat(t.UnixNano(), int64(time.Second)), nil
+		})},
+		"formatTime": {1, 2, formatTime},
+		"duration": {1, 1, strs(func(s []string) (interface{}, error) {
+			d, err := time.ParseDuration(s[0])
+			if err != nil {
+				return nil, err
+			}
+			return big.NewRat(int64(d), int64(time.Second)), nil
+		})},
+	}
+}
+
+func numbers(fn func([]*big.Rat) (interface{}, error)) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		n := make([]*big.Rat, len(args))
+		for i, arg := range args {
+			r, ok := arg.(*big.Rat)
+			if !ok {
+				return nil, fmt.Errorf("expected a number but got %s", transformTypeName(arg))
+			}
+			n[i] = r
+		}
+		return fn(n)
+	}
+}
+
+func strs(fn func([]string) (interface{}, error)) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		s := make([]string, len(args))
+		for i, arg := range args {
+			str, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string but got %s", transformTypeName(arg))
+			}
+			s[i] = str
+		}
+		return fn(s)
+	}
+}
+
+// substr returns the characters of a string from start, to the end or up to
+// the given length.
+func substr(args []interface{}) (interface{}, error) {
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string but got %s", transformTypeName(args[0]))
+	}
+	runes := []rune(s)
+	bounds := []int{0, len(runes)}
+	for i, arg := range args[1:] {
+		r, ok := arg.(*big.Rat)
+		if !ok || !r.IsInt() || r.Sign() < 0 {
+			return nil, errors.New("start and length must be non-negative integers")
+		}
+		if r.Num().Cmp(big.NewInt(int64(len(runes)))) > 0 {
+			bounds[i] = len(runes)
+		} else {
+			bounds[i] = int(r.Num().Int64())
+		}
+	}
+	end := len(runes)
+	if len(args) == 3 && bounds[0]+bounds[1] < end {
+		end = bounds[0] + bounds[1]
+	}
+	return string(runes[bounds[0]:end]), nil
+}
+
+// formatTime renders seconds since the Unix epoch in UTC, using the given Go
+// time layout or RFC3339 by default.
+func formatTime(args []interface{}) (interface{}, error) {
+	seconds, ok := args[0].(*big.Rat)
+	if !ok {
+		return nil, fmt.Errorf("expected a number but got %s", transformTypeName(args[0]))
+	}
+	layout := time.RFC3339
+	if len(args) == 2 {
+		if layout, ok = args[1].(string); !ok {
+			return nil, fmt.Errorf("expected a string but got %s", transformTypeName(args[1]))
+		}
+	}
+	nanos := new(big.Rat).Mul(seconds, big.NewRat(int64(time.Second), 1))
+	n := truncateRat(nanos).Num()
+	if !n.IsInt64() {
+		return nil, errors.New("timestamp out of range")
+	}
+	return time.Unix(0, n.Int64()).UTC().Format(layout), nil
+}
@@ -0,0 +1,165 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform_Perform(t *testing.T) {
+	t.Parallel()
+
+	data := `{
+		"a": 1.5, "b": "2.25", "big": "123456789012345678901234567890.123456789",
+		"name": " Ether ", "ok": true, "nothing": null,
+		"prices": [{"usd": "100.1"}, {"usd": "99.9"}],
+		"time": "2019-10-31T12:00:00Z",
+		"result": "42"
+	}`
+
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+	}{
+		{"number literal", `1.25`, `"1.25"`},
+		{"addition", `a + number(b)`, `"3.75"`},
+		{"precedence", `1 + 2 * 3 - 4 / 2`, `"5"`},
+		{"parentheses", `(1 + 2) * 3`, `"9"`},
+		{"unary minus", `-a * 2`, `"-3"`},
+		{"average rounded", `round((a + number(b)) / 2, 8)`, `"1.875"`},
+		{"round half away from zero", `round(-2.5)`, `"-3"`},
+		{"repeating decimal", `1 / 3`, `"0.333333333333333333"`},
+		{"exact big decimal", `number(big) * 10`, `"1234567890123456789012345678901.23456789"`},
+		{"modulo", `7 % 3`, `"1"`},
+		{"floor and ceil", `floor(-1.5) + ceil(1.2)`, `"0"`},
+		{"pow", `pow(10, 18) * 1.5`, `"1500000000000000000"`},
+		{"negative pow", `pow(2, -2)`, `"0.25"`},
+		{"min and max", `max(1, 5, 3) - min(4, 2)`, `"3"`},
+		{"previous result", `number(result) + 1`, `"43"`},
+		{"array index", `prices[1].usd`, `"99.9"`},
+		{"dotted index", `number(prices.0.usd)`, `"100.1"`},
+		{"quoted key", `prices[0]["usd"]`, `"100.1"`},
+		{"comparison", `a < number(b)`, `true`},
+		{"equality across types", `a == "1.5"`, `false`},
+		{"logical", `ok && !(a > 2) || false`, `true`},
+		{"short circuit", `false && undefined > 1`, `false`},
+		{"conditional", `a > 1 ? "high" : "low"`, `"high"`},
+		{"if function", `if(ok, 1, 2)`, `"1"`},
+		{"null", `nothing`, `null`},
+		{"missing", `missing == null`, `true`},
+		{"string concatenation", `upper(trim(name)) + "/USD"`, `"ETHER/USD"`},
+		{"concat", `concat("a", 1.50, true)`, `"a1.5true"`},
+		{"string functions", `replace(substr(lower(name), 1, 3), "e", "E")`, `"Eth"`},
+		{"contains", `contains(name, "the") && startsWith(name, " ") && endsWith(name, " ")`, `true`},
+		{"len", `len(name)`, `"7"`},
+		{"timestamp", `timestamp(time)`, `"1572523200"`},
+		{"formatTime", `formatTime(timestamp(time) + duration("1h30m"))`, `"2019-10-31T13:30:00Z"`},
+		{"formatTime layout", `formatTime(0, "2006-01-02")`, `"1970-01-01"`},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var adapter adapters.Transform
+			params, err := json.Marshal(map[string]string{"expression": test.expression})
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(params, &adapter))
+
+			result := adapter.Perform(cltest.RunResultWithData(data), nil)
+			require.NoError(t, result.GetError())
+			assert.Equal(t, models.RunStatusCompleted, result.Status)
+			assert.JSONEq(t, test.want, result.Result().Raw)
+		})
+	}
+}
+
+func TestTransform_Perform_Errors(t *testing.T) {
+	t.Parallel()
+
+	data := `{"a": 1, "s": "x", "obj": {"b": 1}, "huge": 1e400, "large": "1e300"}`
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+	}{
+		{"division by zero", `a / 0`, "transform: division by zero"},
+		{"mixed types", `a + s`, "transform: cannot apply + to number and string"},
+		{"condition not boolean", `a ? 1 : 2`, "transform: expected a boolean but got number"},
+		{"object reference", `obj`, "transform: obj is an object or array, not a value"},
+		{"bad number", `number(s)`, `transform: number: cannot parse "x" as a number`},
+		{"pow too large", `pow(2, 1000)`, "transform: pow: exponent must be an integer from -256 to 256"},
+		{"pow of huge base", `pow(number("1e1000000"), 256)`, "transform: number: number exceeds 1024 bits"},
+		{"pow result too large", `pow(number(large), 256)`, "transform: pow: number exceeds 1024 bits"},
+		{"product too large", `number(large) * number(large) * number(large) * number(large)`, "transform: number exceeds 1024 bits"},
+		{"quotient too large", `1 / number(large) / number(large) / number(large) / number(large)`, "transform: number exceeds 1024 bits"},
+		{"huge run data", `huge + 1`, "transform: huge: number exceeds 1024 bits"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var adapter adapters.Transform
+			params, err := json.Marshal(map[string]string{"expression": test.expression})
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(params, &adapter))
+
+			result := adapter.Perform(cltest.RunResultWithData(data), nil)
+			assert.Equal(t, models.RunStatusErrored, result.Status)
+			assert.Equal(t, test.want, result.Error())
+		})
+	}
+}
+
+func TestTransform_ParseErrors(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+	}{
+		{"empty", ``, "transform expression: unexpected end of expression at position 0"},
+		{"unbalanced", `(1 + 2`, "transform expression: expected ')' but found end of expression at position 6"},
+		{"unknown function", `exec("rm")`, "transform expression: unknown function exec at position 0"},
+		{"wrong arity", `round()`, "transform expression: wrong number of arguments to round at position 0"},
+		{"bad character", `a $ b`, "transform expression: unexpected character '$' at position 2"},
+		{"unterminated string", `"abc`, "transform expression: unterminated string at position 0"},
+		{"trailing tokens", `1 2`, "transform expression: unexpected '2' at position 2"},
+		{"huge literal", `1 + 1e400`, "transform expression: number exceeds 1024 bits at position 4"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			params, err := json.Marshal(map[string]string{"expression": test.expression})
+			require.NoError(t, err)
+			task := models.TaskSpec{Type: adapters.TaskTypeTransform, Params: cltest.JSONFromString(t, string(params))}
+			_, err = adapters.For(task, store)
+			assert.EqualError(t, err, test.want)
+		})
+	}
+}
+
+func TestTransform_FeedsEthFormatting(t *testing.T) {
+	t.Parallel()
+
+	var adapter adapters.Transform
+	require.NoError(t, json.Unmarshal([]byte(`{"expression":"round(number(price) * pow(10, 8))"}`), &adapter))
+
+	result := adapter.Perform(cltest.RunResultWithData(`{"price":"180.123456789"}`), nil)
+	require.NoError(t, result.GetError())
+
+	result = (&adapters.EthUint256{}).Perform(result, nil)
+	require.NoError(t, result.GetError())
+	assert.Equal(t, "0x00000000000000000000000000000000000000000000000000000004319e954f", result.Result().String())
+}
@@ -3,19 +3,198 @@
 package adapters
 
 import (
+	"encoding/base64"
 	"fmt"
+	"math"
+	"strconv"
 
+	"github.com/go-interpreter/wagon/wasm"
+	"github.com/perlin-network/life/compiler"
+	"github.com/perlin-network/life/exec"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/tidwall/gjson"
 )
 
-// Wasm represents a wasm binary encoded as base64 or wasm encoded as text (a lisp like language).
+const (
+	// DefaultWasmGasLimit is the maximum number of instructions a wasm
+	// program may execute when the task does not specify a gas limit.
+	DefaultWasmGasLimit = 1000000
+	// DefaultWasmMaxMemoryPages is the maximum number of 64KiB pages of linear
+	// memory a wasm program may use when the task does not specify a limit.
+	DefaultWasmMaxMemoryPages = 16
+)
+
+// Wasm represents a wasm binary encoded as base64.
 type Wasm struct {
-	WasmT string `json:"wasmt"`
+	Wasm           string `json:"wasm"`
+	GasLimit       uint64 `json:"gasLimit"`
+	MaxMemoryPages int    `json:"maxMemoryPages"`
 }
 
-// Perform ships the wasm representation to the SGX enclave where it is evaluated.
+// Perform evaluates the wasm program in a sandboxed interpreter. The
+// program must export a "perform" function, which is invoked with the
+// input's "result" field as its arguments; an array result is spread over
+// the function's parameters. The value returned by "perform" becomes the
+// new result.
+//
+// Execution is bounded by the adapter's "gasLimit", one unit of gas per
+// instruction, and its "maxMemoryPages" of linear memory.
 func (wasm *Wasm) Perform(input models.RunResult, _ *store.Store) models.RunResult {
-	input.SetError(fmt.Errorf("Wasm is not supported without SGX"))
-	return input
+	val, err := wasm.evaluate(input.Result())
+	if err != nil {
+		return models.RunResultError(err)
+	}
+	return models.RunResultComplete(val)
+}
+
+func (wasm *Wasm) evaluate(result gjson.Result) (_ string, err error) {
+	// The interpreter panics, rather than erroring, on some malformed
+	// programs, so recover and report them as a failed evaluation.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("evaluating wasm program: %v", r)
+		}
+	}()
+
+	program, err := base64.StdEncoding.DecodeString(wasm.Wasm)
+	if err != nil {
+		return "", fmt.Errorf("decoding wasm program: %v", err)
+	}
+
+	vm, err := exec.NewVirtualMachine(program, wasm.config(), &exec.NopResolver{}, &compiler.SimpleGasPolicy{GasPerInstruction: 1})
+	if err != nil {
+		return "", fmt.Errorf("loading wasm program: %v", err)
+	}
+
+	entryID, ok := vm.GetFunctionExport("perform")
+	if !ok {
+		return "", fmt.Errorf("wasm program does not export a perform function")
+	}
+	sig, err := wasmSignature(vm.Module.Base, entryID)
+	if err != nil {
+		return "", err
+	}
+
+	params, err := wasmParams(result, sig.ParamTypes)
+	if err != nil {
+		return "", err
+	}
+
+	ret, err := vm.Run(entryID, params...)
+	if err != nil {
+		return "", fmt.Errorf("executing wasm program: %v", err)
+	}
+	if vm.GasLimitExceeded {
+		return "", fmt.Errorf("wasm program exceeded gas limit of %d", vm.Config.GasLimit)
+	}
+	if len(sig.ReturnTypes) != 1 {
+		return "", fmt.Errorf("wasm perform function must return exactly one value")
+	}
+	return wasmResult(ret, sig.ReturnTypes[0])
+}
+
+func (wasm *Wasm) config() exec.VMConfig {
+	config := exec.VMConfig{
+		GasLimit:          wasm.GasLimit,
+		MaxMemoryPages:    wasm.MaxMemoryPages,
+		MaxCallStackDepth: 512,
+	}
+	if config.GasLimit == 0 {
+		config.GasLimit = DefaultWasmGasLimit
+	}
+	if config.MaxMemoryPages == 0 {
+		config.MaxMemoryPages = DefaultWasmMaxMemoryPages
+	}
+	return config
+}
+
+// wasmSignature returns the signature of the function at index functionID.
+// The function index space counts imported functions before those defined
+// in the module, but FunctionIndexSpace only holds the latter.
+func wasmSignature(module *wasm.Module, functionID int) (*wasm.FunctionSig, error) {
+	numImports := 0
+	if module.Import != nil {
+		for _, entry := range module.Import.Entries {
+			if entry.Type.Kind() == wasm.ExternalFunction {
+				numImports++
+			}
+		}
+	}
+
+	index := functionID - numImports
+	if index < 0 {
+		return nil, fmt.Errorf("wasm perform function must be defined in the program, not imported")
+	}
+	if index >= len(module.FunctionIndexSpace) {
+		return nil, fmt.Errorf("wasm perform function %d does not exist", functionID)
+	}
+	return module.FunctionIndexSpace[index].Sig, nil
+}
+
+func wasmParams(result gjson.Result, types []wasm.ValueType) ([]int64, error) {
+	var values []gjson.Result
+	if result.IsArray() {
+		values = result.Array()
+	} else if result.Exists() && result.Type != gjson.Null {
+		values = []gjson.Result{result}
+	}
+
+	if len(values) != len(types) {
+		return nil, fmt.Errorf("wasm perform function takes %d arguments, got %d", len(types), len(values))
+	}
+
+	params := make([]int64, len(values))
+	for i, value := range values {
+		param, err := wasmParam(value, types[i])
+		if err != nil {
+			return nil, err
+		}
+		params[i] = param
+	}
+	return params, nil
+}
+
+func wasmParam(value gjson.Result, t wasm.ValueType) (int64, error) {
+	if value.Type != gjson.Number && value.Type != gjson.String {
+		return 0, fmt.Errorf("wasm argument %s is not a number", value.Raw)
+	}
+
+	switch t {
+	case wasm.ValueTypeI32, wasm.ValueTypeI64:
+		i, err := strconv.ParseInt(value.String(), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("wasm argument %s is not an integer", value.Raw)
+		}
+		if t == wasm.ValueTypeI32 && (i < math.MinInt32 || i > math.MaxInt32) {
+			return 0, fmt.Errorf("wasm argument %s overflows i32", value.Raw)
+		}
+		return i, nil
+	case wasm.ValueTypeF32, wasm.ValueTypeF64:
+		f, err := strconv.ParseFloat(value.String(), 64)
+		if err != nil {
+			return 0, fmt.Errorf("wasm argument %s is not a number", value.Raw)
+		}
+		if t == wasm.ValueTypeF32 {
+			return int64(math.Float32bits(float32(f))), nil
+		}
+		return int64(math.Float64bits(f)), nil
+	}
+	return 0, fmt.Errorf("unsupported wasm argument type %v", t)
+}
+
+// wasmResult formats the raw return value as a string, since RunResult in
+// chainlink only supports string values.
+func wasmResult(ret int64, t wasm.ValueType) (string, error) {
+	switch t {
+	case wasm.ValueTypeI32:
+		return strconv.FormatInt(int64(int32(ret)), 10), nil
+	case wasm.ValueTypeI64:
+		return strconv.FormatInt(ret, 10), nil
+	case wasm.ValueTypeF32:
+		return strconv.FormatFloat(float64(math.Float32frombits(uint32(ret))), 'f', -1, 32), nil
+	case wasm.ValueTypeF64:
+		return strconv.FormatFloat(math.Float64frombits(uint64(ret)), 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported wasm return type %v", t)
 }
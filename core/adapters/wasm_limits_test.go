@@ -0,0 +1,65 @@
+// +build !sgx_enclave
+
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// InfiniteLoopProgram exports a perform function that never returns.
+	InfiniteLoopProgram = "AGFzbQEAAAABBQFgAAF/AwIBAAcLAQdwZXJmb3JtAAAKCwEJAANADAALQQAL"
+	// LargeMemoryProgram declares 32 pages of linear memory.
+	LargeMemoryProgram = "AGFzbQEAAAABBQFgAAF/AwIBAAUDAQAgBwsBB3BlcmZvcm0AAAoGAQQAQQAL"
+	// ImportingProgram is CheckEthProgram with an unused "env.log" function
+	// import, which shifts perform to function index 1.
+	ImportingProgram = "AGFzbQEAAAABCQJgAABgAXwBfwILAQNlbnYDbG9nAAADAgEBBwsBB3BlcmZvcm0AAQoQAQ4ARAAAAAAAIHxAIABjCw=="
+	// MalformedProgram is CheckEthProgram with an invalid opcode in the body
+	// of perform.
+	MalformedProgram = "AGFzbQEAAAABBgFgAXwBfwMCAQAHCwEHcGVyZm9ybQAAChABDgD/AAAAAAAgfEAgAGML"
+)
+
+func TestWasm_Perform_Limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		adapter adapters.Wasm
+		json    string
+		want    string
+		errored bool
+	}{
+		{"gas limit exceeded", adapters.Wasm{Wasm: InfiniteLoopProgram, GasLimit: 1000}, `{}`, "", true},
+		{"default gas limit exceeded", adapters.Wasm{Wasm: InfiniteLoopProgram}, `{}`, "", true},
+		{"memory limit exceeded", adapters.Wasm{Wasm: LargeMemoryProgram}, `{}`, "", true},
+		{"memory within limit", adapters.Wasm{Wasm: LargeMemoryProgram, MaxMemoryPages: 32}, `{}`, "0", false},
+		{"numeric string argument", adapters.Wasm{Wasm: CheckEthProgram}, `{"result": "451"}`, "1", false},
+		{"too many arguments", adapters.Wasm{Wasm: CheckEthProgram}, `{"result": [1, 2]}`, "", true},
+		{"imported function", adapters.Wasm{Wasm: ImportingProgram}, `{"result": 450.1}`, "1", false},
+		{"malformed bytecode", adapters.Wasm{Wasm: MalformedProgram}, `{"result": 450.1}`, "", true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := models.RunResult{
+				Data: cltest.JSONFromString(t, test.json),
+			}
+			result := test.adapter.Perform(input, nil)
+
+			if test.errored {
+				assert.Error(t, result.GetError())
+			} else {
+				assert.NoError(t, result.GetError())
+				val, err := result.ResultString()
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, val)
+			}
+		})
+	}
+}
@@ -262,6 +262,16 @@ func NewApp(client *Client) *cli.App {
 					Usage:  "get information on a specific Ethereum Transaction",
 					Action: client.ShowTransaction,
 				},
+				{
+					Name:   "cancel",
+					Usage:  "Replace a pending Ethereum Transaction with a zero value transfer to its sender, freeing its nonce",
+					Action: client.CancelTransaction,
+				},
+				{
+					Name:   "speedup",
+					Usage:  "Bump the gas of a pending Ethereum Transaction right away",
+					Action: client.SpeedUpTransaction,
+				},
 			},
 		},
 	}
@@ -462,6 +462,30 @@ func (cli *Client) ShowTransaction(c *clipkg.Context) error {
 	return cli.renderAPIResponse(resp, &tx)
 }
 
+// CancelTransaction replaces the transaction with the given hash by a zero
+// value transfer to its sender, freeing its nonce
+func (cli *Client) CancelTransaction(c *clipkg.Context) error {
+	return cli.replaceTransaction(c, "cancel")
+}
+
+// SpeedUpTransaction bumps the gas of the transaction with the given hash
+func (cli *Client) SpeedUpTransaction(c *clipkg.Context) error {
+	return cli.replaceTransaction(c, "speedup")
+}
+
+func (cli *Client) replaceTransaction(c *clipkg.Context, action string) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the hash of the transaction"))
+	}
+	resp, err := cli.HTTP.Post("/v2/transactions/"+c.Args().First()+"/"+action, bytes.NewBuffer(nil))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	var tx presenters.Tx
+	return cli.renderAPIResponse(resp, &tx)
+}
+
 // IndexTxAttempts returns the list of transactions in descending order,
 // taking an optional page parameter
 func (cli *Client) IndexTxAttempts(c *clipkg.Context) error {
@@ -745,6 +745,43 @@ func TestClient_ShowTransaction(t *testing.T) {
 	assert.Equal(t, &tx.From, renderedTx.From)
 }
 
+func TestClient_CancelAndSpeedUpTransaction(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	store := app.GetStore()
+	ethMock := app.MockEthCallerSubscriber()
+	ethMock.Register("eth_getTransactionCount", "0x0")
+	ethMock.Register("eth_chainId", store.Config.ChainID())
+	require.NoError(t, app.StartAndConnect())
+
+	from := cltest.GetAccountAddress(t, store)
+	tx := cltest.CreateTx(t, store, from, 1)
+
+	client, r := app.NewClientAndRenderer()
+
+	set := flag.NewFlagSet("test speedup tx", 0)
+	set.Parse([]string{tx.Hash.Hex()})
+	c := cli.NewContext(nil, set, nil)
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	require.NoError(t, client.SpeedUpTransaction(c))
+	renderedTx := *r.Renders[0].(*presenters.Tx)
+	assert.False(t, renderedTx.Cancelled)
+
+	set = flag.NewFlagSet("test cancel tx", 0)
+	set.Parse([]string{tx.Hash.Hex()})
+	c = cli.NewContext(nil, set, nil)
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	require.NoError(t, client.CancelTransaction(c))
+	renderedTx = *r.Renders[1].(*presenters.Tx)
+	assert.True(t, renderedTx.Cancelled)
+
+	c = cli.NewContext(nil, flag.NewFlagSet("test cancel no hash", 0), nil)
+	assert.EqualError(t, client.CancelTransaction(c), "Must pass the hash of the transaction")
+}
+
 func TestClient_IndexTxAttempts(t *testing.T) {
 	t.Parallel()
 
@@ -100,6 +100,7 @@ func NewTestConfig(t testing.TB) *TestConfig {
 	rawConfig.Set("ETH_CHAIN_ID", 3)
 	rawConfig.Set("CHAINLINK_DEV", true)
 	rawConfig.Set("ETH_GAS_BUMP_THRESHOLD", 3)
+	rawConfig.Set("ETH_STUCK_TX_BLOCKS", 0)
 	rawConfig.Set("LOG_LEVEL", orm.LogLevel{Level: zapcore.DebugLevel})
 	rawConfig.Set("LOG_SQL", false)
 	rawConfig.Set("LOG_SQL_MIGRATIONS", false)
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/mrwonko/cron"
 	"github.com/onsi/gomega"
 	"github.com/smartcontractkit/chainlink/core/cmd"
 	"github.com/smartcontractkit/chainlink/core/logger"
@@ -456,6 +457,11 @@ func (t *TriggerClock) After(_ time.Duration) <-chan time.Time {
 	return t.triggers
 }
 
+// Now returns the current local time.
+func (t *TriggerClock) Now() time.Time {
+	return time.Now()
+}
+
 // NeverClock a never clock
 type NeverClock struct{}
 
@@ -606,13 +612,12 @@ func (*MockCron) Start() {}
 // Stop stops the mockcron
 func (*MockCron) Stop() {}
 
-// AddFunc appends a schedule to mockcron entries
-func (mc *MockCron) AddFunc(schd string, fn func()) error {
+// Schedule appends a schedule to mockcron entries
+func (mc *MockCron) Schedule(schd cron.Schedule, job cron.Job) {
 	mc.Entries = append(mc.Entries, MockCronEntry{
 		Schedule: schd,
-		Function: fn,
+		Function: job.Run,
 	})
-	return nil
 }
 
 // RunEntries run every function for each mockcron entry
@@ -624,7 +629,7 @@ func (mc *MockCron) RunEntries() {
 
 // MockCronEntry a cron schedule and function
 type MockCronEntry struct {
-	Schedule string
+	Schedule cron.Schedule
 	Function func()
 }
 
@@ -634,6 +639,8 @@ type MockHeadTrackable struct {
 	ConnectedCallback func(bn *models.Head)
 	disconnectedCount int32
 	onNewHeadCount    int32
+	onReorgCount      int32
+	ReorgCallback     func(*models.Reorg)
 }
 
 // Connect increases the connected count by one
@@ -666,6 +673,19 @@ func (m *MockHeadTrackable) OnNewHeadCount() int32 {
 	return atomic.LoadInt32(&m.onNewHeadCount)
 }
 
+// OnReorg increases the OnReorgCount count by one
+func (m *MockHeadTrackable) OnReorg(reorg *models.Reorg) {
+	atomic.AddInt32(&m.onReorgCount, 1)
+	if m.ReorgCallback != nil {
+		m.ReorgCallback(reorg)
+	}
+}
+
+// OnReorgCount returns the count of reorgs, safely.
+func (m *MockHeadTrackable) OnReorgCount() int32 {
+	return atomic.LoadInt32(&m.onReorgCount)
+}
+
 // NeverSleeper is a struct that never sleeps
 type NeverSleeper struct{}
 
@@ -8,6 +8,7 @@ import (
 	go_ethereum "github.com/ethereum/go-ethereum"
 	common "github.com/ethereum/go-ethereum/common"
 	gomock "github.com/golang/mock/gomock"
+	store "github.com/smartcontractkit/chainlink/core/store"
 	assets "github.com/smartcontractkit/chainlink/core/store/assets"
 	models "github.com/smartcontractkit/chainlink/core/store/models"
 	big "math/big"
@@ -37,6 +38,51 @@ func (m *MockEthClient) EXPECT() *MockEthClientMockRecorder {
 	return m.recorder
 }
 
+// CallContract mocks base method
+func (m *MockEthClient) CallContract(arg0 store.CallMsg) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CallContract", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CallContract indicates an expected call of CallContract
+func (mr *MockEthClientMockRecorder) CallContract(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallContract", reflect.TypeOf((*MockEthClient)(nil).CallContract), arg0)
+}
+
+// CallContractAt mocks base method
+func (m *MockEthClient) CallContractAt(arg0 store.CallMsg, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CallContractAt", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CallContractAt indicates an expected call of CallContractAt
+func (mr *MockEthClientMockRecorder) CallContractAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallContractAt", reflect.TypeOf((*MockEthClient)(nil).CallContractAt), arg0, arg1)
+}
+
+// EstimateGas mocks base method
+func (m *MockEthClient) EstimateGas(arg0 store.CallMsg) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateGas", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateGas indicates an expected call of EstimateGas
+func (mr *MockEthClientMockRecorder) EstimateGas(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateGas", reflect.TypeOf((*MockEthClient)(nil).EstimateGas), arg0)
+}
+
 // GetBlockByNumber mocks base method
 func (m *MockEthClient) GetBlockByNumber(arg0 string) (models.BlockHeader, error) {
 	m.ctrl.T.Helper()
@@ -52,6 +98,21 @@ func (mr *MockEthClientMockRecorder) GetBlockByNumber(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByNumber", reflect.TypeOf((*MockEthClient)(nil).GetBlockByNumber), arg0)
 }
 
+// GetBlockWithTransactions mocks base method
+func (m *MockEthClient) GetBlockWithTransactions(arg0 string) (models.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockWithTransactions", arg0)
+	ret0, _ := ret[0].(models.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockWithTransactions indicates an expected call of GetBlockWithTransactions
+func (mr *MockEthClientMockRecorder) GetBlockWithTransactions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockWithTransactions", reflect.TypeOf((*MockEthClient)(nil).GetBlockWithTransactions), arg0)
+}
+
 // GetChainID mocks base method
 func (m *MockEthClient) GetChainID() (*big.Int, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +56,51 @@ func (mr *MockTxManagerMockRecorder) BumpGasUntilSafe(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BumpGasUntilSafe", reflect.TypeOf((*MockTxManager)(nil).BumpGasUntilSafe), arg0)
 }
 
+// CallContract mocks base method
+func (m *MockTxManager) CallContract(arg0 store.CallMsg) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CallContract", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CallContract indicates an expected call of CallContract
+func (mr *MockTxManagerMockRecorder) CallContract(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallContract", reflect.TypeOf((*MockTxManager)(nil).CallContract), arg0)
+}
+
+// CallContractAt mocks base method
+func (m *MockTxManager) CallContractAt(arg0 store.CallMsg, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CallContractAt", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CallContractAt indicates an expected call of CallContractAt
+func (mr *MockTxManagerMockRecorder) CallContractAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallContractAt", reflect.TypeOf((*MockTxManager)(nil).CallContractAt), arg0, arg1)
+}
+
+// CancelTx mocks base method
+func (m *MockTxManager) CancelTx(arg0 common.Hash) (*models.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTx", arg0)
+	ret0, _ := ret[0].(*models.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelTx indicates an expected call of CancelTx
+func (mr *MockTxManagerMockRecorder) CancelTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTx", reflect.TypeOf((*MockTxManager)(nil).CancelTx), arg0)
+}
+
 // CheckAttempt mocks base method
 func (m *MockTxManager) CheckAttempt(arg0 *models.TxAttempt, arg1 uint64) (*models.TxReceipt, store.AttemptState, error) {
 	m.ctrl.T.Helper()
@@ -160,6 +205,21 @@ func (mr *MockTxManagerMockRecorder) CreateTxWithGas(arg0, arg1, arg2, arg3, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTxWithGas", reflect.TypeOf((*MockTxManager)(nil).CreateTxWithGas), arg0, arg1, arg2, arg3, arg4)
 }
 
+// CreateTxWithFees mocks base method
+func (m *MockTxManager) CreateTxWithFees(arg0 null_v3.String, arg1 common.Address, arg2 []byte, arg3, arg4 *big.Int, arg5 uint64) (*models.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTxWithFees", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*models.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTxWithFees indicates an expected call of CreateTxWithFees
+func (mr *MockTxManagerMockRecorder) CreateTxWithFees(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTxWithFees", reflect.TypeOf((*MockTxManager)(nil).CreateTxWithFees), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // Disconnect mocks base method
 func (m *MockTxManager) Disconnect() {
 	m.ctrl.T.Helper()
@@ -288,6 +348,18 @@ func (mr *MockTxManagerMockRecorder) OnNewHead(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnNewHead", reflect.TypeOf((*MockTxManager)(nil).OnNewHead), arg0)
 }
 
+// OnReorg mocks base method
+func (m *MockTxManager) OnReorg(arg0 *models.Reorg) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnReorg", arg0)
+}
+
+// OnReorg indicates an expected call of OnReorg
+func (mr *MockTxManagerMockRecorder) OnReorg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnReorg", reflect.TypeOf((*MockTxManager)(nil).OnReorg), arg0)
+}
+
 // Register mocks base method
 func (m *MockTxManager) Register(arg0 []accounts.Account) {
 	m.ctrl.T.Helper()
@@ -300,6 +372,21 @@ func (mr *MockTxManagerMockRecorder) Register(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockTxManager)(nil).Register), arg0)
 }
 
+// SpeedUpTx mocks base method
+func (m *MockTxManager) SpeedUpTx(arg0 common.Hash) (*models.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpeedUpTx", arg0)
+	ret0, _ := ret[0].(*models.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpeedUpTx indicates an expected call of SpeedUpTx
+func (mr *MockTxManagerMockRecorder) SpeedUpTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpeedUpTx", reflect.TypeOf((*MockTxManager)(nil).SpeedUpTx), arg0)
+}
+
 // SubscribeToLogs mocks base method
 func (m *MockTxManager) SubscribeToLogs(arg0 chan<- models.Log, arg1 go_ethereum.FilterQuery) (models.EthSubscription, error) {
 	m.ctrl.T.Helper()
@@ -34,6 +34,7 @@ type Application interface {
 type ChainlinkApplication struct {
 	Exiter                   func(int)
 	HeadTracker              *HeadTracker
+	BlockScheduler           *BlockScheduler
 	JobRunner                JobRunner
 	JobSubscriber            JobSubscriber
 	Scheduler                *Scheduler
@@ -52,10 +53,12 @@ func NewApplication(config *orm.Config, onConnectCallbacks ...func(Application))
 	config.SetRuntimeStore(store.ORM)
 
 	jobSubscriber := NewJobSubscriber(store)
+	blockScheduler := NewBlockScheduler(store)
 	pendingConnectionResumer := newPendingConnectionResumer(store)
 
 	app := &ChainlinkApplication{
 		JobSubscriber:            jobSubscriber,
+		BlockScheduler:           blockScheduler,
 		JobRunner:                NewJobRunner(store),
 		Scheduler:                NewScheduler(store),
 		Store:                    store,
@@ -67,6 +70,7 @@ func NewApplication(config *orm.Config, onConnectCallbacks ...func(Application))
 	headTrackables := []strpkg.HeadTrackable{
 		store.TxManager,
 		jobSubscriber,
+		blockScheduler,
 		pendingConnectionResumer,
 	}
 	for _, onConnectCallback := range onConnectCallbacks {
@@ -147,12 +151,14 @@ func (app *ChainlinkApplication) AddJob(job models.JobSpec) error {
 	}
 
 	app.Scheduler.AddJob(job)
+	app.BlockScheduler.AddJob(job)
 	return app.JobSubscriber.AddJob(job, nil) // nil for latest
 }
 
 // ArchiveJob silences the job from the system, preventing future job runs.
 func (app *ChainlinkApplication) ArchiveJob(ID *models.ID) error {
 	_ = app.JobSubscriber.RemoveJob(ID)
+	app.BlockScheduler.RemoveJob(ID)
 	return app.Store.ArchiveJob(ID)
 }
 
@@ -165,6 +171,7 @@ func (app *ChainlinkApplication) AddServiceAgreement(sa *models.ServiceAgreement
 	}
 
 	app.Scheduler.AddJob(sa.JobSpec)
+	app.BlockScheduler.AddJob(sa.JobSpec)
 	return app.JobSubscriber.AddJob(sa.JobSpec, nil) // nil for latest
 }
 
@@ -201,3 +208,4 @@ func (p *pendingConnectionResumer) Connect(head *models.Head) error {
 
 func (p *pendingConnectionResumer) Disconnect()            {}
 func (p *pendingConnectionResumer) OnNewHead(*models.Head) {}
+func (p *pendingConnectionResumer) OnReorg(*models.Reorg)  {}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"go.uber.org/multierr"
+)
+
+// BlockScheduler runs the jobs of "blocks" and "txconfirmations" initiators
+// as new heads arrive, so that they follow the progress of the chain rather
+// than the wall clock.
+//
+// Heads are processed in the background, so that the HeadTracker is not held
+// up by the database and receipt requests; while a head is processed, only
+// the latest of the heads that arrive in the meantime is processed next.
+type BlockScheduler struct {
+	store      *store.Store
+	jobs       map[string]models.JobSpec
+	receipts   map[common.Hash]*models.TxReceipt
+	jobsMutex  sync.Mutex
+	lastHeight *int64
+
+	headMutex  sync.Mutex
+	pending    *models.Head
+	reorged    bool
+	processing bool
+}
+
+// NewBlockScheduler returns a new BlockScheduler, to be attached to the
+// HeadTracker.
+func NewBlockScheduler(store *store.Store) *BlockScheduler {
+	return &BlockScheduler{
+		store:    store,
+		jobs:     map[string]models.JobSpec{},
+		receipts: map[common.Hash]*models.TxReceipt{},
+	}
+}
+
+// AddJob schedules the job if it has a "blocks" initiator, or a
+// "txconfirmations" initiator that has yet to run.
+func (bs *BlockScheduler) AddJob(job models.JobSpec) {
+	if !scheduled(job) {
+		return
+	}
+	bs.jobsMutex.Lock()
+	defer bs.jobsMutex.Unlock()
+	bs.jobs[job.ID.String()] = job
+}
+
+// RemoveJob stops scheduling the job.
+func (bs *BlockScheduler) RemoveJob(ID *models.ID) {
+	bs.jobsMutex.Lock()
+	defer bs.jobsMutex.Unlock()
+	delete(bs.jobs, ID.String())
+}
+
+// Connect schedules the stored jobs, counting block intervals from the head
+// connected at.
+func (bs *BlockScheduler) Connect(head *models.Head) error {
+	bs.jobsMutex.Lock()
+	if head != nil {
+		height := head.Number
+		bs.lastHeight = &height
+	}
+	bs.jobsMutex.Unlock()
+
+	return bs.store.Jobs(func(j models.JobSpec) bool {
+		bs.AddJob(j)
+		return true
+	})
+}
+
+// Disconnect stops scheduling all jobs until the next Connect.
+func (bs *BlockScheduler) Disconnect() {
+	bs.jobsMutex.Lock()
+	defer bs.jobsMutex.Unlock()
+	bs.jobs = map[string]models.JobSpec{}
+	bs.lastHeight = nil
+}
+
+// OnNewHead queues the head to run, in the background, the jobs whose block
+// interval was reached since the last head, and those whose transaction
+// reached enough confirmations.
+func (bs *BlockScheduler) OnNewHead(head *models.Head) {
+	bs.headMutex.Lock()
+	defer bs.headMutex.Unlock()
+	bs.pending = head
+	if !bs.processing {
+		bs.processing = true
+		go bs.processLoop()
+	}
+}
+
+// OnReorg forgets the receipts of mined transactions, which may have moved
+// to another block. Block intervals are counted on the new chain.
+func (bs *BlockScheduler) OnReorg(*models.Reorg) {
+	bs.headMutex.Lock()
+	defer bs.headMutex.Unlock()
+	bs.reorged = true
+}
+
+func (bs *BlockScheduler) processLoop() {
+	for {
+		bs.headMutex.Lock()
+		head, reorged := bs.pending, bs.reorged
+		bs.pending, bs.reorged = nil, false
+		if head == nil {
+			bs.processing = false
+			bs.headMutex.Unlock()
+			return
+		}
+		bs.headMutex.Unlock()
+
+		bs.process(head, reorged)
+	}
+}
+
+func (bs *BlockScheduler) process(head *models.Head, reorged bool) {
+	bs.jobsMutex.Lock()
+	defer bs.jobsMutex.Unlock()
+
+	if reorged {
+		bs.receipts = map[common.Hash]*models.TxReceipt{}
+	}
+
+	now := bs.store.Clock.Now()
+	for id, job := range bs.jobs {
+		if bs.store.Archived(job.ID) {
+			delete(bs.jobs, id)
+			continue
+		}
+		if job.Ended(now) || !job.Started(now) {
+			continue
+		}
+		for i := range job.Initiators {
+			initr := &job.Initiators[i]
+			var err error
+			switch initr.Type {
+			case models.InitiatorBlocks:
+				err = bs.runOnInterval(job, *initr, head)
+			case models.InitiatorTxConfirmations:
+				err = bs.runOnConfirmations(job, initr, head)
+			}
+			if err != nil && !expectedRecurringScheduleJobError(err) {
+				logger.Errorw(fmt.Sprintf("BlockScheduler: %v", err), "job", job.ID.String())
+			}
+		}
+		if !scheduled(job) {
+			delete(bs.jobs, id)
+		}
+	}
+
+	if bs.lastHeight == nil || head.Number > *bs.lastHeight {
+		height := head.Number
+		bs.lastHeight = &height
+	}
+}
+
+// runOnInterval runs the job once if a multiple of the block interval was
+// reached since the last head, even if heads in between were skipped.
+func (bs *BlockScheduler) runOnInterval(job models.JobSpec, initr models.Initiator, head *models.Head) error {
+	if bs.lastHeight == nil || head.Number <= *bs.lastHeight {
+		return nil
+	}
+	interval := int64(initr.BlockInterval)
+	if head.Number/interval == *bs.lastHeight/interval {
+		return nil
+	}
+	_, err := ExecuteJob(job, initr, headRunResult(head), nil, bs.store)
+	return err
+}
+
+// runOnConfirmations runs the job, only once, when the initiator's
+// transaction has reached its number of confirmations.
+func (bs *BlockScheduler) runOnConfirmations(job models.JobSpec, initr *models.Initiator, head *models.Head) error {
+	if initr.Ran {
+		return nil
+	}
+	receipt, err := bs.receipt(initr.TxHash)
+	if err != nil || receipt == nil {
+		return err
+	}
+	confirmations := head.Number - receipt.BlockNumber.ToInt().Int64() + 1
+	if confirmations < int64(initr.Confirmations) {
+		return nil
+	}
+
+	if err := bs.store.MarkRan(initr, true); err != nil {
+		return err
+	}
+	initr.Ran = true
+	input := headRunResult(head)
+	input.Data, _ = input.Data.Add("txHash", initr.TxHash.Hex())
+	if _, err := ExecuteJob(job, *initr, input, nil, bs.store); err != nil {
+		initr.Ran = false
+		return multierr.Append(err, bs.store.MarkRan(initr, false))
+	}
+	delete(bs.receipts, initr.TxHash)
+	return nil
+}
+
+// receipt returns the receipt of the transaction once it has been mined, or
+// nil until then. The receipt of a mined transaction is only requested once.
+func (bs *BlockScheduler) receipt(txHash common.Hash) (*models.TxReceipt, error) {
+	if receipt, ok := bs.receipts[txHash]; ok {
+		return receipt, nil
+	}
+	receipt, err := bs.store.TxManager.GetTxReceipt(txHash)
+	if err != nil || receipt.Unconfirmed() {
+		return nil, err
+	}
+	bs.receipts[txHash] = receipt
+	return receipt, nil
+}
+
+// scheduled returns true if the job has a "blocks" initiator, or a
+// "txconfirmations" initiator that has yet to run.
+func scheduled(job models.JobSpec) bool {
+	for _, initr := range job.Initiators {
+		switch initr.Type {
+		case models.InitiatorBlocks:
+			return true
+		case models.InitiatorTxConfirmations:
+			if !initr.Ran {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headRunResult is the input of runs started by a head, with its number and
+// hash.
+func headRunResult(head *models.Head) models.RunResult {
+	data, _ := models.JSON{}.Add("blockNumber", head.Number)
+	data, _ = data.Add("blockHash", head.Hash.Hex())
+	return models.RunResult{Data: data}
+}
@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockScheduler_OnNewHead_Blocks(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{
+		Type:            models.InitiatorBlocks,
+		InitiatorParams: models.InitiatorParams{BlockInterval: 3},
+	}}
+	require.NoError(t, store.CreateJob(&j))
+
+	bs := services.NewBlockScheduler(store)
+	require.NoError(t, bs.Connect(cltest.Head(1)))
+
+	bs.OnNewHead(cltest.Head(2))
+	cltest.WaitForRuns(t, j, store, 0)
+
+	bs.OnNewHead(cltest.Head(3))
+	runs := cltest.WaitForRuns(t, j, store, 1)
+	assert.Equal(t, "3", runs[0].Overrides.Get("blockNumber").String())
+
+	bs.OnNewHead(cltest.Head(4))
+	bs.OnNewHead(cltest.Head(5))
+	cltest.WaitForRuns(t, j, store, 1)
+
+	// Skipping block 6 still reaches the interval
+	bs.OnNewHead(cltest.Head(7))
+	cltest.WaitForRuns(t, j, store, 2)
+}
+
+func TestBlockScheduler_OnNewHead_ArchivedJob(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{
+		Type:            models.InitiatorBlocks,
+		InitiatorParams: models.InitiatorParams{BlockInterval: 1},
+	}}
+	require.NoError(t, store.CreateJob(&j))
+
+	bs := services.NewBlockScheduler(store)
+	require.NoError(t, bs.Connect(cltest.Head(1)))
+	require.NoError(t, store.ArchiveJob(j.ID))
+
+	bs.OnNewHead(cltest.Head(2))
+	gomega.NewGomegaWithT(t).Consistently(func() []models.JobRun {
+		runs, err := store.Unscoped().JobRunsFor(j.ID)
+		require.NoError(t, err)
+		return runs
+	}).Should(gomega.HaveLen(0))
+}
+
+func TestBlockScheduler_OnNewHead_TxConfirmations(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	txm := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txm
+
+	txHash := cltest.NewHash()
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{
+		Type: models.InitiatorTxConfirmations,
+		InitiatorParams: models.InitiatorParams{
+			TxHash:        txHash,
+			Confirmations: 3,
+		},
+	}}
+	require.NoError(t, store.CreateJob(&j))
+
+	// the receipt is only requested again until the transaction is mined
+	txm.EXPECT().GetTxReceipt(txHash).Return(&models.TxReceipt{}, nil)
+	txm.EXPECT().GetTxReceipt(txHash).Return(&models.TxReceipt{
+		Hash:        txHash,
+		BlockNumber: cltest.Int(10),
+	}, nil)
+
+	bs := services.NewBlockScheduler(store)
+	require.NoError(t, bs.Connect(cltest.Head(9)))
+
+	bs.OnNewHead(cltest.Head(10))
+	bs.OnNewHead(cltest.Head(11))
+	cltest.WaitForRuns(t, j, store, 0)
+
+	bs.OnNewHead(cltest.Head(12))
+	runs := cltest.WaitForRuns(t, j, store, 1)
+	assert.Equal(t, txHash.Hex(), runs[0].Overrides.Get("txHash").String())
+
+	bs.OnNewHead(cltest.Head(13))
+	cltest.WaitForRuns(t, j, store, 1)
+
+	initr, err := store.FindInitiator(j.Initiators[0].ID)
+	require.NoError(t, err)
+	assert.True(t, initr.Ran)
+}
+
+func TestBlockScheduler_OnNewHead_DoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	txm := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txm
+
+	txHash := cltest.NewHash()
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{
+		Type: models.InitiatorTxConfirmations,
+		InitiatorParams: models.InitiatorParams{
+			TxHash:        txHash,
+			Confirmations: 1,
+		},
+	}}
+	require.NoError(t, store.CreateJob(&j))
+
+	requesting := make(chan struct{})
+	release := make(chan struct{})
+	txm.EXPECT().GetTxReceipt(txHash).DoAndReturn(func(common.Hash) (*models.TxReceipt, error) {
+		close(requesting)
+		<-release
+		return &models.TxReceipt{}, nil
+	})
+
+	bs := services.NewBlockScheduler(store)
+	require.NoError(t, bs.Connect(cltest.Head(9)))
+	bs.OnNewHead(cltest.Head(10))
+	<-requesting
+
+	// heads arriving while a head is processed are coalesced into the latest
+	bs.OnNewHead(cltest.Head(11))
+	bs.OnNewHead(cltest.Head(12))
+	txm.EXPECT().GetTxReceipt(txHash).Return(&models.TxReceipt{
+		Hash:        txHash,
+		BlockNumber: cltest.Int(12),
+	}, nil)
+	close(release)
+
+	runs := cltest.WaitForRuns(t, j, store, 1)
+	assert.Equal(t, "12", runs[0].Overrides.Get("blockNumber").String())
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 )
@@ -12,8 +13,16 @@ func ExportedExecuteRun(
 	return executeRun(run, store)
 }
 
-func ExportedChannelForRun(jr JobRunner, runID *models.ID) chan<- struct{} {
-	return jr.channelForRun(runID)
+func ExportedProcessHead(ht *HeadTracker, head *models.Head) {
+	ht.processHead(head)
+}
+
+func ExportedEnqueue(jr JobRunner, runID *models.ID) error {
+	return jr.enqueue(runID)
+}
+
+func ExportedTake(jr JobRunner) (models.QueuedRun, bool) {
+	return jr.(*jobRunner).take()
 }
 
 func ExportedResumeRunsSinceLastShutdown(jr JobRunner) error {
@@ -33,3 +42,13 @@ func ExportedNewPendingConnectionResumer(
 		resumer: resumer,
 	}
 }
+
+func ExportedSaveJobRun(run *models.JobRun, store *store.Store) error {
+	return saveJobRun(run, store)
+}
+
+func ExportedRunsFinished(jobID *models.ID) (completed, errored, cancelled float64) {
+	return testutil.ToFloat64(promRunsCompleted.WithLabelValues(jobID.String())),
+		testutil.ToFloat64(promRunsErrored.WithLabelValues(jobID.String())),
+		testutil.ToFloat64(promRunsCancelled.WithLabelValues(jobID.String()))
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+var (
+	// FluxMonitorLatestAnswerSelector is the function selector of the
+	// aggregator's latestAnswer() method, returning the value on chain.
+	FluxMonitorLatestAnswerSelector = utils.MustHash("latestAnswer()").Bytes()[:4]
+	// FluxMonitorLatestTimestampSelector is the function selector of the
+	// aggregator's latestTimestamp() method, returning when the value on
+	// chain was last updated.
+	FluxMonitorLatestTimestampSelector = utils.MustHash("latestTimestamp()").Bytes()[:4]
+)
+
+// FluxMonitor polls the feeds of "fluxmonitor" initiators and runs their job
+// when the polled value deviates from the one on chain by more than the
+// threshold, or when the value on chain is older than the heartbeat.
+type FluxMonitor struct {
+	Store *store.Store
+	Clock utils.AfterNower
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewFluxMonitor creates a new instance of FluxMonitor, ready to use.
+func NewFluxMonitor(store *store.Store) *FluxMonitor {
+	return &FluxMonitor{
+		Store: store,
+		Clock: store.Clock,
+	}
+}
+
+// Start allocates the channel used to stop polling.
+func (fm *FluxMonitor) Start() error {
+	fm.done = make(chan struct{})
+	return nil
+}
+
+// Stop stops polling and waits for the polls in progress to finish.
+func (fm *FluxMonitor) Stop() {
+	close(fm.done)
+	fm.wg.Wait()
+}
+
+// AddJob starts polling the feeds of every "fluxmonitor" initiator of the
+// job.
+func (fm *FluxMonitor) AddJob(job models.JobSpec) {
+	for _, initr := range job.InitiatorsFor(models.InitiatorFluxMonitor) {
+		var feeds adapters.Aggregate
+		if err := json.Unmarshal([]byte(initr.Feeds.String()), &feeds); err != nil {
+			logger.Errorf("FluxMonitor: invalid feeds for job %s: %v", job.ID.String(), err)
+			continue
+		}
+		fm.wg.Add(1)
+		go fm.poll(initr, job, feeds)
+	}
+}
+
+// poll checks the feeds every polling interval. While the run it last
+// started is unfinished, its transaction may not have updated the answer on
+// chain yet, so the feeds are not checked, to not start another run for the
+// same deviation.
+func (fm *FluxMonitor) poll(initr models.Initiator, job models.JobSpec, feeds adapters.Aggregate) {
+	defer fm.wg.Done()
+	var inFlight *models.ID
+	for {
+		if fm.Store.Archived(job.ID) {
+			return
+		}
+		if inFlight != nil && !fm.runFinished(inFlight) {
+			logger.Debugw("FluxMonitor: skipping poll while the last run is unfinished", "job", job.ID.String(), "run", inFlight.String())
+		} else if !job.Ended(fm.Clock.Now()) && job.Started(fm.Clock.Now()) {
+			inFlight = nil
+			run, err := fm.check(initr, job, feeds)
+			if err != nil {
+				logger.Errorw(fmt.Sprintf("FluxMonitor: %v", err), "job", job.ID.String())
+			}
+			if run != nil {
+				inFlight = run.ID
+			}
+		}
+		select {
+		case <-fm.done:
+			return
+		case <-fm.Clock.After(initr.PollingInterval.Duration()):
+		}
+	}
+}
+
+// runFinished returns true if the run has finished, or can no longer be
+// found.
+func (fm *FluxMonitor) runFinished(id *models.ID) bool {
+	status, err := fm.Store.JobRunStatus(id)
+	if err != nil {
+		logger.Warnw("FluxMonitor: unable to find the status of the last run", "run", id.String(), "error", err)
+		return true
+	}
+	return status.Finished()
+}
+
+// check polls the feeds and runs the job with the polled value, scaled by
+// the initiator's precision, if it should be written on chain, returning
+// the run it started.
+func (fm *FluxMonitor) check(initr models.Initiator, job models.JobSpec, feeds adapters.Aggregate) (*models.JobRun, error) {
+	polled, err := fm.pollFeeds(initr, feeds)
+	if err != nil {
+		return nil, err
+	}
+	current, err := fm.callInt(initr.Address, FluxMonitorLatestAnswerSelector)
+	if err != nil {
+		return nil, fmt.Errorf("reading latest answer: %v", err)
+	}
+	updatedAt, err := fm.callInt(initr.Address, FluxMonitorLatestTimestampSelector)
+	if err != nil {
+		return nil, fmt.Errorf("reading latest timestamp: %v", err)
+	}
+
+	if !OutsideDeviation(current, polled, initr.Threshold) && !fm.heartbeatElapsed(initr, updatedAt) {
+		return nil, nil
+	}
+
+	data, err := models.JSON{}.Add("result", polled.String())
+	if err != nil {
+		return nil, err
+	}
+	return ExecuteJob(job, initr, models.RunResult{Data: data}, nil, fm.Store)
+}
+
+// pollFeeds aggregates the results of the feeds and scales the aggregate to
+// an integer with the initiator's precision.
+func (fm *FluxMonitor) pollFeeds(initr models.Initiator, feeds adapters.Aggregate) (*big.Int, error) {
+	result := feeds.Perform(models.RunResult{}, fm.Store)
+	if result.HasError() {
+		return nil, fmt.Errorf("polling feeds: %v", result.GetError())
+	}
+	raw := result.Result().String()
+	value, ok := new(big.Float).SetPrec(128).SetString(raw)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse polled value %q as a number", raw)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(initr.Precision)), nil)
+	value.Mul(value, new(big.Float).SetInt(scale))
+	scaled, _ := value.Int(nil)
+	return scaled, nil
+}
+
+func (fm *FluxMonitor) callInt(address common.Address, selector []byte) (*big.Int, error) {
+	b, err := fm.Store.TxManager.CallContract(store.CallMsg{To: address, Data: selector})
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("call returned no data, the aggregator may not exist at that address")
+	} else if len(b) < utils.EVMWordByteLen {
+		return nil, fmt.Errorf("call returned %d bytes, fewer than an integer", len(b))
+	}
+	return math.S256(new(big.Int).SetBytes(b[:utils.EVMWordByteLen])), nil
+}
+
+func (fm *FluxMonitor) heartbeatElapsed(initr models.Initiator, updatedAt *big.Int) bool {
+	if initr.Heartbeat == 0 {
+		return false
+	}
+	deadline := time.Unix(updatedAt.Int64(), 0).Add(initr.Heartbeat.Duration())
+	return !fm.Clock.Now().Before(deadline)
+}
+
+// OutsideDeviation returns true if the polled value differs from the current
+// one by more than threshold percent of the current one. Any change from a
+// current value of zero is outside the deviation.
+func OutsideDeviation(current, polled *big.Int, threshold float64) bool {
+	diff := new(big.Int).Sub(polled, current)
+	if current.Sign() == 0 {
+		return diff.Sign() != 0
+	}
+	percentage := new(big.Float).Quo(new(big.Float).SetInt(diff), new(big.Float).SetInt(current))
+	percentage.Abs(percentage).Mul(percentage, big.NewFloat(100))
+	return percentage.Cmp(big.NewFloat(threshold)) > 0
+}
@@ -0,0 +1,187 @@
+package services_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutsideDeviation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		current   int64
+		polled    int64
+		threshold float64
+		want      bool
+	}{
+		{"unchanged", 10000, 10000, 0.5, false},
+		{"within threshold", 10000, 10040, 0.5, false},
+		{"on threshold", 10000, 10050, 0.5, false},
+		{"above threshold", 10000, 10051, 0.5, true},
+		{"below threshold", 10000, 9949, 0.5, true},
+		{"negative current", -10000, -10100, 0.5, true},
+		{"from zero", 0, 1, 0.5, true},
+		{"zero unchanged", 0, 0, 0.5, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := services.OutsideDeviation(big.NewInt(test.current), big.NewInt(test.polled), test.threshold)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func newFluxMonitorJob(t *testing.T, address common.Address, feedURL string, threshold float64, heartbeat time.Duration) models.JobSpec {
+	j := cltest.NewJob()
+	feeds := cltest.JSONFromString(t, fmt.Sprintf(`{"sources":[{"tasks":[{"type":"httpget","params":{"get":"%s"}}]}]}`, feedURL))
+	j.Initiators = []models.Initiator{{
+		JobSpecID: j.ID,
+		Type:      models.InitiatorFluxMonitor,
+		InitiatorParams: models.InitiatorParams{
+			Address:         address,
+			Feeds:           &feeds,
+			Threshold:       threshold,
+			Precision:       2,
+			PollingInterval: models.Duration(time.Minute),
+			Heartbeat:       models.Duration(heartbeat),
+		},
+	}}
+	return j
+}
+
+func mockAggregatorCalls(txm *mocks.MockTxManager, answer int64, updatedAt time.Time) {
+	txm.EXPECT().CallContract(gomock.Any()).DoAndReturn(func(msg store.CallMsg) ([]byte, error) {
+		if bytes.Equal(msg.Data, services.FluxMonitorLatestAnswerSelector) {
+			return common.LeftPadBytes(big.NewInt(answer).Bytes(), utils.EVMWordByteLen), nil
+		}
+		return common.LeftPadBytes(big.NewInt(updatedAt.Unix()).Bytes(), utils.EVMWordByteLen), nil
+	}).AnyTimes()
+}
+
+func TestFluxMonitor_AddJob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		answer    int64
+		updatedAt time.Time
+		heartbeat time.Duration
+		wantRuns  int
+	}{
+		{"outside deviation", 10000, time.Now(), time.Hour, 1},
+		{"within deviation", 10100, time.Now(), time.Hour, 0},
+		{"heartbeat elapsed", 10100, time.Now().Add(-2 * time.Hour), time.Hour, 1},
+		{"no heartbeat", 10100, time.Now().Add(-2 * time.Hour), 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, cleanup := cltest.NewStore(t)
+			defer cleanup()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			txm := mocks.NewMockTxManager(ctrl)
+			store.TxManager = txm
+			mockAggregatorCalls(txm, test.answer, test.updatedAt)
+
+			feed, feedCleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", "101.5")
+			defer feedCleanup()
+
+			address := cltest.NewAddress()
+			j := newFluxMonitorJob(t, address, feed.URL, 1, test.heartbeat)
+			require.NoError(t, store.CreateJob(&j))
+
+			fm := services.NewFluxMonitor(store)
+			fm.Clock = cltest.NeverClock{}
+			require.NoError(t, fm.Start())
+			fm.AddJob(j)
+
+			runs := cltest.WaitForRuns(t, j, store, test.wantRuns)
+			fm.Stop()
+			if test.wantRuns > 0 {
+				assert.Equal(t, "10150", runs[0].Overrides.Get("result").String())
+			}
+		})
+	}
+}
+
+func TestFluxMonitor_AddJob_SkipsPollsWhileRunUnfinished(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	txm := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txm
+	mockAggregatorCalls(txm, 10000, time.Now())
+
+	feed, feedCleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", "101.5")
+	defer feedCleanup()
+
+	j := newFluxMonitorJob(t, cltest.NewAddress(), feed.URL, 1, time.Hour)
+	require.NoError(t, store.CreateJob(&j))
+
+	clock := cltest.NewTriggerClock(t)
+	fm := services.NewFluxMonitor(store)
+	fm.Clock = clock
+	require.NoError(t, fm.Start())
+	defer fm.Stop()
+	fm.AddJob(j)
+
+	runs := cltest.WaitForRuns(t, j, store, 1)
+	clock.Trigger()
+	clock.Trigger()
+	cltest.WaitForRuns(t, j, store, 1)
+
+	runs[0].Status = models.RunStatusCompleted
+	require.NoError(t, store.SaveJobRun(&runs[0]))
+	clock.Trigger()
+	cltest.WaitForRuns(t, j, store, 2)
+}
+
+func TestFluxMonitor_AddJob_NoAggregatorData(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	txm := mocks.NewMockTxManager(ctrl)
+	store.TxManager = txm
+	txm.EXPECT().CallContract(gomock.Any()).Return([]byte{}, nil).AnyTimes()
+
+	feed, feedCleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", "101.5")
+	defer feedCleanup()
+
+	j := newFluxMonitorJob(t, cltest.NewAddress(), feed.URL, 1, time.Hour)
+	require.NoError(t, store.CreateJob(&j))
+
+	fm := services.NewFluxMonitor(store)
+	fm.Clock = cltest.NeverClock{}
+	require.NoError(t, fm.Start())
+	fm.AddJob(j)
+
+	cltest.WaitForRuns(t, j, store, 0)
+	fm.Stop()
+}
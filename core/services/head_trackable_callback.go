@@ -14,3 +14,4 @@ func (c *headTrackableCallback) Connect(*models.Head) error {
 
 func (c *headTrackableCallback) Disconnect()            {}
 func (c *headTrackableCallback) OnNewHead(*models.Head) {}
+func (c *headTrackableCallback) OnReorg(*models.Reorg)  {}
@@ -2,10 +2,14 @@ package services
 
 import (
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	strpkg "github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
@@ -13,6 +17,29 @@ import (
 	"github.com/smartcontractkit/chainlink/core/utils"
 )
 
+// headWindowSize is the number of recent heads kept to detect chain
+// reorganizations, matching the number of heads persisted in the database.
+const headWindowSize = 100
+
+var (
+	promCurrentHead = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "head_tracker_current_head",
+		Help: "The highest seen head number",
+	})
+	promHeadLag = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "head_tracker_head_lag_seconds",
+		Help: "Seconds between the timestamp of the last received head and the time it was received",
+	})
+	promHeadsReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "head_tracker_heads_received_total",
+		Help: "The total number of heads received from the Ethereum node",
+	})
+	promReorgs = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "head_tracker_reorgs_total",
+		Help: "The total number of chain reorganizations detected",
+	})
+)
+
 // HeadTracker holds and stores the latest block number experienced by this particular node
 // in a thread safe manner. Reconstitutes the last block number from the data
 // store on reboot.
@@ -22,6 +49,7 @@ type HeadTracker struct {
 	headSubscription      models.EthSubscription
 	store                 *strpkg.Store
 	head                  *models.Head
+	window                []models.Head
 	headMutex             sync.RWMutex
 	connected             bool
 	sleeper               utils.Sleeper
@@ -110,7 +138,9 @@ func (ht *HeadTracker) Save(n *models.Head) error {
 	if n.GreaterThan(ht.head) {
 		copy := *n
 		ht.head = &copy
+		ht.appendToWindow(copy)
 		ht.headMutex.Unlock()
+		promCurrentHead.Set(float64(n.Number))
 	} else {
 		ht.headMutex.Unlock()
 		msg := fmt.Sprintf("Cannot save new head confirmation %v because it's equal to or less than current head %v with hash %s", n, ht.head, n.Hash.Hex())
@@ -156,6 +186,15 @@ func (ht *HeadTracker) onNewHead(head *models.Head) {
 	}
 }
 
+func (ht *HeadTracker) onReorg(reorg *models.Reorg) {
+	ht.headMutex.Lock()
+	defer ht.headMutex.Unlock()
+
+	for _, trackable := range ht.callbacks {
+		trackable.OnReorg(reorg)
+	}
+}
+
 func (ht *HeadTracker) listenForNewHeads() {
 	defer ht.listenForNewHeadsWg.Done()
 	defer ht.unsubscribeFromHead()
@@ -210,16 +249,9 @@ func (ht *HeadTracker) receiveHeaders() error {
 				"blockHeight", head.ToInt(),
 				"blockHash", block.Hash(),
 				"hash", head.Hash)
-			if err := ht.Save(head); err != nil {
-				switch err.(type) {
-				case errBlockNotLater:
-					logger.Warn(err)
-				default:
-					logger.Error(err)
-				}
-			} else {
-				ht.onNewHead(head)
-			}
+			promHeadsReceived.Inc()
+			promHeadLag.Set(headLag(block).Seconds())
+			ht.processHead(head)
 		case err, open := <-ht.headSubscription.Err():
 			if open && err != nil {
 				return err
@@ -228,6 +260,159 @@ func (ht *HeadTracker) receiveHeaders() error {
 	}
 }
 
+func (ht *HeadTracker) processHead(head *models.Head) {
+	reorg, err := ht.detectReorg(head)
+	if err != nil {
+		logger.Warnw("Unable to check new head for chain reorganization", "err", err, "blockHeight", head.ToInt())
+	}
+
+	if reorg != nil {
+		logger.Warnw(
+			fmt.Sprintf("Chain reorganization detected at head %v", presenters.FriendlyBigInt(head.ToInt())),
+			"orphanedBlocks", len(reorg.Orphaned),
+			"commonAncestor", reorg.CommonAncestor,
+			"hash", head.Hash)
+		if err := ht.reorganize(reorg); err != nil {
+			logger.Error(err)
+			return
+		}
+		ht.onReorg(reorg)
+		ht.onNewHead(head)
+		return
+	}
+
+	if err := ht.Save(head); err != nil {
+		switch err.(type) {
+		case errBlockNotLater:
+			logger.Warn(err)
+		default:
+			logger.Error(err)
+		}
+	} else {
+		ht.onNewHead(head)
+	}
+}
+
+// detectReorg compares the new head's ancestry with the window of recently
+// tracked heads, and returns the resulting reorganization if the two diverge.
+func (ht *HeadTracker) detectReorg(head *models.Head) (*models.Reorg, error) {
+	ht.headMutex.RLock()
+	window := make([]models.Head, len(ht.window))
+	copy(window, ht.window)
+	ht.headMutex.RUnlock()
+
+	if head.ParentHash == utils.EmptyHash {
+		return nil, nil
+	}
+
+	tracked, ok := headAt(window, head.Number)
+	diverged := ok && tracked.Hash != head.Hash
+	if parent, ok := headAt(window, head.Number-1); ok && parent.Hash != head.ParentHash {
+		diverged = true
+	}
+	if !diverged {
+		return nil, nil
+	}
+
+	ancestor, err := ht.findCommonAncestor(window, head)
+	if err != nil {
+		return nil, err
+	}
+
+	reorg := &models.Reorg{Head: head, CommonAncestor: ancestor}
+	for _, h := range window {
+		if ancestor == nil || h.Number > ancestor.Number {
+			reorg.Orphaned = append(reorg.Orphaned, h)
+		}
+	}
+	return reorg, nil
+}
+
+// findCommonAncestor walks back the new canonical chain from head until it
+// reaches a block in the window, returning nil if the fork is older than the
+// window.
+func (ht *HeadTracker) findCommonAncestor(window []models.Head, head *models.Head) (*models.Head, error) {
+	hash := head.ParentHash
+	for number := head.Number - 1; number >= 0; number-- {
+		tracked, ok := headAt(window, number)
+		if !ok {
+			return nil, nil
+		}
+		if tracked.Hash == hash {
+			return &tracked, nil
+		}
+
+		block, err := ht.store.TxManager.GetBlockByNumber(hexutil.EncodeBig(big.NewInt(number)))
+		if err != nil {
+			return nil, errors.Wrap(err, "TxManager#GetBlockByNumber")
+		}
+		if block.Hash() != hash {
+			return nil, fmt.Errorf("canonical block %d has hash %s, expected %s", number, block.Hash().Hex(), hash.Hex())
+		}
+		hash = block.ParentHash
+	}
+	return nil, nil
+}
+
+// reorganize replaces the orphaned heads with the new head, both in memory and
+// in the database.
+func (ht *HeadTracker) reorganize(reorg *models.Reorg) error {
+	ht.headMutex.Lock()
+	defer ht.headMutex.Unlock()
+
+	forkNumber := reorg.Head.Number - 1
+	if reorg.CommonAncestor != nil {
+		forkNumber = reorg.CommonAncestor.Number
+	} else if len(ht.window) > 0 {
+		forkNumber = ht.window[0].Number - 1
+	}
+
+	if err := ht.store.DeleteHeadsAfter(forkNumber); err != nil {
+		return errors.Wrap(err, "HeadTracker#reorganize DeleteHeadsAfter")
+	}
+	if err := ht.store.CreateHead(reorg.Head); err != nil {
+		return errors.Wrap(err, "HeadTracker#reorganize CreateHead")
+	}
+
+	canonical := ht.window[:0]
+	for _, h := range ht.window {
+		if h.Number <= forkNumber {
+			canonical = append(canonical, h)
+		}
+	}
+	ht.window = canonical
+	ht.appendToWindow(*reorg.Head)
+
+	copy := *reorg.Head
+	ht.head = &copy
+	promReorgs.Inc()
+	promCurrentHead.Set(float64(copy.Number))
+	return nil
+}
+
+// headLag returns how long ago the block was mined, according to its
+// timestamp.
+func headLag(block models.BlockHeader) time.Duration {
+	mined := time.Unix(block.Time.ToInt().Int64(), 0)
+	return time.Since(mined)
+}
+
+func (ht *HeadTracker) appendToWindow(head models.Head) {
+	ht.window = append(ht.window, head)
+	if len(ht.window) > headWindowSize {
+		ht.window = ht.window[len(ht.window)-headWindowSize:]
+	}
+}
+
+func headAt(window []models.Head, number int64) (models.Head, bool) {
+	for i := len(window) - 1; i >= 0; i-- {
+		if window[i].Number == number {
+			return window[i], true
+		}
+	}
+	return models.Head{}, false
+}
+
 func (ht *HeadTracker) subscribeToHead() error {
 	ht.headMutex.Lock()
 	defer ht.headMutex.Unlock()
@@ -270,6 +455,15 @@ func (ht *HeadTracker) updateHeadFromDb() error {
 		return err
 	}
 	ht.head = number
+	if number != nil {
+		promCurrentHead.Set(float64(number.Number))
+	}
+
+	window, err := ht.store.LastHeads(headWindowSize)
+	if err != nil {
+		return err
+	}
+	ht.window = window
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/golang/mock/gomock"
 	"github.com/onsi/gomega"
@@ -220,3 +221,87 @@ func TestHeadTracker_StartConnectsFromLastSavedHeader(t *testing.T) {
 	g.Eventually(func() *big.Int { return ht.Head().ToInt() }).Should(gomega.Equal(currentBN))
 	assert.NoError(t, ht.Stop())
 }
+
+func TestHeadTracker_DetectsReorg(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+	headers := make(chan models.BlockHeader)
+	eth.RegisterSubscription("newHeads", headers)
+	eth.Register("eth_chainId", store.Config.ChainID())
+
+	h1 := models.Head{Number: 1, Hash: cltest.NewHash()}
+	h2 := models.Head{Number: 2, Hash: cltest.NewHash(), ParentHash: h1.Hash}
+	h3 := models.Head{Number: 3, Hash: cltest.NewHash(), ParentHash: h2.Hash}
+	for _, h := range []models.Head{h1, h2, h3} {
+		head := h
+		require.NoError(t, store.CreateHead(&head))
+	}
+
+	checker := &cltest.MockHeadTrackable{}
+	ht := services.NewHeadTracker(store, []strpkg.HeadTrackable{checker}, cltest.NeverSleeper{})
+	require.NoError(t, ht.Start())
+	defer ht.Stop()
+	g.Eventually(func() int32 { return checker.ConnectedCount() }).Should(gomega.Equal(int32(1)))
+
+	// A competing block at the current height replaces the tracked head
+	uncle3 := models.BlockHeader{Number: cltest.BigHexInt(3), ParentHash: h2.Hash, GethHash: cltest.NewHash()}
+	headers <- uncle3
+	g.Eventually(func() int32 { return checker.OnReorgCount() }).Should(gomega.Equal(int32(1)))
+	g.Eventually(func() int32 { return checker.OnNewHeadCount() }).Should(gomega.Equal(int32(1)))
+	assert.Equal(t, uncle3.Hash(), ht.Head().Hash)
+
+	// A block extending the new canonical chain is not a reorg
+	h4 := models.BlockHeader{Number: cltest.BigHexInt(4), ParentHash: uncle3.Hash(), GethHash: cltest.NewHash()}
+	headers <- h4
+	g.Eventually(func() int32 { return checker.OnNewHeadCount() }).Should(gomega.Equal(int32(2)))
+	assert.Equal(t, int32(1), checker.OnReorgCount())
+
+	heads, err := store.LastHeads(10)
+	require.NoError(t, err)
+	require.Len(t, heads, 4)
+	assert.Equal(t, []common.Hash{h1.Hash, h2.Hash, uncle3.Hash(), h4.Hash()},
+		[]common.Hash{heads[0].Hash, heads[1].Hash, heads[2].Hash, heads[3].Hash})
+}
+
+func TestHeadTracker_DetectsReorg_WalksBackToCommonAncestor(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+	eth.Register("eth_chainId", store.Config.ChainID())
+
+	h1 := models.Head{Number: 1, Hash: cltest.NewHash()}
+	h2 := models.Head{Number: 2, Hash: cltest.NewHash(), ParentHash: h1.Hash}
+	h3 := models.Head{Number: 3, Hash: cltest.NewHash(), ParentHash: h2.Hash}
+	for _, h := range []models.Head{h1, h2, h3} {
+		head := h
+		require.NoError(t, store.CreateHead(&head))
+	}
+
+	checker := &cltest.MockHeadTrackable{}
+	ht := services.NewHeadTracker(store, []strpkg.HeadTrackable{checker}, cltest.NeverSleeper{})
+	require.NoError(t, ht.Start())
+	defer ht.Stop()
+
+	fork2 := models.BlockHeader{Number: cltest.BigHexInt(2), ParentHash: h1.Hash, GethHash: cltest.NewHash()}
+	fork3 := models.BlockHeader{Number: cltest.BigHexInt(3), ParentHash: fork2.Hash(), GethHash: cltest.NewHash()}
+	fork4 := models.BlockHeader{Number: cltest.BigHexInt(4), ParentHash: fork3.Hash(), GethHash: cltest.NewHash()}
+	eth.Register("eth_getBlockByNumber", fork3)
+	eth.Register("eth_getBlockByNumber", fork2)
+
+	var reorg *models.Reorg
+	checker.ReorgCallback = func(r *models.Reorg) { reorg = r }
+	services.ExportedProcessHead(ht, fork4.ToHead())
+
+	eth.EventuallyAllCalled(t)
+	require.NotNil(t, reorg)
+	require.NotNil(t, reorg.CommonAncestor)
+	assert.Equal(t, h1.Hash, reorg.CommonAncestor.Hash)
+	assert.Equal(t, []common.Hash{h2.Hash, h3.Hash}, reorg.OrphanedHashes())
+	assert.Equal(t, fork4.Hash(), ht.Head().Hash)
+}
@@ -1,23 +1,49 @@
 package services
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/assets"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"go.uber.org/multierr"
 )
 
+var (
+	promTaskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "task_execution_duration_seconds",
+		Help:    "How long each adapter took to perform a task",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"task_type"})
+	promTaskRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "task_runs_total",
+		Help: "The total number of task runs performed, by the status they ended in",
+	}, []string{"job_spec_id", "task_type", "status"})
+	promQueuedRuns = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "job_runner_queued_runs",
+		Help: "The number of runs waiting for a JobRunner worker",
+	})
+	promActiveRuns = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "job_runner_active_runs",
+		Help: "The number of runs being processed by JobRunner workers",
+	})
+)
+
 // JobRunner safely handles coordinating job runs.
 type JobRunner interface {
 	Start() error
 	Stop()
 	resumeRunsSinceLastShutdown() error
-	channelForRun(*models.ID) chan<- struct{}
+	enqueue(*models.ID) error
 	workerCount() int
 }
 
@@ -26,19 +52,28 @@ type jobRunner struct {
 	done                 chan struct{}
 	bootMutex            sync.Mutex
 	store                *store.Store
-	workerMutex          sync.RWMutex
-	workers              map[string]chan struct{}
+	queueMutex           sync.Mutex
+	queueCond            *sync.Cond
+	stopping             bool
+	queue                []models.QueuedRun
+	active               map[string]models.QueuedRun
+	activePerJob         map[string]uint64
+	requeue              map[string]bool
 	workersWg            sync.WaitGroup
 	demultiplexStopperWg sync.WaitGroup
 }
 
 // NewJobRunner initializes a JobRunner.
 func NewJobRunner(str *store.Store) JobRunner {
-	return &jobRunner{
+	rm := &jobRunner{
 		// Unscoped allows the processing of runs that are soft deleted asynchronously
-		store:   str.Unscoped(),
-		workers: make(map[string]chan struct{}),
+		store:        str.Unscoped(),
+		active:       make(map[string]models.QueuedRun),
+		activePerJob: make(map[string]uint64),
+		requeue:      make(map[string]bool),
 	}
+	rm.queueCond = sync.NewCond(&rm.queueMutex)
+	return rm
 }
 
 // Start reinitializes runs and starts the execution of the store's runs.
@@ -49,9 +84,26 @@ func (rm *jobRunner) Start() error {
 	if rm.started {
 		return errors.New("JobRunner already started")
 	}
+
+	queued, err := rm.store.AllQueuedRuns()
+	if err != nil {
+		return fmt.Errorf("JobRunner unable to load queued runs: %v", err)
+	}
+
+	rm.queueMutex.Lock()
+	rm.queue = queued
+	rm.stopping = false
+	rm.queueMutex.Unlock()
+	promQueuedRuns.Set(float64(len(queued)))
+
 	rm.done = make(chan struct{})
 	rm.started = true
 
+	for i := uint64(0); i < rm.maxWorkers(); i++ {
+		rm.workersWg.Add(1)
+		go rm.workerLoop()
+	}
+
 	var starterWg sync.WaitGroup
 	starterWg.Add(1)
 	go rm.demultiplexRuns(&starterWg)
@@ -61,7 +113,8 @@ func (rm *jobRunner) Start() error {
 	return nil
 }
 
-// Stop closes all open worker channels.
+// Stop lets the workers finish the runs they are processing and stops them.
+// Queued runs remain in the database to be picked up on the next Start.
 func (rm *jobRunner) Stop() {
 	rm.bootMutex.Lock()
 	defer rm.bootMutex.Unlock()
@@ -70,6 +123,12 @@ func (rm *jobRunner) Stop() {
 		return
 	}
 	close(rm.done)
+
+	rm.queueMutex.Lock()
+	rm.stopping = true
+	rm.queueCond.Broadcast()
+	rm.queueMutex.Unlock()
+
 	rm.started = false
 	rm.demultiplexStopperWg.Wait()
 }
@@ -95,11 +154,15 @@ func (rm *jobRunner) resumeRunsSinceLastShutdown() error {
 			if err := QueueSleepingTask(run, rm.store.Unscoped()); err != nil {
 				logger.Errorw("Error resuming sleeping job", "error", err)
 			}
+		} else if run.Status == models.RunStatusPendingRetry {
+			if err := QueueRetryingTask(run, rm.store.Unscoped()); err != nil {
+				logger.Errorw("Error resuming retrying job", "error", err)
+			}
 		} else {
 			merr = multierr.Append(merr, rm.store.RunChannel.Send(run.ID))
 		}
 
-	}, models.RunStatusInProgress, models.RunStatusPendingSleep)
+	}, models.RunStatusInProgress, models.RunStatusPendingSleep, models.RunStatusPendingRetry)
 
 	if err != nil {
 		return err
@@ -122,71 +185,186 @@ func (rm *jobRunner) demultiplexRuns(starterWg *sync.WaitGroup) {
 				logger.Panic("RunChannel closed before JobRunner, can no longer demultiplexing job runs")
 				return
 			}
-			rm.channelForRun(rr.ID) <- struct{}{}
+			if err := rm.enqueue(rr.ID); err != nil {
+				logger.Errorw(fmt.Sprint("Error queueing run ", rr.ID), "error", err)
+			}
 		}
 	}
 }
 
-func (rm *jobRunner) channelForRun(runID *models.ID) chan<- struct{} {
-	rm.workerMutex.Lock()
-	defer rm.workerMutex.Unlock()
+// enqueue persists the run in the work queue for the next free worker. A run
+// that is already being processed is queued again once its worker is done.
+func (rm *jobRunner) enqueue(runID *models.ID) error {
+	run, err := rm.store.FindJobRun(runID)
+	if err != nil {
+		return err
+	}
+	qr := models.NewQueuedRun(run)
 
-	workerChannel, present := rm.workers[runID.String()]
-	if !present {
-		workerChannel = make(chan struct{}, 1)
-		rm.workers[runID.String()] = workerChannel
-		rm.workersWg.Add(1)
+	rm.queueMutex.Lock()
+	defer rm.queueMutex.Unlock()
 
-		go func() {
-			rm.workerLoop(runID, workerChannel)
+	if err := rm.store.CreateQueuedRun(&qr); err != nil {
+		return err
+	}
 
-			rm.workerMutex.Lock()
-			delete(rm.workers, runID.String())
-			rm.workersWg.Done()
-			rm.workerMutex.Unlock()
+	key := runID.String()
+	if _, ok := rm.active[key]; ok {
+		rm.requeue[key] = true
+		return nil
+	}
+	for _, queued := range rm.queue {
+		if queued.JobRunID.String() == key {
+			return nil
+		}
+	}
 
-			logger.Debug("Worker finished for ", runID)
-		}()
+	rm.queue = append(rm.queue, qr)
+	promQueuedRuns.Set(float64(len(rm.queue)))
+	rm.queueCond.Signal()
+	return nil
+}
+
+func (rm *jobRunner) workerLoop() {
+	defer rm.workersWg.Done()
+	for {
+		qr, ok := rm.take()
+		if !ok {
+			return
+		}
+
+		rm.process(qr.JobRunID)
+		rm.release(qr)
 	}
-	return workerChannel
 }
 
-func (rm *jobRunner) workerLoop(runID *models.ID, workerChannel chan struct{}) {
+func (rm *jobRunner) process(runID *models.ID) {
+	run, err := rm.store.FindJobRun(runID)
+	if err != nil {
+		logger.Errorw(fmt.Sprint("Error finding run ", runID), run.ForLogger("error", err)...)
+		return
+	}
+
+	if err := executeRun(&run, rm.store); errors.Cause(err) == orm.ErrorRunCancelled {
+		logger.Infow("Run was cancelled while it was being processed", run.ForLogger()...)
+		return
+	} else if err != nil {
+		logger.Errorw(fmt.Sprint("Error executing run ", runID), run.ForLogger("error", err)...)
+		return
+	}
+
+	if run.Status.Finished() {
+		logger.Debugw("All tasks complete for run", "run", run.ID.String())
+	}
+}
+
+// take blocks until a queued run may be processed, and marks it active. It
+// returns false once the JobRunner is stopping.
+func (rm *jobRunner) take() (models.QueuedRun, bool) {
+	rm.queueMutex.Lock()
+	defer rm.queueMutex.Unlock()
+
 	for {
-		select {
-		case <-workerChannel:
-			run, err := rm.store.FindJobRun(runID)
-			if err != nil {
-				logger.Errorw(fmt.Sprint("Error finding run ", runID), run.ForLogger("error", err)...)
-			}
+		if rm.stopping {
+			return models.QueuedRun{}, false
+		}
 
-			if err := executeRun(&run, rm.store); err != nil {
-				logger.Errorw(fmt.Sprint("Error executing run ", runID), run.ForLogger("error", err)...)
-				return
-			}
+		if i := rm.next(); i >= 0 {
+			qr := rm.queue[i]
+			rm.queue = append(rm.queue[:i], rm.queue[i+1:]...)
+			rm.active[qr.JobRunID.String()] = qr
+			rm.activePerJob[qr.JobSpecID.String()]++
+			promQueuedRuns.Set(float64(len(rm.queue)))
+			promActiveRuns.Set(float64(len(rm.active)))
+			return qr, true
+		}
+		rm.queueCond.Wait()
+	}
+}
 
-			if run.Status.Finished() {
-				logger.Debugw("All tasks complete for run", "run", run.ID.String())
-				return
-			}
+// next returns the index of the highest priority queued run whose job is
+// below its concurrency limit, or -1 if there is none.
+func (rm *jobRunner) next() int {
+	perJobLimit := rm.store.Config.MaxConcurrentRunsPerJob()
+	priority := rm.store.Config.RunQueuePriority()
 
-		case <-rm.done:
-			logger.Debug("JobRunner worker loop for ", runID.String(), " finished")
-			return
+	best := -1
+	for i, qr := range rm.queue {
+		if perJobLimit > 0 && rm.activePerJob[qr.JobSpecID.String()] >= perJobLimit {
+			continue
+		}
+		if best < 0 || higherPriority(qr, rm.queue[best], priority) {
+			best = i
 		}
 	}
+	return best
+}
+
+// release removes the run from the active set, and from the queue unless it
+// was triggered again while it was being processed.
+func (rm *jobRunner) release(qr models.QueuedRun) {
+	rm.queueMutex.Lock()
+	defer rm.queueMutex.Unlock()
+
+	runKey, jobKey := qr.JobRunID.String(), qr.JobSpecID.String()
+	delete(rm.active, runKey)
+	if rm.activePerJob[jobKey]--; rm.activePerJob[jobKey] == 0 {
+		delete(rm.activePerJob, jobKey)
+	}
+
+	if rm.requeue[runKey] {
+		delete(rm.requeue, runKey)
+		rm.queue = append(rm.queue, qr)
+	} else if err := rm.store.DeleteQueuedRun(qr.JobRunID); err != nil {
+		logger.Errorw(fmt.Sprint("Error removing run from queue ", runKey), "error", err)
+	}
+
+	promQueuedRuns.Set(float64(len(rm.queue)))
+	promActiveRuns.Set(float64(len(rm.active)))
+	rm.queueCond.Broadcast()
+}
+
+func (rm *jobRunner) maxWorkers() uint64 {
+	if workers := rm.store.Config.MaxConcurrentRuns(); workers > 0 {
+		return workers
+	}
+	return 1
 }
 
 func (rm *jobRunner) workerCount() int {
-	rm.workerMutex.RLock()
-	defer rm.workerMutex.RUnlock()
+	rm.queueMutex.Lock()
+	defer rm.queueMutex.Unlock()
 
-	return len(rm.workers)
+	return len(rm.active)
 }
 
-func prepareTaskInput(run *models.JobRun, input models.JSON) (models.JSON, error) {
+func higherPriority(a, b models.QueuedRun, priority orm.RunQueuePriority) bool {
+	if priority == orm.RunQueuePriorityPayment {
+		if cmp := linkOrZero(a.Payment).Cmp(linkOrZero(b.Payment)); cmp != 0 {
+			return cmp > 0
+		}
+	}
+	return a.RunCreatedAt.Before(b.RunCreatedAt)
+}
+
+func linkOrZero(l *assets.Link) *assets.Link {
+	if l == nil {
+		return assets.NewLink(0)
+	}
+	return l
+}
+
+func prepareTaskInput(run *models.JobRun, currentTaskRun *models.TaskRun) (models.JSON, error) {
 	var err error
-	if input, err = run.Result.Data.Merge(input); err != nil {
+	previous := run.Result.Data
+	if run.IsTaskGraph() {
+		if previous, err = taskGraphInput(run, currentTaskRun); err != nil {
+			return models.JSON{}, err
+		}
+	}
+
+	input, err := previous.Merge(currentTaskRun.Result.Data)
+	if err != nil {
 		return models.JSON{}, err
 	}
 
@@ -197,35 +375,67 @@ func prepareTaskInput(run *models.JobRun, input models.JSON) (models.JSON, error
 }
 
 func executeTask(run *models.JobRun, currentTaskRun *models.TaskRun, store *store.Store) models.RunResult {
+	task, err := prepareTask(run, currentTaskRun, store)
+	if err != nil {
+		currentTaskRun.Result.SetError(err)
+		return currentTaskRun.Result
+	}
+	return task.perform(store)
+}
+
+// preparedTask is a task ready to be performed, with its own copy of
+// everything it needs from the run, so that tasks can be performed
+// concurrently.
+type preparedTask struct {
+	adapter   *adapters.PipelineAdapter
+	taskType  models.TaskType
+	taskRunID *models.ID
+	jobSpecID *models.ID
+	input     models.RunResult
+}
+
+// prepareTask resolves the task's adapter and input, setting the input on
+// the task run.
+func prepareTask(run *models.JobRun, currentTaskRun *models.TaskRun, store *store.Store) (preparedTask, error) {
 	taskCopy := currentTaskRun.TaskSpec // deliberately copied to keep mutations local
 
 	var err error
 	if taskCopy.Params, err = taskCopy.Params.Merge(run.Overrides); err != nil {
-		currentTaskRun.Result.SetError(err)
-		return currentTaskRun.Result
+		return preparedTask{}, err
 	}
 
 	adapter, err := adapters.For(taskCopy, store)
 	if err != nil {
-		currentTaskRun.Result.SetError(err)
-		return currentTaskRun.Result
+		return preparedTask{}, err
 	}
 
-	logger.Infow(fmt.Sprintf("Processing task %s", taskCopy.Type), []interface{}{"task", currentTaskRun.ID.String()}...)
-
-	data, err := prepareTaskInput(run, currentTaskRun.Result.Data)
+	data, err := prepareTaskInput(run, currentTaskRun)
 	if err != nil {
-		currentTaskRun.Result.SetError(err)
-		return currentTaskRun.Result
+		return preparedTask{}, err
 	}
 
 	currentTaskRun.Result.CachedJobRunID = run.ID
 	currentTaskRun.Result.Data = data
-	result := adapter.Perform(currentTaskRun.Result, store)
-	result.ID = currentTaskRun.Result.ID
+	return preparedTask{
+		adapter:   adapter,
+		taskType:  taskCopy.Type,
+		taskRunID: currentTaskRun.ID,
+		jobSpecID: run.JobSpecID,
+		input:     currentTaskRun.Result,
+	}, nil
+}
+
+func (task preparedTask) perform(store *store.Store) models.RunResult {
+	logger.Infow(fmt.Sprintf("Processing task %s", task.taskType), []interface{}{"task", task.taskRunID.String()}...)
+
+	start := time.Now()
+	result := task.adapter.Perform(task.input, store)
+	promTaskDuration.WithLabelValues(task.taskType.String()).Observe(time.Since(start).Seconds())
+	promTaskRuns.WithLabelValues(task.jobSpecID.String(), task.taskType.String(), string(result.Status)).Inc()
+	result.ID = task.input.ID
 
-	logger.Infow(fmt.Sprintf("Finished processing task %s", taskCopy.Type), []interface{}{
-		"task", currentTaskRun.ID,
+	logger.Infow(fmt.Sprintf("Finished processing task %s", task.taskType), []interface{}{
+		"task", task.taskRunID,
 		"result", result.Status,
 		"result_data", result.Data,
 	}...)
@@ -240,14 +450,45 @@ func executeRun(run *models.JobRun, store *store.Store) error {
 		return fmt.Errorf("Run triggered in non runnable state %s", run.Status)
 	}
 
+	if run.IsTaskGraph() {
+		return executeTaskGraph(run, store)
+	}
+
 	currentTaskRun := run.NextTaskRun()
 	if currentTaskRun == nil {
 		return errors.New("Run triggered with no remaining tasks")
 	}
 
+	if expiredFulfillment(run, currentTaskRun, store) {
+		return cancelExpiredRun(run, store)
+	}
+	if fulfillment(currentTaskRun) && cancelledSinceLoaded(run, store) {
+		return orm.ErrorRunCancelled
+	}
+
+	if currentTaskRun.Status.Unstarted() {
+		currentTaskRun.Attempts++
+	}
+
 	result := executeTask(run, currentTaskRun, store)
 
+	if markForRetry(run, currentTaskRun, result, store) {
+		run.Status = models.RunStatusPendingRetry
+		if err := store.SaveJobRun(run); err != nil {
+			return err
+		}
+		return QueueRetryingTask(run, store)
+	} else if result.HasError() {
+		currentTaskRun.LastError = result.ErrorMessage
+	}
+
 	currentTaskRun.ApplyResult(result)
+	if currentTaskRun.Status.Completed() && currentTaskRun.TaskSpec.Type == adapters.TaskTypeConditional {
+		if err := skipConditionalTasks(run, currentTaskRun); err != nil {
+			result.SetError(err)
+			currentTaskRun.ApplyResult(result)
+		}
+	}
 	run.ApplyResult(result)
 
 	if currentTaskRun.Status.PendingSleep() {
@@ -270,3 +511,241 @@ func executeRun(run *models.JobRun, store *store.Store) error {
 
 	return nil
 }
+
+// markForRetry marks the task as pending a retry if its result is an error
+// that the task's retry policy allows attempting again.
+func markForRetry(run *models.JobRun, taskRun *models.TaskRun, result models.RunResult, store *store.Store) bool {
+	retry := taskRun.TaskSpec.Retry
+	if !result.HasError() || retry == nil || !retry.ShouldRetry(taskRun.Attempts, result.ErrorKind) {
+		return false
+	}
+
+	retryAt := store.Clock.Now().Add(retry.Delay(taskRun.Attempts))
+	logger.Infow("Task failed, scheduling retry", run.ForLogger(
+		"task", taskRun.ID.String(),
+		"attempt", taskRun.Attempts,
+		"error", result.Error(),
+		"retry_at", retryAt,
+	)...)
+	taskRun.MarkPendingRetry(result.Error(), retryAt)
+	return true
+}
+
+// executeTaskGraph concurrently executes every task of a task graph whose
+// dependencies have completed. Sleeping, retrying and bridge tasks are
+// awaited one at a time, and no further tasks start while the run waits on
+// one of them.
+func executeTaskGraph(run *models.JobRun, store *store.Store) error {
+	if waiting := awaitedTaskRun(run); waiting != nil {
+		return awaitTaskRun(run, waiting, store)
+	}
+
+	ready := readyTaskRuns(run)
+	if len(ready) == 0 {
+		return errors.New("Run triggered with no tasks ready to run")
+	}
+
+	for _, taskRun := range ready {
+		if expiredFulfillment(run, taskRun, store) {
+			return cancelExpiredRun(run, store)
+		}
+		if fulfillment(taskRun) && cancelledSinceLoaded(run, store) {
+			return orm.ErrorRunCancelled
+		}
+		validateMinimumConfirmations(run, taskRun, run.ObservedHeight, store)
+		if !run.Status.Runnable() {
+			return updateAndTrigger(run, store)
+		}
+	}
+
+	// Every task is prepared before any is performed, so that the tasks
+	// performed concurrently share nothing with the run or each other.
+	results := make([]models.RunResult, len(ready))
+	tasks := make([]*preparedTask, len(ready))
+	for i, taskRun := range ready {
+		if taskRun.Status.Unstarted() {
+			taskRun.Attempts++
+		}
+		task, err := prepareTask(run, taskRun, store)
+		if err != nil {
+			taskRun.Result.SetError(err)
+			results[i] = taskRun.Result
+			continue
+		}
+		tasks[i] = &task
+	}
+
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		if task == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, task *preparedTask) {
+			defer wg.Done()
+			results[i] = task.perform(store)
+		}(i, task)
+	}
+	wg.Wait()
+
+	var blocked *models.RunResult
+	for i, taskRun := range ready {
+		result := results[i]
+		if markForRetry(run, taskRun, result, store) {
+			continue
+		} else if result.HasError() {
+			taskRun.LastError = result.ErrorMessage
+		} else if result.Status.Unstarted() {
+			return fmt.Errorf("run %s task %s cannot return a status of empty string or Unstarted", run.ID.String(), taskRun.TaskSpec.Type)
+		}
+
+		taskRun.ApplyResult(result)
+		if result.Status.Completed() {
+			run.ApplyResult(result)
+		} else if blocked == nil || (result.HasError() && !blocked.HasError()) {
+			blocked = &results[i]
+		}
+	}
+
+	if blocked != nil {
+		run.ApplyResult(*blocked)
+	}
+	if waiting := awaitedTaskRun(run); waiting != nil && !run.Status.Errored() {
+		return awaitTaskRun(run, waiting, store)
+	}
+
+	if err := updateAndTrigger(run, store); err != nil {
+		return err
+	}
+	logger.Infow("Run finished processing", run.ForLogger()...)
+
+	return nil
+}
+
+// readyTaskRuns returns the tasks of a task graph that can be executed, which
+// are those yet to start or to be confirmed whose dependencies have all
+// completed.
+func readyTaskRuns(run *models.JobRun) []*models.TaskRun {
+	var ready []*models.TaskRun
+	for i := range run.TaskRuns {
+		taskRun := &run.TaskRuns[i]
+		status := taskRun.Status
+		if !status.Unstarted() && !status.PendingConfirmations() && !status.PendingConnection() {
+			continue
+		}
+
+		completed := true
+		for _, name := range taskRun.TaskSpec.DependsOn {
+			if dependency := run.TaskRunNamed(name); dependency == nil || !dependency.Status.Completed() {
+				completed = false
+			}
+		}
+		if completed {
+			ready = append(ready, taskRun)
+		}
+	}
+	return ready
+}
+
+// awaitedTaskRun returns the first task of the run waiting on a timer or an
+// external adapter, if any.
+func awaitedTaskRun(run *models.JobRun) *models.TaskRun {
+	for i := range run.TaskRuns {
+		status := run.TaskRuns[i].Status
+		if status.PendingSleep() || status.PendingRetry() || status.PendingBridge() {
+			return &run.TaskRuns[i]
+		}
+	}
+	return nil
+}
+
+// awaitTaskRun leaves the run pending on the given task until it is resumed.
+func awaitTaskRun(run *models.JobRun, taskRun *models.TaskRun, store *store.Store) error {
+	logger.Debugw("Run waiting on task", run.ForLogger("task", taskRun.ID.String(), "state", taskRun.Status)...)
+	run.Status = taskRun.Status
+	run.Result.Status = taskRun.Status
+	if err := store.SaveJobRun(run); err != nil {
+		return err
+	}
+
+	switch {
+	case taskRun.Status.PendingSleep():
+		return QueueSleepingTask(run, store)
+	case taskRun.Status.PendingRetry():
+		return QueueRetryingTask(run, store)
+	}
+	return nil
+}
+
+// taskGraphInput returns the outputs of the tasks the given task depends on,
+// keyed by their names. A task with a single dependency also receives its
+// output as the result, just like the tasks of a job without dependencies.
+func taskGraphInput(run *models.JobRun, taskRun *models.TaskRun) (models.JSON, error) {
+	var input models.JSON
+	var err error
+	for _, name := range taskRun.TaskSpec.DependsOn {
+		var output json.RawMessage
+		if dependency := run.TaskRunNamed(name); dependency != nil && dependency.Result.Result().Exists() {
+			output = json.RawMessage(dependency.Result.Result().Raw)
+		}
+		if input, err = input.Add(name, output); err != nil {
+			return input, err
+		}
+		if len(taskRun.TaskSpec.DependsOn) == 1 {
+			if input, err = input.Add("result", output); err != nil {
+				return input, err
+			}
+		}
+	}
+	return input, nil
+}
+
+// skipConditionalTasks marks the tasks passed over by the branch taken by a
+// completed conditional task as skipped.
+func skipConditionalTasks(run *models.JobRun, conditional *models.TaskRun) error {
+	var outcome adapters.ConditionalResult
+	if err := json.Unmarshal([]byte(conditional.Result.Get("conditional").Raw), &outcome); err != nil {
+		return fmt.Errorf("reading conditional outcome: %v", err)
+	}
+
+	index := -1
+	for i := range run.TaskRuns {
+		if run.TaskRuns[i].ID.String() == conditional.ID.String() {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("conditional task %s not found in run %s", conditional.ID.String(), run.ID.String())
+	}
+
+	end := index + 1
+	switch outcome.Action {
+	case adapters.ConditionalHalt:
+		end = len(run.TaskRuns)
+	case adapters.ConditionalSkip:
+		end = index + 1 + outcome.Count
+	case adapters.ConditionalJump:
+		end = outcome.To
+	}
+	if end > len(run.TaskRuns) {
+		end = len(run.TaskRuns)
+	}
+
+	skipped := 0
+	skip := func(i int) {
+		if i > index && i < len(run.TaskRuns) && run.TaskRuns[i].Status.Unstarted() {
+			run.TaskRuns[i].MarkSkipped()
+			skipped++
+		}
+	}
+	for i := index + 1; i < end; i++ {
+		skip(i)
+	}
+	for _, i := range outcome.Skip {
+		skip(i)
+	}
+	if skipped > 0 {
+		logger.Debugw(fmt.Sprintf("Conditional task took %s branch, skipped %d tasks", outcome.Action, skipped), run.ForLogger()...)
+	}
+	return nil
+}
@@ -2,15 +2,19 @@ package services_test
 
 import (
 	"fmt"
+	"net/http"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/store/assets"
 
 	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -101,78 +105,470 @@ func TestJobRunner_executeRun_correctlyAddsLinkEarnings(t *testing.T) {
 	assert.Equal(t, assets.NewLink(1), actual)
 }
 
-func TestJobRunner_ChannelForRun_equalityBetweenRuns(t *testing.T) {
-	t.Parallel()
+func TestJobRunner_executeRun_cancelsExpiredRequest(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	clock := cltest.UseSettableClock(store)
+	now := time.Now()
+	clock.SetTime(now)
+
+	j := cltest.NewJobWithRunLogInitiator()
+	j.Tasks = []models.TaskSpec{
+		cltest.NewTask(t, "noop"),
+		cltest.NewTask(t, "ethtx"),
+	}
+	require.NoError(t, store.CreateJob(&j))
+
+	run := j.NewRun(j.Initiators[0])
+	requestID := cltest.NewHash().Hex()
+	expiration := now.Add(-time.Second)
+	run.RunRequest = models.RunRequest{RequestID: &requestID, Expiration: &expiration}
+	require.NoError(t, store.CreateJobRun(&run))
 
+	require.NoError(t, services.ExportedExecuteRun(&run, store))
+	require.NoError(t, services.ExportedExecuteRun(&run, store))
+
+	run, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCancelled, run.Status)
+	assert.True(t, run.FinishedAt.Valid)
+	assert.Equal(t, models.RunStatusCompleted, run.TaskRuns[0].Status)
+	assert.Equal(t, models.RunStatusCancelled, run.TaskRuns[1].Status)
+	assert.Contains(t, run.Result.Error(), "Request "+requestID+" expired at")
+}
+
+func TestJobRunner_executeRun_cancelledWhileActive(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
-	rm, cleanup := cltest.NewJobRunner(store)
+
+	j := cltest.NewJobWithRunLogInitiator()
+	j.Tasks = []models.TaskSpec{
+		cltest.NewTask(t, "noop"),
+		cltest.NewTask(t, "ethtx"),
+	}
+	require.NoError(t, store.CreateJob(&j))
+
+	run := j.NewRun(j.Initiators[0])
+	require.NoError(t, store.CreateJobRun(&run))
+
+	active, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	require.NoError(t, services.CancelRun(&run, store, "request cancelled"))
+
+	err = services.ExportedExecuteRun(&active, store)
+	assert.Equal(t, orm.ErrorRunCancelled, errors.Cause(err))
+
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCancelled, run.Status)
+	assert.NotEqual(t, models.RunStatusCompleted, run.TaskRuns[0].Status)
+}
+
+func TestJobRunner_executeRun_cancelledBeforeFulfillment(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
 
-	job := cltest.NewJobWithWebInitiator()
-	initr := job.Initiators[0]
-	run1 := job.NewRun(initr)
-	run2 := job.NewRun(initr)
+	j := cltest.NewJobWithRunLogInitiator()
+	j.Tasks = []models.TaskSpec{
+		cltest.NewTask(t, "noop"),
+		cltest.NewTask(t, "ethtx"),
+	}
+	require.NoError(t, store.CreateJob(&j))
 
-	chan1a := services.ExportedChannelForRun(rm, run1.ID)
-	chan2 := services.ExportedChannelForRun(rm, run2.ID)
-	chan1b := services.ExportedChannelForRun(rm, run1.ID)
+	run := j.NewRun(j.Initiators[0])
+	require.NoError(t, store.CreateJobRun(&run))
+	require.NoError(t, services.ExportedExecuteRun(&run, store))
 
-	assert.NotEqual(t, chan1a, chan2)
-	assert.Equal(t, chan1a, chan1b)
-	assert.NotEqual(t, chan2, chan1b)
+	active, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	require.NoError(t, services.CancelRun(&run, store, "request cancelled"))
+
+	err = services.ExportedExecuteRun(&active, store)
+	assert.Equal(t, orm.ErrorRunCancelled, errors.Cause(err))
+
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCancelled, run.Status)
+	assert.Equal(t, models.RunStatusCompleted, run.TaskRuns[0].Status)
+	assert.NotEqual(t, models.RunStatusCompleted, run.TaskRuns[1].Status)
+
+	var count int
+	require.NoError(t, store.ORM.DB.Model(&models.Tx{}).Count(&count).Error)
+	assert.Zero(t, count)
 }
 
-func TestJobRunner_ChannelForRun_sendAfterClosing(t *testing.T) {
+func TestJobRunner_executeRun_retriesFailedTask(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	clock := cltest.UseSettableClock(store)
+	now := time.Now()
+	clock.SetTime(now)
+
+	mockServer, assertCalled := cltest.NewHTTPMockServer(t, http.StatusServiceUnavailable, "GET", "try again later")
+	defer assertCalled()
+
+	j := models.NewJob()
+	i := models.Initiator{Type: models.InitiatorWeb}
+	j.Initiators = []models.Initiator{i}
+	task := cltest.NewTask(t, "httpget", fmt.Sprintf(`{"get":"%s"}`, mockServer.URL))
+	task.Retry = &models.RetryPolicy{
+		MaxAttempts: 2,
+		Backoff:     models.BackoffExponential,
+		Initial:     models.Duration(time.Minute),
+		RetryOn:     []models.ErrorKind{models.ErrorKind5xx},
+	}
+	j.Tasks = []models.TaskSpec{task}
+	require.NoError(t, store.CreateJob(&j))
+
+	run := j.NewRun(i)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	require.NoError(t, services.ExportedExecuteRun(&run, store))
+	run, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingRetry, run.Status)
+	assert.False(t, run.FinishedAt.Valid)
+	assert.Equal(t, models.RunStatusPendingRetry, run.TaskRuns[0].Status)
+	assert.Equal(t, uint32(1), run.TaskRuns[0].Attempts)
+	assert.Equal(t, "try again later", run.TaskRuns[0].LastError.String)
+	assert.WithinDuration(t, now.Add(time.Minute), run.TaskRuns[0].RetryAt.Time, time.Second)
+	assert.Equal(t, models.RetryPolicy{
+		MaxAttempts: 2,
+		Backoff:     models.BackoffExponential,
+		Initial:     models.Duration(time.Minute),
+		RetryOn:     []models.ErrorKind{models.ErrorKind5xx},
+	}, *run.TaskRuns[0].TaskSpec.Retry)
+
+	clock.SetTime(now.Add(time.Minute))
+	rr, open := <-store.RunChannel.Receive()
+	require.True(t, open)
+	require.Equal(t, run.ID, rr.ID)
+
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusInProgress, run.Status)
+	assert.Equal(t, models.RunStatusUnstarted, run.TaskRuns[0].Status)
+
+	require.NoError(t, services.ExportedExecuteRun(&run, store))
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusErrored, run.Status)
+	assert.Equal(t, uint32(2), run.TaskRuns[0].Attempts)
+	assert.Equal(t, "try again later", run.TaskRuns[0].LastError.String)
+	assert.Equal(t, "try again later", run.TaskRuns[0].Result.ErrorMessage.String)
+}
+
+func TestJobRunner_executeRun_conditionalSkipsTasks(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      string
+		wantStatus  models.RunStatus
+		wantTasks   []models.RunStatus
+		wantExecute int
+	}{
+		{"halt", `{"predicate":"false"}`, models.RunStatusCompleted,
+			[]models.RunStatus{models.RunStatusCompleted, models.RunStatusSkipped, models.RunStatusSkipped, models.RunStatusSkipped}, 1},
+		{"continue", `{"predicate":"true"}`, models.RunStatusCompleted,
+			[]models.RunStatus{models.RunStatusCompleted, models.RunStatusCompleted, models.RunStatusCompleted, models.RunStatusCompleted}, 4},
+		{"skip", `{"predicate":"false","onFalse":{"action":"skip","count":2}}`, models.RunStatusCompleted,
+			[]models.RunStatus{models.RunStatusCompleted, models.RunStatusSkipped, models.RunStatusSkipped, models.RunStatusCompleted}, 2},
+		{"jump", `{"predicate":"true","onTrue":{"action":"jump","to":2}}`, models.RunStatusCompleted,
+			[]models.RunStatus{models.RunStatusCompleted, models.RunStatusSkipped, models.RunStatusCompleted, models.RunStatusCompleted}, 3},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			store, cleanup := cltest.NewStore(t)
+			defer cleanup()
+
+			j := models.NewJob()
+			i := models.Initiator{Type: models.InitiatorWeb}
+			j.Initiators = []models.Initiator{i}
+			j.Tasks = []models.TaskSpec{
+				cltest.NewTask(t, "conditional", test.params),
+				cltest.NewTask(t, "noop"),
+				cltest.NewTask(t, "noop"),
+				cltest.NewTask(t, "noop"),
+			}
+			require.NoError(t, services.ValidateJob(j, store))
+			require.NoError(t, store.CreateJob(&j))
+
+			run := j.NewRun(i)
+			require.NoError(t, store.CreateJobRun(&run))
+
+			executions := 0
+			for run.Status.Runnable() && !run.Status.Finished() {
+				require.NoError(t, services.ExportedExecuteRun(&run, store))
+				executions++
+			}
+			assert.Equal(t, test.wantExecute, executions)
+
+			run, err := store.FindJobRun(run.ID)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantStatus, run.Status)
+			assert.True(t, run.FinishedAt.Valid)
+			for i, status := range test.wantTasks {
+				assert.Equal(t, status, run.TaskRuns[i].Status, "task %d", i)
+			}
+		})
+	}
+}
+
+func TestJobRunner_executeRun_conditionalBranches(t *testing.T) {
+	completed, skipped := models.RunStatusCompleted, models.RunStatusSkipped
+	tests := []struct {
+		name      string
+		predicate string
+		wantTasks []models.RunStatus
+	}{
+		{"true branch", "true", []models.RunStatus{completed, completed, completed, skipped, skipped, completed}},
+		{"false branch", "false", []models.RunStatus{completed, skipped, skipped, completed, completed, completed}},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			store, cleanup := cltest.NewStore(t)
+			defer cleanup()
+
+			// Tasks 1 and 2 only run on the true branch, 3 and 4 only on the
+			// false branch, and 5 on either.
+			j := models.NewJob()
+			i := models.Initiator{Type: models.InitiatorWeb}
+			j.Initiators = []models.Initiator{i}
+			j.Tasks = []models.TaskSpec{
+				cltest.NewTask(t, "conditional", fmt.Sprintf(`{
+					"predicate": "%s",
+					"onTrue": {"action": "branch", "tasks": [1, 2]},
+					"onFalse": {"action": "branch", "tasks": [3, 4]}
+				}`, test.predicate)),
+				cltest.NewTask(t, "noop"),
+				cltest.NewTask(t, "noop"),
+				cltest.NewTask(t, "noop"),
+				cltest.NewTask(t, "noop"),
+				cltest.NewTask(t, "noop"),
+			}
+			require.NoError(t, services.ValidateJob(j, store))
+			require.NoError(t, store.CreateJob(&j))
+
+			run := j.NewRun(i)
+			require.NoError(t, store.CreateJobRun(&run))
+
+			executions := 0
+			for run.Status.Runnable() && !run.Status.Finished() {
+				require.NoError(t, services.ExportedExecuteRun(&run, store))
+				executions++
+			}
+			assert.Equal(t, 4, executions)
+
+			run, err := store.FindJobRun(run.ID)
+			require.NoError(t, err)
+			assert.Equal(t, models.RunStatusCompleted, run.Status)
+			for i, status := range test.wantTasks {
+				assert.Equal(t, status, run.TaskRuns[i].Status, "task %d", i)
+			}
+		})
+	}
+}
+
+func TestJobRunner_executeRun_taskGraph(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	var requests sync.WaitGroup
+	requests.Add(2)
+	fetched := make(chan struct{})
+	go func() {
+		requests.Wait()
+		close(fetched)
+	}()
+	concurrently := func(http.Header, string) {
+		requests.Done()
+		select {
+		case <-fetched:
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "fetches did not run concurrently")
+		}
+	}
+	first, cleanupFirst := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", `{"last":"10.5"}`, concurrently)
+	defer cleanupFirst()
+	second, cleanupSecond := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", `{"last":"11.5"}`, concurrently)
+	defer cleanupSecond()
+
+	j := models.NewJob()
+	i := models.Initiator{Type: models.InitiatorWeb}
+	j.Initiators = []models.Initiator{i}
+	j.Tasks = []models.TaskSpec{
+		cltest.NewTask(t, "httpget", fmt.Sprintf(`{"get":"%s"}`, first.URL)),
+		cltest.NewTask(t, "httpget", fmt.Sprintf(`{"get":"%s"}`, second.URL)),
+		cltest.NewTask(t, "jsonparse", `{"path":["last"]}`),
+		cltest.NewTask(t, "jsonparse", `{"path":["last"]}`),
+		cltest.NewTask(t, "transform", `{"expression":"(number(firstPrice) + number(secondPrice)) / 2"}`),
+	}
+	j.Tasks[0].Name = "first"
+	j.Tasks[1].Name = "second"
+	j.Tasks[2].Name, j.Tasks[2].DependsOn = "firstPrice", models.TaskNames{"first"}
+	j.Tasks[3].Name, j.Tasks[3].DependsOn = "secondPrice", models.TaskNames{"second"}
+	j.Tasks[4].DependsOn = models.TaskNames{"firstPrice", "secondPrice"}
+	require.NoError(t, services.ValidateJob(j, store))
+	require.NoError(t, store.CreateJob(&j))
+
+	run := j.NewRun(i)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	require.NoError(t, services.ExportedExecuteRun(&run, store))
+	assert.Equal(t, models.RunStatusInProgress, run.Status)
+
+	// resume from the database, as after a restart
+	run, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	wantStatuses := []models.RunStatus{models.RunStatusCompleted, models.RunStatusCompleted, "", "", ""}
+	for i, status := range wantStatuses {
+		assert.Equal(t, status, run.TaskRuns[i].Status, "task %d", i)
+	}
+
+	executions := 1
+	for run.Status.Runnable() && !run.Status.Finished() {
+		require.NoError(t, services.ExportedExecuteRun(&run, store))
+		executions++
+	}
+	assert.Equal(t, 3, executions)
+
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCompleted, run.Status)
+	assert.Equal(t, "10.5", run.TaskRuns[2].Result.Result().String())
+	assert.Equal(t, "11.5", run.TaskRuns[3].Result.Result().String())
+	assert.Equal(t, "11", run.TaskRuns[4].Result.Result().String())
+	assert.Equal(t, "11", run.Result.Result().String())
+}
+
+func TestJobRunner_Enqueue_PersistsUntilProcessed(t *testing.T) {
 	t.Parallel()
 
 	s, cleanup := cltest.NewStore(t)
 	defer cleanup()
 	rm, cleanup := cltest.NewJobRunner(s)
 	defer cleanup()
-	assert.NoError(t, rm.Start())
 
 	j := cltest.NewJobWithWebInitiator()
-	assert.NoError(t, s.CreateJob(&j))
-	initr := j.Initiators[0]
-	jr := j.NewRun(initr)
-	assert.NoError(t, s.CreateJobRun(&jr))
+	require.NoError(t, s.CreateJob(&j))
+	jr := j.NewRun(j.Initiators[0])
+	require.NoError(t, s.CreateJobRun(&jr))
 
-	chan1 := services.ExportedChannelForRun(rm, jr.ID)
-	chan1 <- struct{}{}
-	cltest.WaitForJobRunToComplete(t, s, jr)
+	require.NoError(t, services.ExportedEnqueue(rm, jr.ID))
+	require.NoError(t, services.ExportedEnqueue(rm, jr.ID))
+
+	queued, err := s.AllQueuedRuns()
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	assert.Equal(t, jr.ID, queued[0].JobRunID)
+	assert.Equal(t, j.ID, queued[0].JobSpecID)
 
-	gomega.NewGomegaWithT(t).Eventually(func() chan<- struct{} {
-		return services.ExportedChannelForRun(rm, jr.ID)
-	}).Should(gomega.Not(gomega.Equal(chan1))) // eventually deletes the channel
+	require.NoError(t, rm.Start())
+	cltest.WaitForJobRunToComplete(t, s, jr)
 
-	chan2 := services.ExportedChannelForRun(rm, jr.ID)
-	chan2 <- struct{}{} // does not panic
+	gomega.NewGomegaWithT(t).Eventually(func() []models.QueuedRun {
+		queued, err := s.AllQueuedRuns()
+		require.NoError(t, err)
+		return queued
+	}).Should(gomega.BeEmpty())
 }
 
-func TestJobRunner_ChannelForRun_equalityWithoutClosing(t *testing.T) {
+func TestJobRunner_Start_ResumesQueuedRuns(t *testing.T) {
 	t.Parallel()
 
 	s, cleanup := cltest.NewStore(t)
 	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	require.NoError(t, s.CreateJob(&j))
+	jr := j.NewRun(j.Initiators[0])
+	require.NoError(t, s.CreateJobRun(&jr))
+
+	stopped, cleanup := cltest.NewJobRunner(s)
+	defer cleanup()
+	require.NoError(t, stopped.Start())
+	stopped.Stop()
+	require.NoError(t, services.ExportedEnqueue(stopped, jr.ID))
+
 	rm, cleanup := cltest.NewJobRunner(s)
 	defer cleanup()
-	assert.NoError(t, rm.Start())
+	require.NoError(t, rm.Start())
 
-	j := cltest.NewJobWithWebInitiator()
-	j.Tasks = []models.TaskSpec{cltest.NewTask(t, "nooppend")}
-	assert.NoError(t, s.CreateJob(&j))
-	initr := j.Initiators[0]
-	jr := j.NewRun(initr)
-	assert.NoError(t, s.CreateJobRun(&jr))
+	cltest.WaitForJobRunToComplete(t, s, jr)
+}
+
+func TestJobRunner_Take_RespectsPerJobLimit(t *testing.T) {
+	t.Parallel()
 
-	chan1 := services.ExportedChannelForRun(rm, jr.ID)
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+	config.Set("MAX_CONCURRENT_RUNS_PER_JOB", 1)
+	s, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+	rm, cleanup := cltest.NewJobRunner(s)
+	defer cleanup()
 
-	chan1 <- struct{}{}
-	cltest.WaitForJobRunToPendConfirmations(t, s, jr)
+	busyJob := cltest.NewJobWithWebInitiator()
+	require.NoError(t, s.CreateJob(&busyJob))
+	otherJob := cltest.NewJobWithWebInitiator()
+	require.NoError(t, s.CreateJob(&otherJob))
+
+	busyRun1 := busyJob.NewRun(busyJob.Initiators[0])
+	busyRun1.CreatedAt = time.Now().Add(-3 * time.Minute)
+	busyRun2 := busyJob.NewRun(busyJob.Initiators[0])
+	busyRun2.CreatedAt = time.Now().Add(-2 * time.Minute)
+	otherRun := otherJob.NewRun(otherJob.Initiators[0])
+	otherRun.CreatedAt = time.Now().Add(-1 * time.Minute)
+	for _, run := range []*models.JobRun{&busyRun1, &busyRun2, &otherRun} {
+		require.NoError(t, s.CreateJobRun(run))
+		require.NoError(t, services.ExportedEnqueue(rm, run.ID))
+	}
 
-	chan2 := services.ExportedChannelForRun(rm, jr.ID)
-	assert.Equal(t, chan1, chan2)
+	qr, ok := services.ExportedTake(rm)
+	require.True(t, ok)
+	assert.Equal(t, busyRun1.ID, qr.JobRunID)
+
+	qr, ok = services.ExportedTake(rm)
+	require.True(t, ok)
+	assert.Equal(t, otherRun.ID, qr.JobRunID)
+	assert.Equal(t, 2, services.ExportedWorkerCount(rm))
+}
+
+func TestJobRunner_Take_PrioritizesByPayment(t *testing.T) {
+	t.Parallel()
+
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+	config.Set("RUN_QUEUE_PRIORITY", "payment")
+	s, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+	rm, cleanup := cltest.NewJobRunner(s)
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	require.NoError(t, s.CreateJob(&j))
+
+	unpaid := j.NewRun(j.Initiators[0])
+	unpaid.CreatedAt = time.Now().Add(-3 * time.Minute)
+	cheap := j.NewRun(j.Initiators[0])
+	cheap.Payment = assets.NewLink(1)
+	cheap.CreatedAt = time.Now().Add(-2 * time.Minute)
+	generous := j.NewRun(j.Initiators[0])
+	generous.Payment = assets.NewLink(100)
+	generous.CreatedAt = time.Now().Add(-1 * time.Minute)
+	for _, run := range []*models.JobRun{&unpaid, &cheap, &generous} {
+		require.NoError(t, s.CreateJobRun(run))
+		require.NoError(t, services.ExportedEnqueue(rm, run.ID))
+	}
+
+	for _, expected := range []*models.ID{generous.ID, cheap.ID, unpaid.ID} {
+		qr, ok := services.ExportedTake(rm)
+		require.True(t, ok)
+		assert.Equal(t, expected, qr.JobRunID)
+	}
 }
 
 func TestJobRunner_Stop(t *testing.T) {
@@ -182,18 +578,23 @@ func TestJobRunner_Stop(t *testing.T) {
 	defer cleanup()
 	rm, cleanup := cltest.NewJobRunner(s)
 	defer cleanup()
+
 	j := cltest.NewJobWithWebInitiator()
-	initr := j.Initiators[0]
-	jr := j.NewRun(initr)
+	j.Tasks = []models.TaskSpec{cltest.NewTask(t, "nooppend")}
+	require.NoError(t, s.CreateJob(&j))
+	jr := j.NewRun(j.Initiators[0])
+	require.NoError(t, s.CreateJobRun(&jr))
 
 	require.NoError(t, rm.Start())
-
-	services.ExportedChannelForRun(rm, jr.ID)
-	assert.Equal(t, 1, services.ExportedWorkerCount(rm))
+	require.NoError(t, services.ExportedEnqueue(rm, jr.ID))
+	cltest.WaitForJobRunToPendConfirmations(t, s, jr)
 
 	rm.Stop()
 
 	gomega.NewGomegaWithT(t).Eventually(func() int {
 		return services.ExportedWorkerCount(rm)
 	}).Should(gomega.Equal(0))
+
+	_, ok := services.ExportedTake(rm)
+	assert.False(t, ok)
 }
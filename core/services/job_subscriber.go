@@ -22,6 +22,7 @@ type JobSubscriber interface {
 // jobSubscriber implementation
 type jobSubscriber struct {
 	store            *store.Store
+	broadcaster      *LogBroadcaster
 	jobSubscriptions map[string]JobSubscription
 	jobsMutex        *sync.RWMutex
 }
@@ -30,19 +31,20 @@ type jobSubscriber struct {
 func NewJobSubscriber(store *store.Store) JobSubscriber {
 	return &jobSubscriber{
 		store:            store,
+		broadcaster:      NewLogBroadcaster(store),
 		jobSubscriptions: map[string]JobSubscription{},
 		jobsMutex:        &sync.RWMutex{},
 	}
 }
 
 // AddJob subscribes to ethereum log events for each "runlog" and "ethlog"
-// initiator in the passed job spec.
+// initiator in the passed job spec, unless the job is already subscribed.
 func (js *jobSubscriber) AddJob(job models.JobSpec, bn *models.Head) error {
-	if !job.IsLogInitiated() {
+	if !job.IsLogInitiated() || js.subscribed(job.ID) {
 		return nil
 	}
 
-	sub, err := StartJobSubscription(job, bn, js.store)
+	sub, err := StartJobSubscription(job, bn, js.store, js.broadcaster)
 	if err != nil {
 		return err
 	}
@@ -52,10 +54,7 @@ func (js *jobSubscriber) AddJob(job models.JobSpec, bn *models.Head) error {
 
 // RemoveJob unsubscribes the job from a log subscription to trigger runs.
 func (js *jobSubscriber) RemoveJob(ID *models.ID) error {
-	js.jobsMutex.Lock()
-	sub, ok := js.jobSubscriptions[ID.String()]
-	delete(js.jobSubscriptions, ID.String())
-	js.jobsMutex.Unlock()
+	sub, ok := js.removeSubscription(ID)
 	if !ok {
 		return fmt.Errorf("JobSubscriber#RemoveJob: job %s not found", ID)
 	}
@@ -74,20 +73,39 @@ func (js *jobSubscriber) Jobs() []models.JobSpec {
 	return jobs
 }
 
+func (js *jobSubscriber) subscribed(ID *models.ID) bool {
+	js.jobsMutex.RLock()
+	defer js.jobsMutex.RUnlock()
+	_, ok := js.jobSubscriptions[ID.String()]
+	return ok
+}
+
+func (js *jobSubscriber) removeSubscription(ID *models.ID) (JobSubscription, bool) {
+	js.jobsMutex.Lock()
+	defer js.jobsMutex.Unlock()
+	sub, ok := js.jobSubscriptions[ID.String()]
+	delete(js.jobSubscriptions, ID.String())
+	return sub, ok
+}
+
 func (js *jobSubscriber) addSubscription(sub JobSubscription) {
 	js.jobsMutex.Lock()
 	defer js.jobsMutex.Unlock()
 	js.jobSubscriptions[sub.Job.ID.String()] = sub
 }
 
-// Connect connects the jobs to the ethereum node by creating corresponding subscriptions.
+// Connect connects the jobs to the ethereum node by creating corresponding
+// subscriptions, backfilling the logs of all of them together.
 func (js *jobSubscriber) Connect(bn *models.Head) error {
 	var merr error
-	err := js.store.Jobs(func(j models.JobSpec) bool {
-		merr = multierr.Append(merr, js.AddJob(j, bn))
-		return true
+	js.broadcaster.Batch(func() {
+		err := js.store.Jobs(func(j models.JobSpec) bool {
+			merr = multierr.Append(merr, js.AddJob(j, bn))
+			return true
+		})
+		merr = multierr.Append(merr, err)
 	})
-	return multierr.Append(merr, err)
+	return merr
 }
 
 // Disconnect disconnects all subscriptions associated with jobs belonging to
@@ -117,3 +135,23 @@ func (js *jobSubscriber) OnNewHead(head *models.Head) {
 		logger.Errorf("error fetching pending job runs: %v", err)
 	}
 }
+
+// OnReorg revalidates the runs awaiting the chain whose initiating log was
+// included in an orphaned block.
+func (js *jobSubscriber) OnReorg(reorg *models.Reorg) {
+	err := js.store.UnscopedJobRunsWithStatus(func(run *models.JobRun) {
+		if run.RunRequest.BlockHash == nil || !reorg.Orphans(*run.RunRequest.BlockHash) {
+			return
+		}
+
+		err := RevalidateOrphanedRun(run, js.store.Unscoped(), reorg)
+		if err != nil {
+			logger.Errorf("JobSubscriber.OnReorg: %v", err)
+		}
+
+	}, models.RunStatusPendingConnection, models.RunStatusPendingConfirmations, models.RunStatusPendingBridge)
+
+	if err != nil {
+		logger.Errorf("error fetching pending job runs: %v", err)
+	}
+}
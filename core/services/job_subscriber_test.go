@@ -22,7 +22,9 @@ func TestJobSubscriber_Connect_WithJobs(t *testing.T) {
 	store, el, cleanup := cltest.NewJobSubscriber(t)
 	defer cleanup()
 	eth := cltest.MockEthOnStore(t, store)
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{})
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{})
 
 	j1 := cltest.NewJobWithLogInitiator()
@@ -47,9 +49,13 @@ func TestJobSubscriber_reconnectLoop_Resubscribing(t *testing.T) {
 	defer cleanup()
 
 	eth := cltest.MockEthOnStore(t, store)
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{})
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{})
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{})
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{})
 
 	j1 := cltest.NewJobWithLogInitiator()
@@ -129,6 +135,7 @@ func TestJobSubscriber_AddJob_Listening(t *testing.T) {
 			defer cleanup()
 
 			eth := cltest.MockEthOnStore(t, store)
+			eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 			eth.Register("eth_getLogs", []models.Log{})
 			eth.Register("eth_chainId", store.Config.ChainID())
 			logChan := make(chan models.Log, 1)
@@ -169,6 +176,7 @@ func TestJobSubscriber_RemoveJob_RunLog(t *testing.T) {
 	defer cleanup()
 
 	eth := cltest.MockEthOnStore(t, store)
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{})
 	eth.Register("eth_chainId", store.Config.ChainID())
 	logChan := make(chan models.Log, 1)
@@ -205,6 +213,7 @@ func TestJobSubscriber_RemoveJob_EthLog(t *testing.T) {
 	defer cleanup()
 
 	eth := cltest.MockEthOnStore(t, store)
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{})
 	eth.Register("eth_chainId", store.Config.ChainID())
 	logChan := make(chan models.Log, 1)
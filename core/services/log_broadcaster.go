@@ -0,0 +1,570 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// logDedupeBlocks is how many blocks back a log group remembers the logs it
+// dispatched, to skip those delivered again after it resubscribes.
+const logDedupeBlocks = 50
+
+// LogBroadcaster fans the logs of a single Ethereum subscription per
+// contract address out to every listener registered for that address,
+// rather than each initiator opening its own subscription, which with many
+// jobs exceeds the number of filters Ethereum providers allow. A listener
+// without an address, listening to every contract, keeps a subscription of
+// its own.
+//
+// Listeners registered with a FromBlock are backfilled with the logs since
+// that block, with one eth_getLogs per address and range of at most
+// ETH_LOG_BLOCK_RANGE blocks for all listeners registered in a Batch. A
+// subscription that fails is resubscribed, and the logs missed meanwhile
+// backfilled.
+type LogBroadcaster struct {
+	store   *strpkg.Store
+	mutex   sync.Mutex
+	groups  map[common.Address]*logGroup
+	batch   []*logListener
+	batched bool
+}
+
+// LogHandler handles the logs a LogBroadcaster delivers to a listener.
+type LogHandler interface {
+	HandleLog(log models.Log)
+	// HandleBackfilled is called once the listener has been delivered every
+	// log it was registered for, up to and including the block.
+	HandleBackfilled(blockNumber uint64)
+}
+
+// LogHandlerFunc is a LogHandler of only the logs.
+type LogHandlerFunc func(models.Log)
+
+// HandleLog calls f with the log.
+func (f LogHandlerFunc) HandleLog(log models.Log) {
+	f(log)
+}
+
+// HandleBackfilled does nothing.
+func (f LogHandlerFunc) HandleBackfilled(uint64) {}
+
+// NewLogBroadcaster returns a LogBroadcaster with no listeners.
+func NewLogBroadcaster(store *strpkg.Store) *LogBroadcaster {
+	return &LogBroadcaster{
+		store:  store,
+		groups: map[common.Address]*logGroup{},
+	}
+}
+
+// Register calls the handler with every log matching the filter, including,
+// when the filter has a FromBlock, those already in blocks since then.
+func (b *LogBroadcaster) Register(filter ethereum.FilterQuery, handler LogHandler) (Unsubscriber, error) {
+	listener := &logListener{
+		broadcaster: b,
+		filter:      filter,
+		handler:     handler,
+		backfilling: filter.FromBlock != nil,
+	}
+
+	b.mutex.Lock()
+	if err := b.addListener(listener); err != nil {
+		b.mutex.Unlock()
+		listener.Unsubscribe()
+		return nil, err
+	}
+	backfill := listener.backfilling && !b.batched
+	if listener.backfilling && b.batched {
+		b.batch = append(b.batch, listener)
+	}
+	b.mutex.Unlock()
+
+	if backfill {
+		go b.backfill([]*logListener{listener})
+	}
+	return listener, nil
+}
+
+// Batch registers the listeners of fn together, backfilling all of them at
+// once when fn returns.
+func (b *LogBroadcaster) Batch(fn func()) {
+	b.mutex.Lock()
+	if b.batched {
+		b.mutex.Unlock()
+		fn()
+		return
+	}
+	b.batched = true
+	b.mutex.Unlock()
+
+	fn()
+
+	b.mutex.Lock()
+	listeners := b.batch
+	b.batch = nil
+	b.batched = false
+	b.mutex.Unlock()
+
+	if len(listeners) > 0 {
+		go b.backfill(listeners)
+	}
+}
+
+func (b *LogBroadcaster) addListener(listener *logListener) error {
+	if len(listener.filter.Addresses) == 0 {
+		group, err := b.subscribe(nil, listener.filter.Topics)
+		if err != nil {
+			return err
+		}
+		group.add(listener)
+		listener.groups = append(listener.groups, group)
+		return nil
+	}
+
+	for _, address := range listener.filter.Addresses {
+		group, ok := b.groups[address]
+		if !ok {
+			var err error
+			group, err = b.subscribe([]common.Address{address}, nil)
+			if err != nil {
+				return err
+			}
+			b.groups[address] = group
+		}
+		group.add(listener)
+		listener.groups = append(listener.groups, group)
+	}
+	return nil
+}
+
+func (b *LogBroadcaster) subscribe(addresses []common.Address, topics [][]common.Hash) (*logGroup, error) {
+	group := &logGroup{
+		store:     b.store,
+		filter:    ethereum.FilterQuery{Addresses: addresses, Topics: topics},
+		listeners: map[*logListener]struct{}{},
+		logs:      make(chan models.Log),
+		done:      make(chan struct{}),
+		recent:    map[string]uint64{},
+	}
+	// Logs missed by a subscription failing before dispatching any are
+	// backfilled from the head it was made at.
+	head, err := b.store.LastHead()
+	if err != nil {
+		return nil, errors.Wrap(err, "LogBroadcaster#subscribe")
+	}
+	if head != nil {
+		group.lastBlock = uint64(head.Number)
+	}
+	sub, err := b.store.TxManager.SubscribeToLogs(group.logs, group.filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "LogBroadcaster#subscribe")
+	}
+	group.ethSubscription = sub
+	go group.listen()
+	return group, nil
+}
+
+func (b *LogBroadcaster) removeListener(listener *logListener) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, group := range listener.groups {
+		if group.remove(listener) > 0 {
+			continue
+		}
+		group.close()
+		for _, address := range group.filter.Addresses {
+			if b.groups[address] == group {
+				delete(b.groups, address)
+			}
+		}
+	}
+	listener.groups = nil
+}
+
+// backfill requests the logs of the listeners' addresses since the earliest
+// of their FromBlocks, once per address, and delivers each listener those in
+// blocks since its own FromBlock.
+func (b *LogBroadcaster) backfill(listeners []*logListener) {
+	byAddress := map[common.Address][]*logListener{}
+	latest := map[*logListener]uint64{}
+	failed := map[*logListener]bool{}
+	backfillQuery := func(q ethereum.FilterQuery, ls []*logListener) {
+		blockNumber, err := getLogsInChunks(b.store, q, func(log models.Log) {
+			for _, listener := range ls {
+				listener.backfillLog(log)
+			}
+		})
+		for _, listener := range ls {
+			if err != nil {
+				failed[listener] = true
+			} else if _, ok := latest[listener]; !ok || blockNumber < latest[listener] {
+				latest[listener] = blockNumber
+			}
+		}
+		if err != nil {
+			logger.Errorw("Unable to backfill logs", "err", err, "fromBlock", q.FromBlock.String(), "toBlock", q.ToBlock.String())
+		}
+	}
+
+	for _, listener := range listeners {
+		if len(listener.filter.Addresses) == 0 {
+			backfillQuery(listener.filter, []*logListener{listener})
+			continue
+		}
+		for _, address := range listener.filter.Addresses {
+			byAddress[address] = append(byAddress[address], listener)
+		}
+	}
+
+	for address, ls := range byAddress {
+		q := ethereum.FilterQuery{Addresses: []common.Address{address}}
+		q.FromBlock, q.ToBlock, q.Topics = mergeFilters(ls)
+		backfillQuery(q, ls)
+	}
+
+	for _, listener := range listeners {
+		listener.finishBackfill(latest[listener], !failed[listener])
+	}
+}
+
+// getLogsInChunks requests the logs of the query in ranges of at most
+// ETH_LOG_BLOCK_RANGE blocks, the last of which is left open so that no log
+// is missed as blocks are mined, calling fn with each log. It returns the
+// latest block when the logs were requested, up to which every log has
+// been.
+func getLogsInChunks(store *strpkg.Store, q ethereum.FilterQuery, fn func(models.Log)) (uint64, error) {
+	head, err := store.TxManager.GetBlockByNumber("latest")
+	if err != nil {
+		return 0, errors.Wrap(err, "getLogsInChunks#GetBlockByNumber")
+	}
+	latest := head.Number.ToInt().Uint64()
+	to := latest
+	if q.ToBlock != nil && q.ToBlock.Uint64() < to {
+		to = q.ToBlock.Uint64()
+	}
+	blockRange := store.Config.EthLogBlockRange()
+	if blockRange == 0 {
+		blockRange = 1
+	}
+
+	var start uint64
+	if q.FromBlock != nil {
+		start = q.FromBlock.Uint64()
+	}
+	for {
+		chunk := q
+		chunk.FromBlock = new(big.Int).SetUint64(start)
+		end := start + blockRange - 1
+		if end < to {
+			chunk.ToBlock = new(big.Int).SetUint64(end)
+		}
+
+		logs, err := store.TxManager.GetLogs(chunk)
+		if err != nil {
+			return 0, errors.Wrapf(err, "getLogsInChunks#GetLogs from block %d", start)
+		}
+		for _, log := range logs {
+			fn(log)
+		}
+
+		if end >= to {
+			return latest, nil
+		}
+		start = end + 1
+	}
+}
+
+// mergeFilters returns the block range and topics of a query covering the
+// filters of every listener.
+func mergeFilters(listeners []*logListener) (from, to *big.Int, topics [][]common.Hash) {
+	for i, listener := range listeners {
+		f := listener.filter
+		if i == 0 {
+			from, to, topics = f.FromBlock, f.ToBlock, f.Topics
+			continue
+		}
+		from = utils.MinBigs(from, f.FromBlock)
+		if to != nil && f.ToBlock != nil {
+			to = utils.MaxBigs(to, f.ToBlock)
+		} else {
+			to = nil
+		}
+		topics = mergeTopics(topics, f.Topics)
+	}
+	return from, to, topics
+}
+
+// mergeTopics returns topic filters matching the logs of either, leaving
+// positions unfiltered unless both filter them.
+func mergeTopics(a, b [][]common.Hash) [][]common.Hash {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	merged := make([][]common.Hash, n)
+	for i := 0; i < n; i++ {
+		if len(a[i]) == 0 || len(b[i]) == 0 {
+			continue
+		}
+		merged[i] = append(append([]common.Hash{}, a[i]...), b[i]...)
+	}
+	return merged
+}
+
+// filterMatches reports whether the log has the topics the filter asks for,
+// its address being filtered by the node.
+func filterMatches(q ethereum.FilterQuery, log models.Log) bool {
+	for i, topics := range q.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if i >= len(log.Topics) || !containsHash(topics, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// blockInRange reports whether the log is in the filter's range of blocks.
+func blockInRange(q ethereum.FilterQuery, log models.Log) bool {
+	number := new(big.Int).SetUint64(log.BlockNumber)
+	if q.FromBlock != nil && number.Cmp(q.FromBlock) < 0 {
+		return false
+	}
+	return q.ToBlock == nil || number.Cmp(q.ToBlock) <= 0
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func logKey(log models.Log) string {
+	return fmt.Sprintf("%s-%s-%d-%t", log.BlockHash.Hex(), log.TxHash.Hex(), log.Index, log.Removed)
+}
+
+// logListener is the registration of a LogHandler with a LogBroadcaster.
+// Until it is backfilled, the logs it receives from its subscriptions are
+// held back. Logs in blocks that were backfilled are never delivered again.
+type logListener struct {
+	broadcaster *LogBroadcaster
+	filter      ethereum.FilterQuery
+	handler     LogHandler
+	groups      []*logGroup
+
+	mutex        sync.Mutex
+	backfilling  bool
+	backfilled   map[string]bool
+	pending      []models.Log
+	unsubscribed bool
+}
+
+func (l *logListener) receive(log models.Log) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.unsubscribed || !filterMatches(l.filter, log) {
+		return
+	}
+	if l.backfilling {
+		l.pending = append(l.pending, log)
+		return
+	}
+	if !l.backfilled[log.BlockHash.String()] {
+		l.handler.HandleLog(log)
+	}
+}
+
+func (l *logListener) backfillLog(log models.Log) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.unsubscribed || !filterMatches(l.filter, log) || !blockInRange(l.filter, log) {
+		return
+	}
+	if l.backfilled == nil {
+		l.backfilled = map[string]bool{}
+	}
+	l.backfilled[log.BlockHash.String()] = true
+	l.handler.HandleLog(log)
+}
+
+// finishBackfill delivers the logs held back, then, if the backfill
+// succeeded, the block it reached.
+func (l *logListener) finishBackfill(blockNumber uint64, ok bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, log := range l.pending {
+		if !l.unsubscribed && !l.backfilled[log.BlockHash.String()] {
+			l.handler.HandleLog(log)
+		}
+	}
+	l.pending = nil
+	l.backfilling = false
+	if ok && !l.unsubscribed {
+		l.handler.HandleBackfilled(blockNumber)
+	}
+}
+
+// Unsubscribe stops calling the listener's handler, and closes the
+// subscriptions no other listener needs.
+func (l *logListener) Unsubscribe() {
+	l.mutex.Lock()
+	l.unsubscribed = true
+	l.mutex.Unlock()
+	l.broadcaster.removeListener(l)
+}
+
+// logGroup is an Ethereum log subscription shared by a set of listeners.
+type logGroup struct {
+	store  *strpkg.Store
+	filter ethereum.FilterQuery
+	logs   chan models.Log
+	done   chan struct{}
+
+	mutex           sync.RWMutex
+	listeners       map[*logListener]struct{}
+	ethSubscription models.EthSubscription
+
+	// Only accessed by listen. lastBlock is the block of the latest log
+	// dispatched, or the head when subscribing until then.
+	recent    map[string]uint64
+	lastBlock uint64
+}
+
+func (g *logGroup) add(listener *logListener) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.listeners[listener] = struct{}{}
+}
+
+func (g *logGroup) remove(listener *logListener) int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	delete(g.listeners, listener)
+	return len(g.listeners)
+}
+
+func (g *logGroup) close() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	close(g.done)
+	timedUnsubscribe(g.ethSubscription)
+}
+
+func (g *logGroup) subscription() models.EthSubscription {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+	return g.ethSubscription
+}
+
+func (g *logGroup) listen() {
+	for {
+		select {
+		case <-g.done:
+			return
+		case log := <-g.logs:
+			g.dispatch(log)
+		case err := <-g.subscription().Err():
+			select {
+			case <-g.done:
+				// Closing the group unsubscribed
+				return
+			default:
+			}
+			logger.Errorw("Error in log subscription, resubscribing", "err", err, "addresses", g.filter.Addresses)
+			if !g.resubscribe() {
+				return
+			}
+		}
+	}
+}
+
+func (g *logGroup) dispatch(log models.Log) {
+	key := logKey(log)
+	if _, seen := g.recent[key]; seen {
+		return
+	}
+	g.recent[key] = log.BlockNumber
+	if log.BlockNumber > g.lastBlock {
+		g.lastBlock = log.BlockNumber
+		for k, number := range g.recent {
+			if number+logDedupeBlocks < g.lastBlock {
+				delete(g.recent, k)
+			}
+		}
+	}
+
+	g.mutex.RLock()
+	listeners := make([]*logListener, 0, len(g.listeners))
+	for listener := range g.listeners {
+		listeners = append(listeners, listener)
+	}
+	g.mutex.RUnlock()
+
+	for _, listener := range listeners {
+		listener.receive(log)
+	}
+}
+
+// replaceSubscription swaps in the new subscription, unless the group was
+// closed while it was being made.
+func (g *logGroup) replaceSubscription(sub models.EthSubscription) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	select {
+	case <-g.done:
+		timedUnsubscribe(sub)
+		return false
+	default:
+		g.ethSubscription = sub
+		return true
+	}
+}
+
+// resubscribe replaces the failed subscription, backing off until it
+// succeeds, then dispatches the logs since the last one received, or since
+// the group subscribed, returning false if the group was closed first.
+func (g *logGroup) resubscribe() bool {
+	timedUnsubscribe(g.subscription())
+
+	sleeper := utils.NewBackoffSleeper()
+	for {
+		select {
+		case <-g.done:
+			return false
+		case <-time.After(sleeper.After()):
+		}
+
+		sub, err := g.store.TxManager.SubscribeToLogs(g.logs, g.filter)
+		if err != nil {
+			logger.Warnw("Unable to resubscribe to logs", "err", err, "addresses", g.filter.Addresses)
+			continue
+		}
+		if !g.replaceSubscription(sub) {
+			return false
+		}
+		break
+	}
+
+	if g.lastBlock == 0 {
+		return true
+	}
+	q := g.filter
+	q.FromBlock = new(big.Int).SetUint64(g.lastBlock)
+	if _, err := getLogsInChunks(g.store, q, g.dispatch); err != nil {
+		logger.Errorw("Unable to backfill logs missed while resubscribing", "err", err, "fromBlock", g.lastBlock)
+	}
+	return true
+}
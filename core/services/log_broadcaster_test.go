@@ -0,0 +1,237 @@
+package services_test
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logRecorder struct {
+	mutex      sync.Mutex
+	logs       []models.Log
+	backfilled []uint64
+}
+
+func (r *logRecorder) HandleLog(log models.Log) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.logs = append(r.logs, log)
+}
+
+func (r *logRecorder) HandleBackfilled(blockNumber uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.backfilled = append(r.backfilled, blockNumber)
+}
+
+func (r *logRecorder) backfilledBlocks() []uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]uint64{}, r.backfilled...)
+}
+
+func (r *logRecorder) blocks() []uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	blocks := []uint64{}
+	for _, log := range r.logs {
+		blocks = append(blocks, log.BlockNumber)
+	}
+	return blocks
+}
+
+func TestLogBroadcaster_SharesSubscriptionAndBackfill(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+
+	address := cltest.NewAddress()
+	topicA, topicB := cltest.NewHash(), cltest.NewHash()
+	logs := make(chan models.Log, 2)
+	eth.RegisterSubscription("logs", logs)
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
+	eth.Register("eth_getLogs", []models.Log{
+		{Address: address, BlockNumber: 1, BlockHash: cltest.NewHash(), Topics: []common.Hash{topicA}},
+		{Address: address, BlockNumber: 2, BlockHash: cltest.NewHash(), Topics: []common.Hash{topicB}},
+	})
+
+	broadcaster := services.NewLogBroadcaster(store)
+	a, b := &logRecorder{}, &logRecorder{}
+	var subA, subB services.Unsubscriber
+	broadcaster.Batch(func() {
+		var err error
+		subA, err = broadcaster.Register(ethereum.FilterQuery{
+			FromBlock: big.NewInt(1),
+			Addresses: []common.Address{address},
+			Topics:    [][]common.Hash{{topicA}},
+		}, a)
+		require.NoError(t, err)
+		subB, err = broadcaster.Register(ethereum.FilterQuery{
+			FromBlock: big.NewInt(2),
+			Addresses: []common.Address{address},
+		}, b)
+		require.NoError(t, err)
+
+		// Held back until backfilled
+		logs <- models.Log{Address: address, BlockNumber: 3, BlockHash: cltest.NewHash(), Topics: []common.Hash{topicA}}
+	})
+	defer subA.Unsubscribe()
+	defer subB.Unsubscribe()
+	logs <- models.Log{Address: address, BlockNumber: 4, BlockHash: cltest.NewHash(), Topics: []common.Hash{topicB}}
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(a.blocks).Should(gomega.Equal([]uint64{1, 3}))
+	g.Eventually(b.blocks).Should(gomega.Equal([]uint64{2, 3, 4}))
+	eth.EventuallyAllCalled(t)
+}
+
+func TestLogBroadcaster_Unsubscribe_ClosesUnusedSubscription(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+
+	address := cltest.NewAddress()
+	logs := make(chan models.Log, 1)
+	eth.RegisterSubscription("logs", logs)
+
+	broadcaster := services.NewLogBroadcaster(store)
+	filter := ethereum.FilterQuery{Addresses: []common.Address{address}}
+	first, err := broadcaster.Register(filter, services.LogHandlerFunc(func(models.Log) {}))
+	require.NoError(t, err)
+	second, err := broadcaster.Register(filter, services.LogHandlerFunc(func(models.Log) {}))
+	require.NoError(t, err)
+	eth.EventuallyAllCalled(t)
+
+	first.Unsubscribe()
+	assert.False(t, sendingOnClosedChannel(func() {
+		logs <- models.Log{}
+	}))
+
+	second.Unsubscribe()
+	assert.True(t, sendingOnClosedChannel(func() {
+		logs <- models.Log{}
+	}))
+}
+
+func TestLogBroadcaster_ResubscribesAndBackfillsAfterError(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+
+	address := cltest.NewAddress()
+	logs := make(chan models.Log, 1)
+	sub := eth.RegisterSubscription("logs", logs)
+
+	broadcaster := services.NewLogBroadcaster(store)
+	recorder := &logRecorder{}
+	unsubscriber, err := broadcaster.Register(ethereum.FilterQuery{Addresses: []common.Address{address}}, recorder)
+	require.NoError(t, err)
+	defer unsubscriber.Unsubscribe()
+
+	received := models.Log{Address: address, BlockNumber: 5, BlockHash: cltest.NewHash(), TxHash: cltest.NewHash()}
+	logs <- received
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(recorder.blocks).Should(gomega.Equal([]uint64{5}))
+
+	missed := models.Log{Address: address, BlockNumber: 6, BlockHash: cltest.NewHash(), TxHash: cltest.NewHash()}
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
+	eth.Register("eth_getLogs", []models.Log{received, missed})
+	eth.RegisterSubscription("logs")
+	sub.Errors <- errors.New("websocket closed")
+
+	eth.EventuallyAllCalled(t)
+	g.Eventually(recorder.blocks).Should(gomega.Equal([]uint64{5, 6}))
+	g.Consistently(recorder.blocks).Should(gomega.Equal([]uint64{5, 6}))
+}
+
+func TestLogBroadcaster_ResubscribesAndBackfillsFromSubscribingHead(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+	require.NoError(t, store.CreateHead(cltest.Head(7)))
+
+	address := cltest.NewAddress()
+	sub := eth.RegisterSubscription("logs")
+
+	broadcaster := services.NewLogBroadcaster(store)
+	recorder := &logRecorder{}
+	unsubscriber, err := broadcaster.Register(ethereum.FilterQuery{Addresses: []common.Address{address}}, recorder)
+	require.NoError(t, err)
+	defer unsubscriber.Unsubscribe()
+
+	// No log was received before the subscription failed
+	missed := models.Log{Address: address, BlockNumber: 8, BlockHash: cltest.NewHash(), TxHash: cltest.NewHash()}
+	var fromBlock string
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{Number: hexutil.Big(*big.NewInt(8))})
+	eth.Register("eth_getLogs", []models.Log{missed}, func(_ interface{}, args ...interface{}) error {
+		fromBlock = args[0].([]interface{})[0].(map[string]interface{})["fromBlock"].(string)
+		return nil
+	})
+	eth.RegisterSubscription("logs")
+	sub.Errors <- errors.New("websocket closed")
+
+	eth.EventuallyAllCalled(t)
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(recorder.blocks).Should(gomega.Equal([]uint64{8}))
+	assert.Equal(t, "0x7", fromBlock)
+}
+
+func TestLogBroadcaster_BackfillsInChunks(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("ETH_LOG_BLOCK_RANGE", 2)
+	eth := cltest.MockEthOnStore(t, store)
+
+	address := cltest.NewAddress()
+	eth.RegisterSubscription("logs")
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{Number: hexutil.Big(*big.NewInt(5))})
+	var mutex sync.Mutex
+	var ranges [][2]string
+	recordRange := func(_ interface{}, data ...interface{}) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		arg := data[0].([]interface{})[0].(map[string]interface{})
+		ranges = append(ranges, [2]string{arg["fromBlock"].(string), arg["toBlock"].(string)})
+		return nil
+	}
+	eth.Register("eth_getLogs", []models.Log{{Address: address, BlockNumber: 2, BlockHash: cltest.NewHash()}}, recordRange)
+	eth.Register("eth_getLogs", []models.Log{}, recordRange)
+	eth.Register("eth_getLogs", []models.Log{{Address: address, BlockNumber: 5, BlockHash: cltest.NewHash()}}, recordRange)
+
+	broadcaster := services.NewLogBroadcaster(store)
+	recorder := &logRecorder{}
+	unsubscriber, err := broadcaster.Register(ethereum.FilterQuery{
+		FromBlock: big.NewInt(1),
+		Addresses: []common.Address{address},
+	}, recorder)
+	require.NoError(t, err)
+	defer unsubscriber.Unsubscribe()
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(recorder.backfilledBlocks).Should(gomega.Equal([]uint64{5}))
+	assert.Equal(t, []uint64{2, 5}, recorder.blocks())
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, [][2]string{{"0x1", "0x2"}, {"0x3", "0x4"}, {"0x5", "latest"}}, ranges)
+}
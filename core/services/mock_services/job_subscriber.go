@@ -90,6 +90,16 @@ func (mr *MockJobSubscriberMockRecorder) OnNewHead(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnNewHead", reflect.TypeOf((*MockJobSubscriber)(nil).OnNewHead), arg0)
 }
 
+// OnReorg mocks base method
+func (m *MockJobSubscriber) OnReorg(arg0 *models.Reorg) {
+	m.ctrl.Call(m, "OnReorg", arg0)
+}
+
+// OnReorg indicates an expected call of OnReorg
+func (mr *MockJobSubscriberMockRecorder) OnReorg(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnReorg", reflect.TypeOf((*MockJobSubscriber)(nil).OnReorg), arg0)
+}
+
 // RemoveJob mocks base method
 func (m *MockJobSubscriber) RemoveJob(arg0 *models.ID) error {
 	ret := m.ctrl.Call(m, "RemoveJob", arg0)
@@ -5,13 +5,36 @@ import (
 	"math/big"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	clnull "github.com/smartcontractkit/chainlink/core/null"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/assets"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/utils"
+	null "gopkg.in/guregu/null.v3"
+)
+
+var (
+	promRunsStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_runs_started_total",
+		Help: "The total number of job runs created",
+	}, []string{"job_spec_id"})
+	promRunsCompleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_runs_completed_total",
+		Help: "The total number of job runs completed successfully",
+	}, []string{"job_spec_id"})
+	promRunsErrored = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_runs_errored_total",
+		Help: "The total number of job runs that ended in an error",
+	}, []string{"job_spec_id"})
+	promRunsCancelled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_runs_cancelled_total",
+		Help: "The total number of job runs cancelled along with their request",
+	}, []string{"job_spec_id"})
 )
 
 // ExecuteJob saves and immediately begins executing a run for a specified job
@@ -206,6 +229,116 @@ func ResumeConfirmingTask(
 	return updateAndTrigger(run, store)
 }
 
+// RevalidateOrphanedRun re-validates a run whose initiating log was included
+// in a block orphaned by a chain reorganization. If the initiating
+// transaction was mined again on the canonical chain, the run's creation
+// height moves to the new block and its confirmations are counted again,
+// otherwise the run is errored.
+func RevalidateOrphanedRun(
+	run *models.JobRun,
+	store *store.Store,
+	reorg *models.Reorg,
+) error {
+
+	logger.Debugw("Chain reorganization revalidating run", run.ForLogger()...)
+
+	if run.Status.Finished() {
+		return fmt.Errorf("Attempting to revalidate finished run %s", run.ID.String())
+	}
+
+	txhash := run.RunRequest.TxHash
+	if txhash == nil {
+		return nil
+	}
+
+	receipt, err := store.TxManager.GetTxReceipt(*txhash)
+	if err != nil {
+		return err
+	}
+
+	if receipt.Unconfirmed() || receipt.BlockHash == nil || reorg.Orphans(*receipt.BlockHash) {
+		run.SetError(fmt.Errorf(
+			"TxHash %s initiating run %s not on main chain after reorg to block %v",
+			txhash.Hex(),
+			run.ID.String(),
+			reorg.Head,
+		))
+		return saveJobRun(run, store)
+	}
+
+	run.RunRequest.BlockHash = receipt.BlockHash
+	run.CreationHeight = receipt.BlockNumber
+	run.ObservedHeight = models.NewBig(reorg.Head.ToInt())
+
+	if !run.Status.PendingConfirmations() {
+		return store.SaveJobRun(run)
+	}
+
+	currentTaskRun := run.NextTaskRun()
+	if currentTaskRun == nil {
+		return fmt.Errorf("Attempting to revalidate confirming run with no remaining tasks %s", run.ID.String())
+	}
+
+	validateMinimumConfirmations(run, currentTaskRun, run.ObservedHeight, store)
+	return updateAndTrigger(run, store)
+}
+
+// CancelRun stops a run that has yet to finish, because its request was
+// cancelled, fulfilled by someone else, or has expired. A JobRunner worker
+// may be processing its own copy of the run, so the run is reloaded first,
+// and the worker is prevented from saving over the cancellation or sending
+// the run's fulfillment once it has been saved.
+func CancelRun(
+	run *models.JobRun,
+	store *store.Store,
+	reason string,
+) error {
+
+	current, err := store.FindJobRun(run.ID)
+	if err != nil {
+		return err
+	}
+	*run = current
+
+	if run.Status.Finished() {
+		return fmt.Errorf("Attempting to cancel finished run %s", run.ID.String())
+	}
+
+	logger.Infow(fmt.Sprintf("Cancelling run: %s", reason), run.ForLogger()...)
+	run.Cancel(reason)
+	return saveJobRun(run, store)
+}
+
+// fulfillment returns true if the task sends a transaction, which cannot be
+// taken back once the run is cancelled.
+func fulfillment(taskRun *models.TaskRun) bool {
+	taskType := taskRun.TaskSpec.Type
+	return taskType == adapters.TaskTypeEthTx || taskType == adapters.TaskTypeEthTxABIEncode
+}
+
+// expiredFulfillment returns true if the task is yet to fulfill a request
+// that has expired, which its requester can cancel at any time.
+func expiredFulfillment(run *models.JobRun, taskRun *models.TaskRun, store *store.Store) bool {
+	return fulfillment(taskRun) && taskRun.Status.Unstarted() && run.RunRequest.Expired(store.Clock.Now())
+}
+
+// cancelledSinceLoaded returns true if the run has been cancelled since it
+// was loaded, in which case its fulfillment must not be sent.
+func cancelledSinceLoaded(run *models.JobRun, store *store.Store) bool {
+	status, err := store.JobRunStatus(run.ID)
+	if err != nil {
+		logger.Errorw("Unable to check whether run was cancelled", run.ForLogger("error", err)...)
+		return false
+	}
+	return status.Cancelled()
+}
+
+// cancelExpiredRun cancels the run instead of fulfilling its expired request.
+func cancelExpiredRun(run *models.JobRun, store *store.Store) error {
+	reason := fmt.Sprintf("Request %s expired at %v", *run.RunRequest.RequestID, *run.RunRequest.Expiration)
+	return CancelRun(run, store, reason)
+}
+
 // ResumeConnectingTask resumes a run that was left in pending_connection.
 func ResumeConnectingTask(
 	run *models.JobRun,
@@ -247,7 +380,7 @@ func ResumePendingTask(
 		return fmt.Errorf("Attempting to resume non pending run %s", run.ID.String())
 	}
 
-	currentTaskRun := run.NextTaskRun()
+	currentTaskRun := pendingTaskRun(run, models.RunStatusPendingBridge)
 	if currentTaskRun == nil {
 		return fmt.Errorf("Attempting to resume pending run with no remaining tasks %s", run.ID.String())
 	}
@@ -260,6 +393,15 @@ func ResumePendingTask(
 	return updateAndTrigger(run, store)
 }
 
+// pendingTaskRun returns the task the run is pending on. The tasks of a task
+// graph don't finish in order, so its pending task is found by its status.
+func pendingTaskRun(run *models.JobRun, status models.RunStatus) *models.TaskRun {
+	if run.IsTaskGraph() {
+		return run.TaskRunWithStatus(status)
+	}
+	return run.NextTaskRun()
+}
+
 func prepareAdapter(
 	taskRun *models.TaskRun,
 	data models.JSON,
@@ -286,7 +428,7 @@ func QueueSleepingTask(
 		return fmt.Errorf("Attempting to resume non sleeping run %s", run.ID.String())
 	}
 
-	currentTaskRun := run.NextTaskRun()
+	currentTaskRun := pendingTaskRun(run, models.RunStatusPendingSleep)
 	if currentTaskRun == nil {
 		return fmt.Errorf("Attempting to resume sleeping run with no remaining tasks %s", run.ID.String())
 	}
@@ -299,7 +441,7 @@ func QueueSleepingTask(
 	if err != nil {
 		currentTaskRun.SetError(err)
 		run.SetError(err)
-		return store.SaveJobRun(run)
+		return saveJobRun(run, store)
 	}
 
 	if sleepAdapter, ok := adapter.BaseAdapter.(*adapters.Sleep); ok {
@@ -309,6 +451,55 @@ func QueueSleepingTask(
 	return fmt.Errorf("Attempting to resume non sleeping task for run %s (%s)", run.ID.String(), currentTaskRun.TaskSpec.Type)
 }
 
+// QueueRetryingTask creates a go routine which will wake up the job runner
+// to attempt a failed task again once its retry delay has elapsed.
+func QueueRetryingTask(
+	run *models.JobRun,
+	store *store.Store,
+) error {
+	if !run.Status.PendingRetry() {
+		return fmt.Errorf("Attempting to retry non retrying run %s", run.ID.String())
+	}
+
+	currentTaskRun := pendingTaskRun(run, models.RunStatusPendingRetry)
+	if currentTaskRun == nil {
+		return fmt.Errorf("Attempting to retry run with no remaining tasks %s", run.ID.String())
+	}
+
+	if !currentTaskRun.Status.PendingRetry() {
+		return fmt.Errorf("Attempting to retry run with non retrying task %s", run.ID.String())
+	}
+
+	delay := currentTaskRun.RetryAt.Time.Sub(store.Clock.Now())
+
+	// XXX: This is to eliminate data race that occurs because slices share their
+	// underlying array even in copies
+	runCopy := *run
+	runCopy.TaskRuns = make([]models.TaskRun, len(run.TaskRuns))
+	copy(runCopy.TaskRuns, run.TaskRuns)
+
+	go func(run models.JobRun) {
+		if delay > 0 {
+			logger.Debugw(fmt.Sprintf("Retrying task in %v", delay), run.ForLogger()...)
+			<-store.Clock.After(delay)
+		}
+
+		task := pendingTaskRun(&run, models.RunStatusPendingRetry)
+		task.Status = models.RunStatusUnstarted
+		task.Result.Status = models.RunStatusUnstarted
+		task.RetryAt = null.Time{}
+		run.Status = models.RunStatusInProgress
+
+		logger.Debugw("Retrying task", run.ForLogger("attempt", task.Attempts+1)...)
+
+		if err := updateAndTrigger(&run, store); err != nil {
+			logger.Errorw("Error retrying task:", "error", err)
+		}
+	}(runCopy)
+
+	return nil
+}
+
 func performTaskSleep(
 	run *models.JobRun,
 	task *models.TaskRun,
@@ -334,7 +525,7 @@ func performTaskSleep(
 
 		<-store.Clock.After(duration)
 
-		task := run.NextTaskRun()
+		task := pendingTaskRun(&run, models.RunStatusPendingSleep)
 		task.Status = models.RunStatusCompleted
 		run.Status = models.RunStatusInProgress
 
@@ -426,7 +617,7 @@ func blockConfirmations(currentHeight, creationHeight *models.Big) *big.Int {
 }
 
 func updateAndTrigger(run *models.JobRun, store *store.Store) error {
-	if err := store.SaveJobRun(run); err != nil {
+	if err := saveJobRun(run, store); err != nil {
 		return err
 	}
 	return triggerIfReady(run, store)
@@ -436,9 +627,38 @@ func createAndTrigger(run *models.JobRun, store *store.Store) error {
 	if err := store.CreateJobRun(run); err != nil {
 		return errors.Wrap(err, "CreateJobRun failed")
 	}
+	promRunsStarted.WithLabelValues(run.JobSpecID.String()).Inc()
+	recordRunFinished(run)
 	return triggerIfReady(run, store)
 }
 
+// saveJobRun saves the run, and records it as finished if it was not before
+// the save.
+func saveJobRun(run *models.JobRun, store *store.Store) error {
+	previous, err := store.JobRunStatus(run.ID)
+	if err != nil && err != orm.ErrorNotFound {
+		return err
+	}
+	if err := store.SaveJobRun(run); err != nil {
+		return err
+	}
+	if !previous.Finished() {
+		recordRunFinished(run)
+	}
+	return nil
+}
+
+func recordRunFinished(run *models.JobRun) {
+	switch run.Status {
+	case models.RunStatusCompleted:
+		promRunsCompleted.WithLabelValues(run.JobSpecID.String()).Inc()
+	case models.RunStatusErrored:
+		promRunsErrored.WithLabelValues(run.JobSpecID.String()).Inc()
+	case models.RunStatusCancelled:
+		promRunsCancelled.WithLabelValues(run.JobSpecID.String()).Inc()
+	}
+}
+
 func triggerIfReady(run *models.JobRun, store *store.Store) error {
 	if run.Status == models.RunStatusInProgress {
 		logger.Debugw(fmt.Sprintf("Executing run originally initiated by %s", run.Initiator.Type), run.ForLogger()...)
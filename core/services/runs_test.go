@@ -434,6 +434,69 @@ func TestResumeConnectingTask(t *testing.T) {
 	assert.Equal(t, string(models.RunStatusInProgress), string(run.Status))
 }
 
+func TestRevalidateOrphanedRun(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+
+	jobSpec := models.JobSpec{ID: models.NewID()}
+	require.NoError(t, store.ORM.CreateJob(&jobSpec))
+
+	orphanedHash := cltest.NewHash()
+	reorg := &models.Reorg{
+		Head:     cltest.Head(5),
+		Orphaned: []models.Head{{Number: 3, Hash: orphanedHash}},
+	}
+
+	newRun := func() *models.JobRun {
+		txHash := cltest.NewHash()
+		run := &models.JobRun{
+			ID:             models.NewID(),
+			JobSpecID:      jobSpec.ID,
+			CreationHeight: models.NewBig(big.NewInt(3)),
+			Status:         models.RunStatusPendingConfirmations,
+			RunRequest:     models.RunRequest{TxHash: &txHash, BlockHash: &orphanedHash},
+			TaskRuns: []models.TaskRun{models.TaskRun{
+				ID:                   models.NewID(),
+				MinimumConfirmations: clnull.Uint32From(3),
+				TaskSpec: models.TaskSpec{
+					JobSpecID: jobSpec.ID,
+					Type:      adapters.TaskTypeNoOp,
+				},
+			}},
+		}
+		require.NoError(t, store.CreateJobRun(run))
+		return run
+	}
+
+	// error the run if its initiating transaction is no longer mined
+	run := newRun()
+	eth.Register("eth_getTransactionReceipt", models.TxReceipt{})
+	require.NoError(t, services.RevalidateOrphanedRun(run, store, reorg))
+	assert.Equal(t, string(models.RunStatusErrored), string(run.Status))
+
+	// move the run to the new block and wait for its confirmations again
+	run = newRun()
+	newBlockHash := cltest.NewHash()
+	eth.Register("eth_getTransactionReceipt", models.TxReceipt{
+		Hash:        *run.RunRequest.TxHash,
+		BlockHash:   &newBlockHash,
+		BlockNumber: cltest.Int(4),
+	})
+	require.NoError(t, services.RevalidateOrphanedRun(run, store, reorg))
+	assert.Equal(t, string(models.RunStatusPendingConfirmations), string(run.Status))
+	assert.Equal(t, big.NewInt(4), run.CreationHeight.ToInt())
+	assert.Equal(t, uint32(2), run.TaskRuns[0].Confirmations.Uint32)
+
+	found, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newBlockHash, *found.RunRequest.BlockHash)
+
+	// reject a finished run
+	run.Status = models.RunStatusCompleted
+	assert.Error(t, services.RevalidateOrphanedRun(run, store, reorg))
+}
+
 func sleepAdapterParams(t testing.TB, n int) models.JSON {
 	d := time.Duration(n)
 	json := []byte(fmt.Sprintf(`{"until":%v}`, time.Now().Add(d*time.Second).Unix()))
@@ -827,3 +890,40 @@ func TestExecuteJobWithRunRequest_fromRunLog_ConnectToLaggingEthNode(t *testing.
 	assert.Equal(t, uint32(0), updatedJR.TaskRuns[0].Confirmations.Uint32)
 	assert.True(t, eth.AllCalled(), eth.Remaining())
 }
+
+func TestSaveJobRun_RecordsFinishedRunsOnce(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	run := job.NewRun(job.Initiators[0])
+	run.Status = models.RunStatusInProgress
+	require.NoError(t, store.CreateJobRun(&run))
+
+	require.NoError(t, services.ExportedSaveJobRun(&run, store))
+	completed, _, _ := services.ExportedRunsFinished(job.ID)
+	assert.Equal(t, float64(0), completed)
+
+	run.Status = models.RunStatusCompleted
+	require.NoError(t, services.ExportedSaveJobRun(&run, store))
+	completed, _, _ = services.ExportedRunsFinished(job.ID)
+	assert.Equal(t, float64(1), completed)
+
+	// Saving the finished run again does not count it again
+	require.NoError(t, services.ExportedSaveJobRun(&run, store))
+	completed, _, _ = services.ExportedRunsFinished(job.ID)
+	assert.Equal(t, float64(1), completed)
+
+	// Nor does a save that fails
+	cancelled := job.NewRun(job.Initiators[0])
+	require.NoError(t, store.CreateJobRun(&cancelled))
+	require.NoError(t, services.CancelRun(&cancelled, store, "request cancelled"))
+	errored := cancelled
+	errored.SetError(fmt.Errorf("too late"))
+	assert.Error(t, services.ExportedSaveJobRun(&errored, store))
+	assert.Error(t, services.CancelRun(&cancelled, store, "request cancelled again"))
+	_, erroredCount, cancelledCount := services.ExportedRunsFinished(job.ID)
+	assert.Equal(t, float64(0), erroredCount)
+	assert.Equal(t, float64(1), cancelledCount)
+}
@@ -2,7 +2,10 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/mrwonko/cron"
 	"github.com/smartcontractkit/chainlink/core/logger"
@@ -12,11 +15,12 @@ import (
 )
 
 // Scheduler contains fields for Recurring and OneTime for occurrences,
-// a pointer to the store and a started field to indicate if the Scheduler
-// has started or not.
+// FluxMonitor for polled feeds, a pointer to the store and a started field
+// to indicate if the Scheduler has started or not.
 type Scheduler struct {
 	Recurring    *Recurring
 	OneTime      *OneTime
+	FluxMonitor  *FluxMonitor
 	store        *store.Store
 	startedMutex sync.RWMutex
 	started      bool
@@ -31,14 +35,15 @@ func NewScheduler(store *store.Store) *Scheduler {
 			Store: store,
 			Clock: store.Clock,
 		},
-		store: store,
+		FluxMonitor: NewFluxMonitor(store),
+		store:       store,
 	}
 }
 
 // Start checks to ensure the Scheduler has not already started,
 // calls the Start function for both Recurring and OneTime types,
 // sets the started field to true, and adds jobs relevant to its
-// initiator ("cron" and "runat").
+// initiator ("cron", "runat" and "fluxmonitor").
 func (s *Scheduler) Start() error {
 	s.startedMutex.Lock()
 	defer s.startedMutex.Unlock()
@@ -51,6 +56,9 @@ func (s *Scheduler) Start() error {
 	if err := s.Recurring.Start(); err != nil {
 		return err
 	}
+	if err := s.FluxMonitor.Start(); err != nil {
+		return err
+	}
 	s.started = true
 
 	return s.store.Jobs(func(j models.JobSpec) bool {
@@ -67,6 +75,7 @@ func (s *Scheduler) Stop() {
 	if s.started {
 		s.Recurring.Stop()
 		s.OneTime.Stop()
+		s.FluxMonitor.Stop()
 		s.started = false
 	}
 }
@@ -74,6 +83,7 @@ func (s *Scheduler) Stop() {
 func (s *Scheduler) addJob(job models.JobSpec) {
 	s.Recurring.AddJob(job)
 	s.OneTime.AddJob(job)
+	s.FluxMonitor.AddJob(job)
 }
 
 // AddJob is the governing function for Recurring and OneTime,
@@ -92,7 +102,7 @@ func (s *Scheduler) AddJob(job models.JobSpec) {
 // Instances of Recurring must be initialized using NewRecurring().
 type Recurring struct {
 	Cron  Cron
-	Clock utils.Nower
+	Clock utils.AfterNower
 	store *store.Store
 }
 
@@ -117,27 +127,118 @@ func (r *Recurring) Stop() {
 	r.Cron.Stop()
 }
 
+// maxCronCatchUpRuns bounds the runs of a "run-all" cron initiator making up
+// for a long downtime.
+const maxCronCatchUpRuns = 100
+
 // AddJob looks for "cron" initiators, adds them to cron's schedule
-// for execution when specified.
+// for execution when specified, and makes up for the fires missed since
+// they last fired according to their catch-up policy.
 func (r *Recurring) AddJob(job models.JobSpec) {
 	for _, i := range job.InitiatorsFor(models.InitiatorCron) {
 		initr := i
-		if !job.Ended(r.Clock.Now()) {
-			archived := false
-			r.Cron.AddFunc(string(initr.Schedule), func() {
-				if archived || r.store.Archived(job.ID) {
-					archived = true
-					return
-				}
-				_, err := ExecuteJob(job, initr, models.RunResult{}, nil, r.store)
-				if err != nil && !expectedRecurringScheduleJobError(err) {
-					logger.Errorw(err.Error())
-				}
-			})
+		if job.Ended(r.Clock.Now()) {
+			continue
+		}
+		schedule, err := cronSchedule(initr)
+		if err != nil {
+			logger.Errorw(err.Error(), "job", job.ID.String())
+			continue
 		}
+
+		archived := false
+		fire := func() {
+			if archived || r.store.Archived(job.ID) {
+				archived = true
+				return
+			}
+			r.fire(job, initr)
+		}
+		r.catchUp(initr, schedule, fire)
+		r.Cron.Schedule(schedule, cron.FuncJob(fire))
 	}
 }
 
+// fire records when the initiator fired, waits a random part of its jitter
+// window and runs the job.
+func (r *Recurring) fire(job models.JobSpec, initr models.Initiator) {
+	if err := r.store.MarkFired(&initr, r.Clock.Now()); err != nil {
+		logger.Errorw(err.Error(), "job", job.ID.String())
+	}
+	if initr.Jitter > 0 {
+		<-r.Clock.After(time.Duration(rand.Int63n(int64(initr.Jitter))))
+	}
+	_, err := ExecuteJob(job, initr, models.RunResult{}, nil, r.store)
+	if err != nil && !expectedRecurringScheduleJobError(err) {
+		logger.Errorw(err.Error())
+	}
+}
+
+// catchUp fires the initiator for the fires of its schedule missed since it
+// last fired: none for "skip", one for "run-once" and every one, up to
+// maxCronCatchUpRuns, for "run-all".
+func (r *Recurring) catchUp(initr models.Initiator, schedule cron.Schedule, fire func()) {
+	if !initr.LastFiredAt.Valid {
+		return
+	}
+
+	missed := 0
+	now := r.Clock.Now()
+	next := schedule.Next(initr.LastFiredAt.Time)
+	for !next.IsZero() && !next.After(now) && missed < maxCronCatchUpRuns {
+		missed++
+		next = schedule.Next(next)
+	}
+
+	switch initr.CatchUp {
+	case models.CatchUpRunAll:
+	case models.CatchUpRunOnce:
+		if missed > 1 {
+			missed = 1
+		}
+	default:
+		missed = 0
+	}
+
+	if missed == 0 {
+		return
+	}
+	go func() {
+		for i := 0; i < missed; i++ {
+			fire()
+		}
+	}()
+}
+
+// cronSchedule parses the initiator's schedule, to be evaluated in its time
+// zone, or the node's when it has none.
+func cronSchedule(initr models.Initiator) (cron.Schedule, error) {
+	location := time.Local
+	if initr.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(initr.TimeZone); err != nil {
+			return nil, fmt.Errorf("cron time zone: %v", err)
+		}
+	}
+	schedule, err := cron.Parse(string(initr.Schedule))
+	if err != nil {
+		return nil, fmt.Errorf("cron schedule: %v", err)
+	}
+	return locatedSchedule{schedule, location}, nil
+}
+
+// locatedSchedule evaluates a cron schedule in a time zone rather than the
+// node's.
+type locatedSchedule struct {
+	cron.Schedule
+	location *time.Location
+}
+
+// Next returns the next activation time after t, in the schedule's location.
+func (ls locatedSchedule) Next(t time.Time) time.Time {
+	return ls.Schedule.Next(t.In(ls.location))
+}
+
 // OneTime represents runs that are to be executed only once.
 type OneTime struct {
 	Store *store.Store
@@ -205,7 +306,7 @@ func expectedRecurringScheduleJobError(err error) bool {
 type Cron interface {
 	Start()
 	Stop()
-	AddFunc(string, func()) error
+	Schedule(cron.Schedule, cron.Job)
 }
 
 type chainlinkCron struct {
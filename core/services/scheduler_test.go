@@ -144,6 +144,97 @@ func TestRecurring_AddJob_Archived(t *testing.T) {
 	assert.Equal(t, 1, count)
 }
 
+func TestRecurring_AddJob_TimeZone(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	r := services.NewRecurring(store)
+	cron := cltest.NewMockCron()
+	r.Cron = cron
+	defer r.Stop()
+
+	job := cltest.NewJobWithSchedule("0 0 9 * * *")
+	job.Initiators[0].TimeZone = "America/New_York"
+	require.NoError(t, store.CreateJob(&job))
+	r.AddJob(job)
+
+	require.Len(t, cron.Entries, 1)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	next := cron.Entries[0].Schedule.Next(time.Now().UTC()).In(newYork)
+	assert.Equal(t, 9, next.Hour())
+	assert.Equal(t, 0, next.Minute())
+}
+
+func TestRecurring_AddJob_DefaultsToLocalTimeZone(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	r := services.NewRecurring(store)
+	cron := cltest.NewMockCron()
+	r.Cron = cron
+	defer r.Stop()
+
+	job := cltest.NewJobWithSchedule("0 0 9 * * *")
+	require.NoError(t, store.CreateJob(&job))
+	r.AddJob(job)
+
+	require.Len(t, cron.Entries, 1)
+	next := cron.Entries[0].Schedule.Next(time.Now().UTC()).In(time.Local)
+	assert.Equal(t, 9, next.Hour())
+	assert.Equal(t, 0, next.Minute())
+}
+
+func TestRecurring_AddJob_MarksFired(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	r := services.NewRecurring(store)
+	cron := cltest.NewMockCron()
+	r.Cron = cron
+	defer r.Stop()
+
+	job := cltest.NewJobWithSchedule("* * * * *")
+	require.NoError(t, store.CreateJob(&job))
+	r.AddJob(job)
+
+	cron.RunEntries()
+	initr, err := store.FindInitiator(job.Initiators[0].ID)
+	require.NoError(t, err)
+	assert.True(t, initr.LastFiredAt.Valid)
+}
+
+func TestRecurring_AddJob_CatchUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		catchUp  models.CatchUpPolicy
+		wantRuns int
+	}{
+		{"default", "", 0},
+		{"skip", models.CatchUpSkip, 0},
+		{"run once", models.CatchUpRunOnce, 1},
+		{"run all", models.CatchUpRunAll, 3},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			store, cleanup := cltest.NewStore(t)
+			defer cleanup()
+			r := services.NewRecurring(store)
+			cron := cltest.NewMockCron()
+			r.Cron = cron
+			defer r.Stop()
+
+			// Every minute, last fired on the minute three minutes ago
+			job := cltest.NewJobWithSchedule("0 * * * * *")
+			job.Initiators[0].CatchUp = test.catchUp
+			job.Initiators[0].LastFiredAt = null.TimeFrom(time.Now().Truncate(time.Minute).Add(-3 * time.Minute))
+			require.NoError(t, store.CreateJob(&job))
+			r.AddJob(job)
+
+			cltest.WaitForRuns(t, job, store, test.wantRuns)
+		})
+	}
+}
+
 func TestOneTime_AddJob(t *testing.T) {
 	nullTime := cltest.NullTime(t, nil)
 	pastTime := cltest.NullTime(t, "2000-01-01T00:00:00.000Z")
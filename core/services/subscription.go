@@ -5,16 +5,22 @@ import (
 	"math/big"
 	"time"
 
-	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum"
 	"github.com/pkg/errors"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	strpkg "github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
 	"github.com/smartcontractkit/chainlink/core/utils"
 	"go.uber.org/multierr"
 )
 
+// logCursorReorgBlocks is how far behind the blocks it processed an
+// initiator's log cursor is kept, so that after a restart it reads again the
+// logs of blocks since replaced by a reorg.
+const logCursorReorgBlocks = 50
+
 // Unsubscriber is the interface for all subscriptions, allowing one to unsubscribe.
 type Unsubscriber interface {
 	Unsubscribe()
@@ -29,7 +35,12 @@ type JobSubscription struct {
 // StartJobSubscription constructs a JobSubscription which listens for and
 // tracks event logs corresponding to the specified job. Ignores any errors if
 // there is at least one successful subscription to an initiator log.
-func StartJobSubscription(job models.JobSpec, head *models.Head, store *strpkg.Store) (JobSubscription, error) {
+func StartJobSubscription(
+	job models.JobSpec,
+	head *models.Head,
+	store *strpkg.Store,
+	broadcaster *LogBroadcaster,
+) (JobSubscription, error) {
 	var merr error
 	var unsubscribers []Unsubscriber
 
@@ -39,14 +50,25 @@ func StartJobSubscription(job models.JobSpec, head *models.Head, store *strpkg.S
 		models.InitiatorServiceAgreementExecutionLog,
 	)
 
-	for _, initr := range initrs {
-		unsubscriber, err := NewInitiatorSubscription(initr, job, store, head, ReceiveLogRequest)
-		if err == nil {
-			unsubscribers = append(unsubscribers, unsubscriber)
-		} else {
-			merr = multierr.Append(merr, err)
+	broadcaster.Batch(func() {
+		for _, initr := range initrs {
+			unsubscriber, err := NewInitiatorSubscription(initr, job, store, broadcaster, head, ReceiveLogRequest)
+			if err == nil {
+				unsubscribers = append(unsubscribers, unsubscriber)
+			} else {
+				merr = multierr.Append(merr, err)
+			}
 		}
-	}
+
+		for _, initr := range job.InitiatorsFor(models.InitiatorRunLog) {
+			unsubscriber, err := NewRequestLifecycleSubscription(initr, job, store, broadcaster, head)
+			if err == nil {
+				unsubscribers = append(unsubscribers, unsubscriber)
+			} else {
+				merr = multierr.Append(merr, err)
+			}
+		}
+	})
 
 	if len(unsubscribers) == 0 {
 		return JobSubscription{}, multierr.Append(
@@ -63,11 +85,15 @@ func (js JobSubscription) Unsubscribe() {
 	}
 }
 
-// InitiatorSubscription encapsulates all functionality needed to wrap an ethereum subscription
-// for use with a Chainlink Initiator. Initiator specific functionality is delegated
-// to the callback.
+// InitiatorSubscription encapsulates all functionality needed to listen to the
+// logs of a LogBroadcaster for use with a Chainlink Initiator. Initiator
+// specific functionality is delegated to the callback.
+//
+// Each log is processed once, even when delivered again after a restart or a
+// resubscription, and the initiator's log cursor is moved up as logs are
+// processed, for it to resume from there after a restart.
 type InitiatorSubscription struct {
-	*ManagedSubscription
+	Unsubscriber
 	Job       models.JobSpec
 	Initiator models.Initiator
 	store     *strpkg.Store
@@ -75,18 +101,33 @@ type InitiatorSubscription struct {
 }
 
 // NewInitiatorSubscription creates a new InitiatorSubscription that feeds received
-// logs to the callback func parameter.
+// logs to the callback func parameter. It resumes from the initiator's log
+// cursor, or else starts after the from head.
 func NewInitiatorSubscription(
 	initr models.Initiator,
 	job models.JobSpec,
 	store *strpkg.Store,
+	broadcaster *LogBroadcaster,
 	from *models.Head,
 	callback func(*strpkg.Store, models.LogRequest),
 ) (InitiatorSubscription, error) {
-	filter, err := models.FilterQueryFactory(initr, from.NextInt()) // Exclude current block from subscription
+	fromBlock := from.NextInt() // Exclude current block from subscription
+	cursor, err := store.FindLogCursor(initr.ID)
+	if err == nil {
+		fromBlock = new(big.Int).SetUint64(cursor.BlockNumber)
+	} else if err != orm.ErrorNotFound {
+		return InitiatorSubscription{}, errors.Wrap(err, "NewInitiatorSubscription#FindLogCursor")
+	}
+
+	filter, err := models.FilterQueryFactory(initr, fromBlock)
 	if err != nil {
 		return InitiatorSubscription{}, errors.Wrap(err, "NewInitiatorSubscription#FilterQueryFactory")
 	}
+	if filter.FromBlock != nil {
+		if err := store.AdvanceLogCursor(initr.ID, filter.FromBlock.Uint64()); err != nil {
+			return InitiatorSubscription{}, errors.Wrap(err, "NewInitiatorSubscription#AdvanceLogCursor")
+		}
+	}
 
 	sub := InitiatorSubscription{
 		Job:       job,
@@ -95,16 +136,66 @@ func NewInitiatorSubscription(
 		callback:  callback,
 	}
 
-	managedSub, err := NewManagedSubscription(store, filter, sub.dispatchLog)
+	unsubscriber, err := broadcaster.Register(filter, sub)
 	if err != nil {
-		return sub, errors.Wrap(err, "NewInitiatorSubscription#NewManagedSubscription")
+		return sub, errors.Wrap(err, "NewInitiatorSubscription#Register")
 	}
 
-	sub.ManagedSubscription = managedSub
+	sub.Unsubscriber = unsubscriber
 	loggerLogListening(initr, filter.FromBlock)
 	return sub, nil
 }
 
+// HandleLog dispatches the log, unless the initiator already processed it,
+// and moves the initiator's log cursor up to its block. A log is marked
+// processed in the same transaction as the run it starts is created, so
+// that it starts exactly one run.
+func (sub InitiatorSubscription) HandleLog(log models.Log) {
+	if !log.Removed {
+		consumed, err := sub.store.LogConsumed(models.NewConsumedLog(sub.Initiator.ID, log))
+		if err != nil {
+			logger.Errorw("Unable to check whether log was consumed", "txHash", log.TxHash.Hex(), "logIndex", log.Index, "job", sub.Job.ID.String(), "error", err)
+			return
+		} else if consumed {
+			logger.Debugw("Skipping log already consumed", "txHash", log.TxHash.Hex(), "logIndex", log.Index, "job", sub.Job.ID.String())
+			return
+		}
+	}
+
+	sub.dispatchLog(log)
+	if !log.Removed {
+		sub.advanceLogCursor(log.BlockNumber)
+	}
+}
+
+// HandleBackfilled moves the initiator's log cursor past the backfilled
+// blocks.
+func (sub InitiatorSubscription) HandleBackfilled(blockNumber uint64) {
+	sub.advanceLogCursor(blockNumber + 1)
+}
+
+func (sub InitiatorSubscription) advanceLogCursor(blockNumber uint64) {
+	if blockNumber <= logCursorReorgBlocks {
+		return
+	}
+	cursor := blockNumber - logCursorReorgBlocks
+	err := sub.store.AdvanceLogCursor(sub.Initiator.ID, cursor)
+	if err != nil {
+		logger.Errorw("Unable to advance log cursor", "blockNumber", blockNumber, "job", sub.Job.ID.String(), "error", err)
+		return
+	}
+
+	// Logs behind the cursor are only delivered again by a reorg of the
+	// blocks just behind it, so older ones need not be remembered.
+	if cursor <= logCursorReorgBlocks {
+		return
+	}
+	err = sub.store.DeleteConsumedLogsBefore(sub.Initiator.ID, cursor-logCursorReorgBlocks)
+	if err != nil {
+		logger.Errorw("Unable to delete consumed logs", "blockNumber", blockNumber, "job", sub.Job.ID.String(), "error", err)
+	}
+}
+
 func (sub InitiatorSubscription) dispatchLog(log models.Log) {
 	logger.Debugw(fmt.Sprintf("Log for %v initiator for job %s", sub.Initiator.Type, sub.Job.ID.String()),
 		"txHash", log.TxHash.Hex(), "logIndex", log.Index, "blockNumber", log.BlockNumber, "job", sub.Job.ID.String())
@@ -127,6 +218,96 @@ func loggerLogListening(initr models.Initiator, blockNumber *big.Int) {
 	logger.Infow(msg)
 }
 
+// RequestLifecycleSubscription watches the oracle of a run log initiator for
+// the cancellation of the requests that started the job's runs, and their
+// requesters for their fulfillment by another node, and cancels those runs.
+type RequestLifecycleSubscription struct {
+	Job           models.JobSpec
+	store         *strpkg.Store
+	unsubscribers []Unsubscriber
+}
+
+// NewRequestLifecycleSubscription creates a new RequestLifecycleSubscription
+// for the run log initiator.
+func NewRequestLifecycleSubscription(
+	initr models.Initiator,
+	job models.JobSpec,
+	store *strpkg.Store,
+	broadcaster *LogBroadcaster,
+	from *models.Head,
+) (RequestLifecycleSubscription, error) {
+	sub := RequestLifecycleSubscription{Job: job, store: store}
+	filters := []ethereum.FilterQuery{
+		models.RequestCancelledFilterQuery(initr, from.NextInt()),
+		models.RequestFulfilledFilterQuery(from.NextInt()),
+	}
+	for _, filter := range filters {
+		unsubscriber, err := broadcaster.Register(filter, LogHandlerFunc(sub.dispatchLog))
+		if err != nil {
+			sub.Unsubscribe()
+			return sub, errors.Wrap(err, "NewRequestLifecycleSubscription#Register")
+		}
+		sub.unsubscribers = append(sub.unsubscribers, unsubscriber)
+	}
+	return sub, nil
+}
+
+// Unsubscribe stops watching for cancellations and fulfillments.
+func (sub RequestLifecycleSubscription) Unsubscribe() {
+	for _, unsubscriber := range sub.unsubscribers {
+		unsubscriber.Unsubscribe()
+	}
+}
+
+func (sub RequestLifecycleSubscription) dispatchLog(log models.Log) {
+	ReceiveRequestLifecycleLog(sub.store, sub.Job, log)
+}
+
+// ReceiveRequestLifecycleLog cancels the job's unfinished runs for the request
+// that the CancelOracleRequest or ChainlinkFulfilled log is for. A
+// ChainlinkFulfilled log only counts when emitted by the run's requester, and
+// a run is left to finish if the fulfillment is its own.
+func ReceiveRequestLifecycleLog(store *strpkg.Store, job models.JobSpec, log models.Log) {
+	if log.Removed {
+		return
+	}
+
+	requestID, err := models.LifecycleRequestID(log)
+	if err != nil {
+		logger.Errorw("Unable to parse request lifecycle log", "job", job.ID.String(), "error", err)
+		return
+	}
+
+	runs, err := store.UnfinishedJobRunsForRequest(job.ID, requestID)
+	if err != nil {
+		logger.Errorw("Unable to find runs for request", "job", job.ID.String(), "requestId", requestID, "error", err)
+		return
+	}
+
+	for i := range runs {
+		run := &runs[i]
+		reason := fmt.Sprintf("Request %s was cancelled by its requester", requestID)
+		if log.Topics[0] == models.ChainlinkFulfilledTopic {
+			requester := run.RunRequest.Requester
+			if requester == nil || *requester != log.Address || fulfilledByRun(store, run, log) {
+				continue
+			}
+			reason = fmt.Sprintf("Request %s was fulfilled by another node", requestID)
+		}
+
+		if err := CancelRun(run, store, reason); err != nil {
+			logger.Errorw("Unable to cancel run", run.ForLogger("error", err)...)
+		}
+	}
+}
+
+// fulfilledByRun returns true if the log was emitted by a transaction the run
+// sent.
+func fulfilledByRun(store *strpkg.Store, run *models.JobRun, log models.Log) bool {
+	tx, _, err := store.FindTxByAttempt(log.TxHash)
+	return err == nil && tx.SurrogateID.Valid && tx.SurrogateID.String == run.ID.String()
+}
+
 // ReceiveLogRequest parses the log and runs the job indicated by a RunLog or
 // ServiceAgreementExecutionLog. (Both log events have the same format.)
 func ReceiveLogRequest(store *strpkg.Store, le models.LogRequest) {
@@ -160,6 +341,8 @@ func runJob(store *strpkg.Store, le models.LogRequest, data models.JSON) {
 		input.SetError(err)
 		logger.Errorw(err.Error(), le.ForLogger()...)
 	}
+	cl := models.NewConsumedLog(le.GetInitiator().ID, le.GetLog())
+	rr.ConsumedLog = &cl
 
 	_, err = ExecuteJobWithRunRequest(
 		le.GetJobSpec(),
@@ -169,51 +352,13 @@ func runJob(store *strpkg.Store, le models.LogRequest, data models.JSON) {
 		store.Unscoped(),
 		rr,
 	)
-	if err != nil {
+	if errors.Cause(err) == orm.ErrorLogConsumed {
+		logger.Debugw("Skipping log already consumed", le.ForLogger()...)
+	} else if err != nil {
 		logger.Errorw(err.Error(), le.ForLogger()...)
 	}
 }
 
-// ManagedSubscription encapsulates the connecting, backfilling, and clean up of an
-// ethereum node subscription.
-type ManagedSubscription struct {
-	store           *strpkg.Store
-	logs            chan models.Log
-	ethSubscription models.EthSubscription
-	callback        func(models.Log)
-}
-
-// NewManagedSubscription subscribes to the ethereum node with the passed filter
-// and delegates incoming logs to callback.
-func NewManagedSubscription(
-	store *strpkg.Store,
-	filter ethereum.FilterQuery,
-	callback func(models.Log),
-) (*ManagedSubscription, error) {
-	logs := make(chan models.Log)
-	es, err := store.TxManager.SubscribeToLogs(logs, filter)
-	if err != nil {
-		return nil, err
-	}
-
-	sub := &ManagedSubscription{
-		store:           store,
-		callback:        callback,
-		logs:            logs,
-		ethSubscription: es,
-	}
-	go sub.listenToLogs(filter)
-	return sub, nil
-}
-
-// Unsubscribe closes channels and cleans up resources.
-func (sub ManagedSubscription) Unsubscribe() {
-	if sub.ethSubscription != nil {
-		timedUnsubscribe(sub.ethSubscription)
-	}
-	close(sub.logs)
-}
-
 // timedUnsubscribe attempts to unsubscribe but aborts abruptly after a time delay
 // unblocking the application. This is an effort to mitigate the occasional
 // indefinite block described here from go-ethereum:
@@ -230,44 +375,3 @@ func timedUnsubscribe(unsubscriber Unsubscriber) {
 		logger.Warnf("Subscription %T Unsubscribe timed out.", unsubscriber)
 	}
 }
-
-func (sub ManagedSubscription) listenToLogs(q ethereum.FilterQuery) {
-	backfilledSet := sub.backfillLogs(q)
-	for {
-		select {
-		case log, open := <-sub.logs:
-			if !open {
-				return
-			}
-			if _, present := backfilledSet[log.BlockHash.String()]; !present {
-				sub.callback(log)
-			}
-		case err, ok := <-sub.ethSubscription.Err():
-			if ok {
-				logger.Errorw(fmt.Sprintf("Error in log subscription: %s", err.Error()), "err", err)
-			}
-		}
-	}
-}
-
-// Manually retrieve old logs since SubscribeToLogs(logs, filter) only returns newly
-// imported blocks: https://github.com/ethereum/go-ethereum/wiki/RPC-PUB-SUB#logs
-// Therefore TxManager.GetLogs does a one time retrieval of old logs.
-func (sub ManagedSubscription) backfillLogs(q ethereum.FilterQuery) map[string]bool {
-	backfilledSet := map[string]bool{}
-	if q.FromBlock == nil {
-		return backfilledSet
-	}
-
-	logs, err := sub.store.TxManager.GetLogs(q)
-	if err != nil {
-		logger.Errorw("Unable to backfill logs", "err", err, "fromBlock", q.FromBlock.String(), "toBlock", q.ToBlock.String())
-		return backfilledSet
-	}
-
-	for _, log := range logs {
-		backfilledSet[log.BlockHash.String()] = true
-		sub.callback(log)
-	}
-	return backfilledSet
-}
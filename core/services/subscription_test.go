@@ -1,9 +1,13 @@
 package services_test
 
 import (
+	"math/big"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/onsi/gomega"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/services"
@@ -11,6 +15,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
 )
 
 func TestServices_NewInitiatorSubscription_BackfillLogs(t *testing.T) {
@@ -21,15 +26,17 @@ func TestServices_NewInitiatorSubscription_BackfillLogs(t *testing.T) {
 	eth := cltest.MockEthOnStore(t, store)
 
 	job := cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
 	initr := job.Initiators[0]
 	log := cltest.LogFromFixture(t, "testdata/subscription_logs.json")
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{log})
 	eth.RegisterSubscription("logs")
 
 	var count int32
 	callback := func(*strpkg.Store, models.LogRequest) { atomic.AddInt32(&count, 1) }
 	fromBlock := cltest.Head(0)
-	sub, err := services.NewInitiatorSubscription(initr, job, store, fromBlock, callback)
+	sub, err := services.NewInitiatorSubscription(initr, job, store, services.NewLogBroadcaster(store), fromBlock, callback)
 	assert.NoError(t, err)
 	defer sub.Unsubscribe()
 
@@ -48,12 +55,13 @@ func TestServices_NewInitiatorSubscription_BackfillLogs_WithNoHead(t *testing.T)
 	eth := cltest.MockEthOnStore(t, store)
 
 	job := cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
 	initr := job.Initiators[0]
 	eth.RegisterSubscription("logs")
 
 	var count int32
 	callback := func(*strpkg.Store, models.LogRequest) { atomic.AddInt32(&count, 1) }
-	sub, err := services.NewInitiatorSubscription(initr, job, store, nil, callback)
+	sub, err := services.NewInitiatorSubscription(initr, job, store, services.NewLogBroadcaster(store), nil, callback)
 	assert.NoError(t, err)
 	defer sub.Unsubscribe()
 
@@ -69,9 +77,11 @@ func TestServices_NewInitiatorSubscription_PreventsDoubleDispatch(t *testing.T)
 	eth := cltest.MockEthOnStore(t, store)
 
 	job := cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
 	initr := job.Initiators[0]
 
 	log := cltest.LogFromFixture(t, "testdata/subscription_logs.json")
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{})
 	eth.Register("eth_getLogs", []models.Log{log}) // backfill
 	logsChan := make(chan models.Log)
 	eth.RegisterSubscription("logs", logsChan)
@@ -79,7 +89,7 @@ func TestServices_NewInitiatorSubscription_PreventsDoubleDispatch(t *testing.T)
 	var count int32
 	callback := func(*strpkg.Store, models.LogRequest) { atomic.AddInt32(&count, 1) }
 	head := cltest.Head(0)
-	sub, err := services.NewInitiatorSubscription(initr, job, store, head, callback)
+	sub, err := services.NewInitiatorSubscription(initr, job, store, services.NewLogBroadcaster(store), head, callback)
 	assert.NoError(t, err)
 	defer sub.Unsubscribe()
 
@@ -94,6 +104,95 @@ func TestServices_NewInitiatorSubscription_PreventsDoubleDispatch(t *testing.T)
 	g.Eventually(func() int32 { return atomic.LoadInt32(&count) }).Should(gomega.Equal(int32(2)))
 }
 
+func TestServices_NewInitiatorSubscription_ResumesFromLogCursor(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+
+	job := cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	initr := job.Initiators[0]
+	require.NoError(t, store.AdvanceLogCursor(initr.ID, 100))
+
+	consumed := cltest.LogFromFixture(t, "testdata/subscription_logs.json")
+	consumed.BlockNumber = 120
+	cl := models.NewConsumedLog(initr.ID, consumed)
+	require.NoError(t, store.ORM.DB.Create(&cl).Error)
+	missed := consumed
+	missed.BlockNumber, missed.BlockHash, missed.TxHash = 150, cltest.NewHash(), cltest.NewHash()
+
+	eth.Register("eth_getBlockByNumber", models.BlockHeader{Number: hexutil.Big(*big.NewInt(300))})
+	eth.Register("eth_getLogs", []models.Log{consumed, missed}, func(_ interface{}, data ...interface{}) error {
+		arg := data[0].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "0x64", arg["fromBlock"])
+		return nil
+	})
+	eth.RegisterSubscription("logs")
+
+	var count int32
+	callback := func(*strpkg.Store, models.LogRequest) { atomic.AddInt32(&count, 1) }
+	sub, err := services.NewInitiatorSubscription(initr, job, store, services.NewLogBroadcaster(store), cltest.Head(200), callback)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	eth.EventuallyAllCalled(t)
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() uint64 {
+		cursor, err := store.FindLogCursor(initr.ID)
+		require.NoError(t, err)
+		return cursor.BlockNumber
+	}).Should(gomega.Equal(uint64(251)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
+
+func TestServices_ReceiveLogRequest_ConsumesLogWithRun(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	initr := job.Initiators[0]
+	log := cltest.LogFromFixture(t, "testdata/subscription_logs.json")
+	le := models.InitiatorLogEvent{JobSpec: job, Initiator: initr, Log: log}
+
+	services.ReceiveLogRequest(store, le.LogRequest())
+	services.ReceiveLogRequest(store, le.LogRequest())
+
+	runs, err := store.JobRunsFor(job.ID)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+	consumed, err := store.LogConsumed(models.NewConsumedLog(initr.ID, log))
+	require.NoError(t, err)
+	assert.True(t, consumed)
+}
+
+func TestServices_ReceiveLogRequest_LeavesLogWithoutRunUnconsumed(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithLogInitiator()
+	job.EndAt = null.TimeFrom(time.Now().Add(-time.Hour))
+	require.NoError(t, store.CreateJob(&job))
+	initr := job.Initiators[0]
+	log := cltest.LogFromFixture(t, "testdata/subscription_logs.json")
+
+	le := models.InitiatorLogEvent{JobSpec: job, Initiator: initr, Log: log}
+	services.ReceiveLogRequest(store, le.LogRequest())
+
+	runs, err := store.JobRunsFor(job.ID)
+	require.NoError(t, err)
+	assert.Len(t, runs, 0)
+	consumed, err := store.LogConsumed(models.NewConsumedLog(initr.ID, log))
+	require.NoError(t, err)
+	assert.False(t, consumed)
+}
+
 func TestServices_ReceiveLogRequest_IgnoredLogWithRemovedFlag(t *testing.T) {
 	t.Parallel()
 
@@ -123,3 +222,120 @@ func TestServices_ReceiveLogRequest_IgnoredLogWithRemovedFlag(t *testing.T) {
 		return count - originalCount
 	}).Should(gomega.Equal(0))
 }
+
+func TestServices_ReceiveRequestLifecycleLog(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithRunLogInitiator()
+	job.Tasks = []models.TaskSpec{cltest.NewTask(t, "noop"), cltest.NewTask(t, "ethtx")}
+	require.NoError(t, store.CreateJob(&job))
+
+	requester := cltest.NewAddress()
+	createRun := func(requestID common.Hash, status models.RunStatus) models.JobRun {
+		run := job.NewRun(job.Initiators[0])
+		id := requestID.Hex()
+		run.RunRequest.RequestID = &id
+		run.RunRequest.Requester = &requester
+		run.Status = status
+		require.NoError(t, store.CreateJobRun(&run))
+		return run
+	}
+	lifecycleLog := func(topic, requestID common.Hash) models.Log {
+		return models.Log{Topics: []common.Hash{topic, requestID}, TxHash: cltest.NewHash()}
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		requestID, otherRequestID := cltest.NewHash(), cltest.NewHash()
+		pending := createRun(requestID, models.RunStatusPendingConfirmations)
+		completed := createRun(requestID, models.RunStatusCompleted)
+		other := createRun(otherRequestID, models.RunStatusPendingConfirmations)
+
+		services.ReceiveRequestLifecycleLog(store, job, lifecycleLog(models.CancelOracleRequestLogTopic, requestID))
+
+		run, err := store.FindJobRun(pending.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunStatusCancelled, run.Status)
+		assert.Equal(t, "Request "+requestID.Hex()+" was cancelled by its requester", run.Result.Error())
+		for _, tr := range run.TaskRuns {
+			assert.Equal(t, models.RunStatusCancelled, tr.Status)
+		}
+
+		run, err = store.FindJobRun(completed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunStatusCompleted, run.Status)
+		run, err = store.FindJobRun(other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunStatusPendingConfirmations, run.Status)
+	})
+
+	t.Run("fulfillment", func(t *testing.T) {
+		requestID := cltest.NewHash()
+		fulfilling := createRun(requestID, models.RunStatusPendingConfirmations)
+		tx := cltest.CreateTx(t, store, cltest.NewAddress(), 1)
+		require.NoError(t, store.DB.Model(tx).Update("surrogate_id", fulfilling.ID.String()).Error)
+		late := createRun(requestID, models.RunStatusPendingConfirmations)
+
+		// a ChainlinkFulfilled log from any other contract is ignored
+		spoofed := lifecycleLog(models.ChainlinkFulfilledTopic, requestID)
+		spoofed.Address = cltest.NewAddress()
+		services.ReceiveRequestLifecycleLog(store, job, spoofed)
+		run, err := store.FindJobRun(late.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunStatusPendingConfirmations, run.Status)
+
+		log := lifecycleLog(models.ChainlinkFulfilledTopic, requestID)
+		log.Address = requester
+		log.TxHash = tx.Hash
+		services.ReceiveRequestLifecycleLog(store, job, log)
+
+		run, err = store.FindJobRun(fulfilling.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunStatusPendingConfirmations, run.Status)
+		run, err = store.FindJobRun(late.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunStatusCancelled, run.Status)
+		assert.Equal(t, "Request "+requestID.Hex()+" was fulfilled by another node", run.Result.Error())
+	})
+}
+
+func TestServices_StartJobSubscription_WatchesRequestLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	eth := cltest.MockEthOnStore(t, store)
+
+	job := cltest.NewJobWithRunLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	run := job.NewRun(job.Initiators[0])
+	requestID := cltest.NewHash()
+	id := requestID.Hex()
+	run.RunRequest.RequestID = &id
+	run.Status = models.RunStatusPendingConfirmations
+	require.NoError(t, store.CreateJobRun(&run))
+
+	// The run log and cancellation listeners share the oracle's subscription,
+	// fulfillments are watched on every requester
+	logs := make(chan models.Log, 1)
+	eth.RegisterSubscription("logs", logs)
+	eth.RegisterSubscription("logs")
+
+	sub, err := services.StartJobSubscription(job, nil, store, services.NewLogBroadcaster(store))
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	eth.EventuallyAllCalled(t)
+
+	logs <- models.Log{
+		Address: job.Initiators[0].Address,
+		Topics:  []common.Hash{models.CancelOracleRequestLogTopic, requestID},
+	}
+
+	gomega.NewGomegaWithT(t).Eventually(func() models.RunStatus {
+		run, err := store.FindJobRun(run.ID)
+		require.NoError(t, err)
+		return run.Status
+	}).Should(gomega.Equal(models.RunStatusCancelled))
+}
@@ -1,16 +1,20 @@
 package services
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/utils"
 )
 
 // ValidateJob checks the job and its associated Initiators and Tasks for any
@@ -28,11 +32,16 @@ func ValidateJob(j models.JobSpec, store *store.Store) error {
 			fe.Merge(err)
 		}
 	}
-	for _, task := range j.Tasks {
+	for i, task := range j.Tasks {
 		if err := validateTask(task, store); err != nil {
 			fe.Merge(err)
+		} else if err := validateConditional(task, i, len(j.Tasks), store); err != nil {
+			fe.Merge(err)
 		}
 	}
+	if err := validateTaskGraph(j.Tasks); err != nil {
+		fe.Merge(err)
+	}
 	return fe.CoerceEmptyToNil()
 }
 
@@ -87,13 +96,19 @@ func ValidateInitiator(i models.Initiator, j models.JobSpec) error {
 		return validateCronInitiator(i)
 	case models.InitiatorExternal:
 		return validateExternalInitiator(i)
+	case models.InitiatorFluxMonitor:
+		return validateFluxMonitorInitiator(i)
+	case models.InitiatorBlocks:
+		return validateBlocksInitiator(i)
+	case models.InitiatorTxConfirmations:
+		return validateTxConfirmationsInitiator(i)
 	case models.InitiatorServiceAgreementExecutionLog:
 		return validateServiceAgreementInitiator(i, j)
+	case models.InitiatorEthLog:
+		return validateEthLogInitiator(i)
 	case models.InitiatorWeb:
 		fallthrough
 	case models.InitiatorRunLog:
-		fallthrough
-	case models.InitiatorEthLog:
 		return nil
 	default:
 		return models.NewJSONAPIErrorsWith(fmt.Sprintf("type %v does not exist", i.Type))
@@ -116,7 +131,19 @@ func validateCronInitiator(i models.Initiator) error {
 	if i.Schedule == "" {
 		return models.NewJSONAPIErrorsWith("Schedule must have a cron")
 	}
-	return nil
+	fe := models.NewJSONAPIErrors()
+	if _, err := time.LoadLocation(i.TimeZone); err != nil {
+		fe.Add(fmt.Sprintf("Cron timeZone is invalid: %v", err))
+	}
+	if i.Jitter < 0 {
+		fe.Add("Cron jitter cannot be negative")
+	}
+	switch i.CatchUp {
+	case "", models.CatchUpSkip, models.CatchUpRunOnce, models.CatchUpRunAll:
+	default:
+		fe.Add(fmt.Sprintf("Cron catchUp %s is not one of skip, run-once or run-all", i.CatchUp))
+	}
+	return fe.CoerceEmptyToNil()
 }
 
 func validateExternalInitiator(i models.Initiator) error {
@@ -126,6 +153,71 @@ func validateExternalInitiator(i models.Initiator) error {
 	return nil
 }
 
+// minimumFluxMonitorPollingInterval keeps flux monitors from flooding their
+// data sources with requests.
+const minimumFluxMonitorPollingInterval = time.Second
+
+func validateFluxMonitorInitiator(i models.Initiator) error {
+	fe := models.NewJSONAPIErrors()
+	if i.Address == utils.ZeroAddress {
+		fe.Add("FluxMonitor must have an address")
+	}
+	if i.Feeds == nil {
+		fe.Add("FluxMonitor must have feeds")
+	} else {
+		var feeds adapters.Aggregate
+		if err := json.Unmarshal([]byte(i.Feeds.String()), &feeds); err != nil {
+			fe.Add(fmt.Sprintf("FluxMonitor feeds are invalid: %v", err))
+		} else if len(feeds.Sources) == 0 {
+			fe.Add("FluxMonitor feeds must have at least one source")
+		}
+	}
+	if i.Threshold <= 0 {
+		fe.Add("FluxMonitor threshold must be a positive percentage")
+	}
+	if i.Precision < 0 {
+		fe.Add("FluxMonitor precision cannot be negative")
+	}
+	if i.PollingInterval.Duration() < minimumFluxMonitorPollingInterval {
+		fe.Add(fmt.Sprintf("FluxMonitor pollingInterval must be at least %v", minimumFluxMonitorPollingInterval))
+	}
+	if i.Heartbeat < 0 {
+		fe.Add("FluxMonitor heartbeat cannot be negative")
+	}
+	return fe.CoerceEmptyToNil()
+}
+
+func validateEthLogInitiator(i models.Initiator) error {
+	if i.EventABI == nil || i.EventABI.Anonymous || len(i.Topics) == 0 || len(i.Topics[0]) == 0 {
+		return nil
+	}
+	for _, topic := range i.Topics[0] {
+		if topic == i.EventABI.ID() {
+			return nil
+		}
+	}
+	return models.NewJSONAPIErrorsWith(fmt.Sprintf("EthLog topics do not match the %s event", i.EventABI.Sig()))
+}
+
+func validateBlocksInitiator(i models.Initiator) error {
+	fe := models.NewJSONAPIErrors()
+	if i.BlockInterval == 0 {
+		fe.Add("Blocks must have a blockInterval of at least 1")
+	}
+	return fe.CoerceEmptyToNil()
+}
+
+func validateTxConfirmationsInitiator(i models.Initiator) error {
+	fe := models.NewJSONAPIErrors()
+	if i.TxHash == (common.Hash{}) {
+		fe.Add("TxConfirmations must have a txHash")
+	}
+	if i.Confirmations == 0 {
+		fe.Add("TxConfirmations must have at least 1 confirmation")
+	}
+	return fe.CoerceEmptyToNil()
+}
+
 func validateServiceAgreementInitiator(i models.Initiator, j models.JobSpec) error {
 	fe := models.NewJSONAPIErrors()
 	if len(j.Initiators) != 1 {
@@ -144,7 +236,126 @@ func validateTask(task models.TaskSpec, store *store.Store) error {
 			return errors.New("EthTxABIEncode Adapter is not implemented yet")
 		}
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	if task.Retry != nil {
+		return task.Retry.Validate()
+	}
+	return nil
+}
+
+// validateConditional checks that the branches of a conditional task only
+// skip over tasks that follow it.
+func validateConditional(task models.TaskSpec, index, taskCount int, store *store.Store) error {
+	adapter, err := adapters.For(task, store)
+	if err != nil {
+		return err
+	}
+	conditional, ok := adapter.BaseAdapter.(*adapters.Conditional)
+	if !ok {
+		return nil
+	}
+	onTrue, onFalse := conditional.Branches()
+	if err := onTrue.Validate(index, taskCount); err != nil {
+		return err
+	}
+	return onFalse.Validate(index, taskCount)
+}
+
+var taskNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// validateTaskGraph checks that task names are unique identifiers, and that
+// the dependencies between tasks name other tasks of the job without forming
+// a cycle.
+func validateTaskGraph(tasks []models.TaskSpec) error {
+	names := map[string]int{}
+	for i, task := range tasks {
+		if task.Name == "" {
+			continue
+		}
+		if !taskNameRegex.MatchString(task.Name) || task.Name == "result" {
+			return fmt.Errorf("task name %q must be an identifier other than result", task.Name)
+		}
+		if _, ok := names[task.Name]; ok {
+			return fmt.Errorf("task name %s is used by more than one task", task.Name)
+		}
+		names[task.Name] = i
+	}
+
+	if !models.IsTaskGraph(tasks) {
+		return nil
+	}
+
+	dependencies := make([][]int, len(tasks))
+	for i, task := range tasks {
+		if task.Type == adapters.TaskTypeConditional {
+			return fmt.Errorf("task %d: conditional tasks cannot be used in a job whose tasks have dependencies", i)
+		}
+		for _, name := range task.DependsOn {
+			dependency, ok := names[name]
+			if !ok {
+				return fmt.Errorf("task %d depends on unknown task %s", i, name)
+			}
+			if dependency == i {
+				return fmt.Errorf("task %s cannot depend on itself", name)
+			}
+			dependencies[i] = append(dependencies[i], dependency)
+		}
+	}
+
+	if cycle := findTaskCycle(tasks, dependencies); cycle != nil {
+		return fmt.Errorf("task dependencies form a cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findTaskCycle returns the names of the tasks forming a dependency cycle,
+// starting and ending with the same task, or nil if there is none.
+func findTaskCycle(tasks []models.TaskSpec, dependencies [][]int) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(tasks))
+	var path []int
+
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		state[i] = visiting
+		path = append(path, i)
+		for _, dependency := range dependencies[i] {
+			switch state[dependency] {
+			case visiting:
+				start := len(path) - 1
+				for path[start] != dependency {
+					start--
+				}
+				var cycle []string
+				for _, j := range path[start:] {
+					cycle = append(cycle, tasks[j].Name)
+				}
+				return append(cycle, tasks[dependency].Name)
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range tasks {
+		if state[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
 }
 
 // ValidateServiceAgreement checks the ServiceAgreement for any application logic errors.
@@ -38,6 +38,26 @@ func TestValidateJob(t *testing.T) {
 			cltest.MustReadFile(t, "testdata/nonexistent_task_job.json"),
 			models.NewJSONAPIErrorsWith("idonotexist is not a supported adapter type"),
 		},
+		{
+			"error in task retry policy",
+			cltest.MustReadFile(t, "testdata/invalid_retry_job.json"),
+			models.NewJSONAPIErrorsWith("retry maxAttempts must be at least 1"),
+		},
+		{
+			"error in conditional branch",
+			cltest.MustReadFile(t, "testdata/invalid_conditional_job.json"),
+			models.NewJSONAPIErrorsWith("conditional task 0 cannot jump to task 5, it must be a later task"),
+		},
+		{
+			"cycle in task dependencies",
+			cltest.MustReadFile(t, "testdata/cyclic_task_graph_job.json"),
+			models.NewJSONAPIErrorsWith("task dependencies form a cycle: parse -> check -> parse"),
+		},
+		{
+			"unknown task dependency",
+			cltest.MustReadFile(t, "testdata/unknown_dependency_job.json"),
+			models.NewJSONAPIErrorsWith("task 1 depends on unknown task fecth"),
+		},
 		{
 			"zero initiators",
 			cltest.MustReadFile(t, "testdata/zero_initiators.json"),
@@ -192,6 +212,8 @@ func TestValidateInitiator(t *testing.T) {
 	}{
 		{"web", `{"type":"web"}`, false},
 		{"ethlog", `{"type":"ethlog"}`, false},
+		{"ethlog w event", `{"type":"ethlog","params":{"eventABI":"Transfer(address indexed from, address indexed to, uint256 value)","topics":[["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"]]}}`, false},
+		{"ethlog w event not matching topics", `{"type":"ethlog","params":{"eventABI":"Approval(address indexed owner, address indexed spender, uint256 value)","topics":[["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"]]}}`, true},
 		{"external", `{"type":"external","params":{"name":"bitcoin"}}`, false},
 		{"runlog", `{"type":"runlog"}`, false},
 		{"runat", fmt.Sprintf(`{"type":"runat","params": {"time":"%v"}}`, utils.ISO8601UTC(startAt)), false},
@@ -200,7 +222,22 @@ func TestValidateInitiator(t *testing.T) {
 		{"runat w time after end at", fmt.Sprintf(`{"type":"runat","params": {"time":"%v"}}`, endAt.Add(time.Second).Unix()), true},
 		{"cron", `{"type":"cron","params": {"schedule":"* * * * * *"}}`, false},
 		{"cron w/o schedule", `{"type":"cron"}`, true},
+		{"cron w time zone, jitter and catch up", `{"type":"cron","params": {"schedule":"0 0 9 * * *","timeZone":"America/New_York","jitter":"30s","catchUp":"run-once"}}`, false},
+		{"cron w invalid time zone", `{"type":"cron","params": {"schedule":"* * * * * *","timeZone":"Mars/Olympus_Mons"}}`, true},
+		{"cron w negative jitter", `{"type":"cron","params": {"schedule":"* * * * * *","jitter":"-1s"}}`, true},
+		{"cron w invalid catch up", `{"type":"cron","params": {"schedule":"* * * * * *","catchUp":"sometimes"}}`, true},
 		{"external w/o name", `{"type":"external"}`, true},
+		{"fluxmonitor", `{"type":"fluxmonitor","params":{"address":"0x3cCad4715152693fE3BC4460591e3D3Fbd071b42","feeds":{"sources":[{"tasks":[{"type":"noop"}]}]},"threshold":0.5,"precision":2,"pollingInterval":"1m","heartbeat":"1h"}}`, false},
+		{"fluxmonitor w/o address", `{"type":"fluxmonitor","params":{"feeds":{"sources":[{"tasks":[{"type":"noop"}]}]},"threshold":0.5,"pollingInterval":"1m"}}`, true},
+		{"fluxmonitor w/o feeds", `{"type":"fluxmonitor","params":{"address":"0x3cCad4715152693fE3BC4460591e3D3Fbd071b42","threshold":0.5,"pollingInterval":"1m"}}`, true},
+		{"fluxmonitor w/o sources", `{"type":"fluxmonitor","params":{"address":"0x3cCad4715152693fE3BC4460591e3D3Fbd071b42","feeds":{"sources":[]},"threshold":0.5,"pollingInterval":"1m"}}`, true},
+		{"fluxmonitor w/o threshold", `{"type":"fluxmonitor","params":{"address":"0x3cCad4715152693fE3BC4460591e3D3Fbd071b42","feeds":{"sources":[{"tasks":[{"type":"noop"}]}]},"pollingInterval":"1m"}}`, true},
+		{"fluxmonitor w short polling interval", `{"type":"fluxmonitor","params":{"address":"0x3cCad4715152693fE3BC4460591e3D3Fbd071b42","feeds":{"sources":[{"tasks":[{"type":"noop"}]}]},"threshold":0.5,"pollingInterval":"10ms"}}`, true},
+		{"blocks", `{"type":"blocks","params":{"blockInterval":10}}`, false},
+		{"blocks w/o blockInterval", `{"type":"blocks"}`, true},
+		{"txconfirmations", `{"type":"txconfirmations","params":{"txHash":"0xb7862c896a6ba2711bccc0410184e46d793ea83b3e05470f1d359ea276d16bb5","confirmations":12}}`, false},
+		{"txconfirmations w/o txHash", `{"type":"txconfirmations","params":{"confirmations":12}}`, true},
+		{"txconfirmations w/o confirmations", `{"type":"txconfirmations","params":{"txHash":"0xb7862c896a6ba2711bccc0410184e46d793ea83b3e05470f1d359ea276d16bb5"}}`, true},
 		{"non-existent initiator", `{"type":"doesntExist"}`, true},
 	}
 
@@ -22,8 +22,12 @@ type EthClient interface {
 	SendRawTx(hex string) (common.Hash, error)
 	GetTxReceipt(hash common.Hash) (*models.TxReceipt, error)
 	GetBlockByNumber(hex string) (models.BlockHeader, error)
+	GetBlockWithTransactions(hex string) (models.Block, error)
 	GetLogs(q ethereum.FilterQuery) ([]models.Log, error)
 	GetChainID() (*big.Int, error)
+	CallContract(msg CallMsg) ([]byte, error)
+	CallContractAt(msg CallMsg, block string) ([]byte, error)
+	EstimateGas(msg CallMsg) (uint64, error)
 	SubscribeToLogs(channel chan<- models.Log, q ethereum.FilterQuery) (models.EthSubscription, error)
 	SubscribeToNewHeads(channel chan<- models.BlockHeader) (models.EthSubscription, error)
 }
@@ -109,6 +113,14 @@ func (eth *EthCallerSubscriber) GetBlockByNumber(hex string) (models.BlockHeader
 	return header, err
 }
 
+// GetBlockWithTransactions returns the block for the passed hex, or "latest",
+// "earliest", "pending", along with its transactions.
+func (eth *EthCallerSubscriber) GetBlockWithTransactions(hex string) (models.Block, error) {
+	var block models.Block
+	err := eth.Call(&block, "eth_getBlockByNumber", hex, true)
+	return block, err
+}
+
 // GetLogs returns all logs that respect the passed filter query.
 func (eth *EthCallerSubscriber) GetLogs(q ethereum.FilterQuery) ([]models.Log, error) {
 	var results []models.Log
@@ -123,6 +135,35 @@ func (eth *EthCallerSubscriber) GetChainID() (*big.Int, error) {
 	return value.ToInt(), err
 }
 
+// CallMsg is a transaction to simulate with CallContract or EstimateGas.
+type CallMsg struct {
+	From common.Address `json:"from"`
+	To   common.Address `json:"to"`
+	Data hexutil.Bytes  `json:"data"`
+}
+
+// CallContract executes the message against the pending state without
+// creating a transaction, and returns what it returned.
+func (eth *EthCallerSubscriber) CallContract(msg CallMsg) ([]byte, error) {
+	return eth.CallContractAt(msg, "pending")
+}
+
+// CallContractAt executes the message against the state at the block, given
+// as a hex number or a tag such as "latest", and returns what it returned.
+func (eth *EthCallerSubscriber) CallContractAt(msg CallMsg, block string) ([]byte, error) {
+	var result hexutil.Bytes
+	err := eth.Call(&result, "eth_call", msg, block)
+	return result, err
+}
+
+// EstimateGas returns the gas the message would use if sent as a
+// transaction against the pending state.
+func (eth *EthCallerSubscriber) EstimateGas(msg CallMsg) (uint64, error) {
+	var result hexutil.Uint64
+	err := eth.Call(&result, "eth_estimateGas", msg)
+	return uint64(result), err
+}
+
 // SubscribeToLogs registers a subscription for push notifications of logs
 // from a given address.
 func (eth *EthCallerSubscriber) SubscribeToLogs(
@@ -0,0 +1,389 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+const (
+	// EthEndpointMaxConsecutiveErrors is the number of consecutive failed
+	// requests after which an endpoint is considered unhealthy.
+	EthEndpointMaxConsecutiveErrors = 3
+	// EthEndpointMaxHeadLag is the number of blocks an endpoint may fall
+	// behind the most advanced endpoint before it is considered unhealthy.
+	EthEndpointMaxHeadLag = 5
+	// EthEndpointHealthCheckPeriod is how often every endpoint is polled for
+	// its latest block number and latency.
+	EthEndpointHealthCheckPeriod = 15 * time.Second
+)
+
+// ErrNoEthEndpoint is returned when no configured endpoint can serve a request.
+var ErrNoEthEndpoint = errors.New("no Ethereum endpoint available")
+
+// ErrEthEndpointFailBack is delivered on a subscription's error channel when
+// a more preferred endpoint has recovered, so that the subscriber
+// resubscribes and lands back on it.
+var ErrEthEndpointFailBack = errors.New("failing back to preferred Ethereum endpoint")
+
+// EthEndpointHealth is a snapshot of the health of a single Ethereum endpoint.
+type EthEndpointHealth struct {
+	URL               string        `json:"url"`
+	Subscribable      bool          `json:"subscribable"`
+	Latency           time.Duration `json:"latency"`
+	ConsecutiveErrors uint64        `json:"consecutiveErrors"`
+	TotalErrors       uint64        `json:"totalErrors"`
+	LastError         string        `json:"lastError,omitempty"`
+	HeadNumber        uint64        `json:"headNumber"`
+	HeadLag           uint64        `json:"headLag"`
+	Healthy           bool          `json:"healthy"`
+}
+
+type ethEndpoint struct {
+	url          string
+	caller       CallerSubscriber
+	subscribable bool
+
+	mutex             sync.RWMutex
+	latency           time.Duration
+	consecutiveErrors uint64
+	totalErrors       uint64
+	lastError         error
+	headNumber        uint64
+}
+
+func newEthEndpoint(urlString string, caller CallerSubscriber) *ethEndpoint {
+	_, polling := caller.(*pollingCallerSubscriber)
+	return &ethEndpoint{
+		url:          urlString,
+		caller:       caller,
+		subscribable: isWebsocketURL(urlString) || polling,
+	}
+}
+
+// recordSuccess folds the latency of a successful request into an
+// exponentially weighted moving average and resets the error streak.
+func (ep *ethEndpoint) recordSuccess(elapsed time.Duration) {
+	ep.mutex.Lock()
+	defer ep.mutex.Unlock()
+	if ep.latency == 0 {
+		ep.latency = elapsed
+	} else {
+		ep.latency = (4*ep.latency + elapsed) / 5
+	}
+	ep.consecutiveErrors = 0
+}
+
+func (ep *ethEndpoint) recordError(err error) {
+	ep.mutex.Lock()
+	defer ep.mutex.Unlock()
+	ep.consecutiveErrors++
+	ep.totalErrors++
+	ep.lastError = err
+}
+
+func (ep *ethEndpoint) recordHead(number uint64) {
+	ep.mutex.Lock()
+	defer ep.mutex.Unlock()
+	ep.headNumber = number
+}
+
+func (ep *ethEndpoint) health(highestHead uint64) EthEndpointHealth {
+	ep.mutex.RLock()
+	defer ep.mutex.RUnlock()
+	h := EthEndpointHealth{
+		URL:               ep.url,
+		Subscribable:      ep.subscribable,
+		Latency:           ep.latency,
+		ConsecutiveErrors: ep.consecutiveErrors,
+		TotalErrors:       ep.totalErrors,
+		HeadNumber:        ep.headNumber,
+	}
+	if ep.lastError != nil {
+		h.LastError = ep.lastError.Error()
+	}
+	if highestHead > ep.headNumber {
+		h.HeadLag = highestHead - ep.headNumber
+	}
+	h.Healthy = h.ConsecutiveErrors < EthEndpointMaxConsecutiveErrors && h.HeadLag <= EthEndpointMaxHeadLag
+	return h
+}
+
+// failoverCallerSubscriber is a CallerSubscriber spread over a primary and
+// any number of fallback endpoints. Requests go to the first healthy
+// endpoint in order of preference, and are retried on the next endpoint
+// when the transport fails.
+type failoverCallerSubscriber struct {
+	endpoints     []*ethEndpoint
+	active        string
+	subscriptions map[*endpointSubscription]struct{}
+	mutex         sync.Mutex
+	done          chan struct{}
+	wg            sync.WaitGroup
+}
+
+func newFailoverCallerSubscriber(endpoints []*ethEndpoint) *failoverCallerSubscriber {
+	return &failoverCallerSubscriber{
+		endpoints:     endpoints,
+		subscriptions: make(map[*endpointSubscription]struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start begins periodically polling every endpoint for its head and latency,
+// moving subscriptions back to the preferred endpoint once it recovers.
+func (f *failoverCallerSubscriber) Start() error {
+	f.wg.Add(1)
+	go f.healthCheckLoop()
+	return nil
+}
+
+// Close stops polling the endpoints.
+func (f *failoverCallerSubscriber) Close() error {
+	close(f.done)
+	f.wg.Wait()
+	return nil
+}
+
+func (f *failoverCallerSubscriber) healthCheckLoop() {
+	defer f.wg.Done()
+	f.checkHealth()
+	ticker := time.NewTicker(EthEndpointHealthCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+			f.checkHealth()
+			f.failBack()
+		}
+	}
+}
+
+func (f *failoverCallerSubscriber) checkHealth() {
+	for _, ep := range f.endpoints {
+		var result hexutil.Uint64
+		start := time.Now()
+		if err := ep.caller.Call(&result, "eth_blockNumber"); err != nil {
+			ep.recordError(err)
+			logger.Warnw("Ethereum endpoint health check failed", "url", ep.url, "err", err)
+			continue
+		}
+		ep.recordSuccess(time.Since(start))
+		ep.recordHead(uint64(result))
+	}
+}
+
+// failBack ends every subscription that is not on the most preferred healthy
+// endpoint. Subscribers resubscribe when their subscription errors, which
+// moves them back onto that endpoint.
+func (f *failoverCallerSubscriber) failBack() {
+	candidates := f.candidates(true)
+	if len(candidates) == 0 || !f.healthy(candidates[0]) {
+		return
+	}
+	preferred := candidates[0]
+
+	f.mutex.Lock()
+	stale := []*endpointSubscription{}
+	for es := range f.subscriptions {
+		if es.endpoint != preferred {
+			stale = append(stale, es)
+		}
+	}
+	f.mutex.Unlock()
+
+	for _, es := range stale {
+		logger.Infow("Moving Ethereum subscription to preferred endpoint", "from", es.endpoint.url, "to", preferred.url)
+		es.fail(ErrEthEndpointFailBack)
+		es.EthSubscription.Unsubscribe()
+	}
+}
+
+// Health returns a snapshot of the health of every endpoint, in order of
+// preference.
+func (f *failoverCallerSubscriber) Health() []EthEndpointHealth {
+	var highest uint64
+	for _, ep := range f.endpoints {
+		ep.mutex.RLock()
+		if ep.headNumber > highest {
+			highest = ep.headNumber
+		}
+		ep.mutex.RUnlock()
+	}
+
+	healths := make([]EthEndpointHealth, len(f.endpoints))
+	for i, ep := range f.endpoints {
+		healths[i] = ep.health(highest)
+	}
+	return healths
+}
+
+func (f *failoverCallerSubscriber) healthy(ep *ethEndpoint) bool {
+	for i, h := range f.Health() {
+		if f.endpoints[i] == ep {
+			return h.Healthy
+		}
+	}
+	return false
+}
+
+// candidates returns the endpoints ordered by preference, with healthy
+// endpoints ahead of unhealthy ones, so that an unhealthy endpoint is only
+// used when nothing better is available.
+func (f *failoverCallerSubscriber) candidates(subscribable bool) []*ethEndpoint {
+	healths := f.Health()
+	type candidate struct {
+		endpoint *ethEndpoint
+		healthy  bool
+	}
+	cs := []candidate{}
+	for i, ep := range f.endpoints {
+		if subscribable && !ep.subscribable {
+			continue
+		}
+		cs = append(cs, candidate{ep, healths[i].Healthy})
+	}
+	sort.SliceStable(cs, func(i, j int) bool {
+		return cs[i].healthy && !cs[j].healthy
+	})
+
+	endpoints := make([]*ethEndpoint, len(cs))
+	for i, c := range cs {
+		endpoints[i] = c.endpoint
+	}
+	return endpoints
+}
+
+func (f *failoverCallerSubscriber) use(ep *ethEndpoint) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.active != ep.url {
+		if f.active != "" {
+			logger.Warnw("Failing over to Ethereum endpoint", "from", f.active, "to", ep.url)
+		}
+		f.active = ep.url
+	}
+}
+
+// Call performs the JSON-RPC call on the preferred endpoint, moving on to
+// the next endpoint if the request could not be delivered. Errors returned
+// by the node itself, such as a reverted call, are passed straight back.
+func (f *failoverCallerSubscriber) Call(result interface{}, method string, args ...interface{}) error {
+	err := ErrNoEthEndpoint
+	for _, ep := range f.candidates(false) {
+		start := time.Now()
+		err = ep.caller.Call(result, method, args...)
+		if err == nil || isRPCError(err) {
+			ep.recordSuccess(time.Since(start))
+			f.use(ep)
+			return err
+		}
+		ep.recordError(err)
+		logger.Warnw(fmt.Sprintf("Ethereum endpoint failed to serve %s", method), "url", ep.url, "err", err)
+	}
+	return err
+}
+
+// EthSubscribe registers the subscription on the preferred websocket
+// endpoint. When the subscription later fails, the endpoint is penalized so
+// that the subscriber's next attempt lands on a healthier endpoint.
+func (f *failoverCallerSubscriber) EthSubscribe(
+	ctx context.Context, channel interface{}, args ...interface{},
+) (models.EthSubscription, error) {
+	err := ErrNoEthEndpoint
+	for _, ep := range f.candidates(true) {
+		var sub models.EthSubscription
+		sub, err = ep.caller.EthSubscribe(ctx, channel, args...)
+		if err == nil {
+			f.use(ep)
+			return f.track(newEndpointSubscription(ep, sub)), nil
+		}
+		ep.recordError(err)
+		logger.Warnw("Ethereum endpoint failed to subscribe", "url", ep.url, "err", err)
+	}
+	return nil, err
+}
+
+// track registers the subscription for failing back until it ends.
+func (f *failoverCallerSubscriber) track(es *endpointSubscription) *endpointSubscription {
+	f.mutex.Lock()
+	f.subscriptions[es] = struct{}{}
+	f.mutex.Unlock()
+
+	go func() {
+		<-es.ended
+		f.mutex.Lock()
+		delete(f.subscriptions, es)
+		f.mutex.Unlock()
+	}()
+	return es
+}
+
+// endpointSubscription wraps a subscription to record its failure against
+// the endpoint it was registered on.
+type endpointSubscription struct {
+	models.EthSubscription
+	endpoint *ethEndpoint
+	errors   chan error
+	ended    chan struct{}
+	once     sync.Once
+}
+
+func newEndpointSubscription(ep *ethEndpoint, sub models.EthSubscription) *endpointSubscription {
+	es := &endpointSubscription{
+		EthSubscription: sub,
+		endpoint:        ep,
+		errors:          make(chan error, 1),
+		ended:           make(chan struct{}),
+	}
+	go func() {
+		defer close(es.ended)
+		err, ok := <-sub.Err()
+		if ok && err != nil {
+			ep.recordError(err)
+			es.fail(err)
+		}
+		es.once.Do(func() {})
+		close(es.errors)
+	}()
+	return es
+}
+
+// fail delivers the first error to the subscriber; any later error, or an
+// error after the subscription has ended, is dropped.
+func (es *endpointSubscription) fail(err error) {
+	es.once.Do(func() {
+		es.errors <- err
+	})
+}
+
+func (es *endpointSubscription) Err() <-chan error {
+	return es.errors
+}
+
+// isRPCError reports whether the error was returned by the node in a
+// JSON-RPC error response, as opposed to a transport failure.
+func isRPCError(err error) bool {
+	_, ok := err.(interface{ ErrorCode() int })
+	return ok
+}
+
+func isWebsocketURL(urlString string) bool {
+	parsed, err := url.Parse(urlString)
+	return err == nil && (parsed.Scheme == "ws" || parsed.Scheme == "wss")
+}
+
+func isHTTPURL(urlString string) bool {
+	parsed, err := url.Parse(urlString)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https")
+}
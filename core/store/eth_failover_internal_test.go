@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rpcCodeError struct{ msg string }
+
+func (e rpcCodeError) Error() string  { return e.msg }
+func (e rpcCodeError) ErrorCode() int { return -32000 }
+
+type fakeSubscription struct {
+	errors chan error
+	once   sync.Once
+}
+
+func (s *fakeSubscription) Err() <-chan error { return s.errors }
+func (s *fakeSubscription) Unsubscribe()      { s.once.Do(func() { close(s.errors) }) }
+
+type fakeCallerSubscriber struct {
+	head     uint64
+	err      error
+	calls    int
+	sub      *fakeSubscription
+	subCalls int
+}
+
+func (f *fakeCallerSubscriber) Call(result interface{}, method string, args ...interface{}) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	if ptr, ok := result.(*hexutil.Uint64); ok {
+		*ptr = hexutil.Uint64(f.head)
+	}
+	return nil
+}
+
+func (f *fakeCallerSubscriber) EthSubscribe(context.Context, interface{}, ...interface{}) (models.EthSubscription, error) {
+	f.subCalls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.sub = &fakeSubscription{errors: make(chan error, 1)}
+	return f.sub, nil
+}
+
+func TestFailoverCallerSubscriber_Call_FailsOverOnTransportError(t *testing.T) {
+	t.Parallel()
+
+	primary := &fakeCallerSubscriber{err: errors.New("connection refused")}
+	secondary := &fakeCallerSubscriber{head: 10}
+	f := newFailoverCallerSubscriber([]*ethEndpoint{
+		newEthEndpoint("ws://primary", primary),
+		newEthEndpoint("http://secondary", secondary),
+	})
+
+	var result hexutil.Uint64
+	require.NoError(t, f.Call(&result, "eth_blockNumber"))
+	assert.Equal(t, hexutil.Uint64(10), result)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, secondary.calls)
+
+	health := f.Health()
+	assert.Equal(t, uint64(1), health[0].ConsecutiveErrors)
+	assert.Equal(t, "connection refused", health[0].LastError)
+	assert.Equal(t, uint64(0), health[1].ConsecutiveErrors)
+}
+
+func TestFailoverCallerSubscriber_Call_ReturnsRPCErrors(t *testing.T) {
+	t.Parallel()
+
+	primary := &fakeCallerSubscriber{err: rpcCodeError{"nonce too low"}}
+	secondary := &fakeCallerSubscriber{}
+	f := newFailoverCallerSubscriber([]*ethEndpoint{
+		newEthEndpoint("ws://primary", primary),
+		newEthEndpoint("ws://secondary", secondary),
+	})
+
+	err := f.Call(nil, "eth_sendRawTransaction", "0x")
+	assert.EqualError(t, err, "nonce too low")
+	assert.Equal(t, 0, secondary.calls)
+	assert.True(t, f.Health()[0].Healthy)
+}
+
+func TestFailoverCallerSubscriber_Call_PrefersHealthyEndpoints(t *testing.T) {
+	t.Parallel()
+
+	primary := &fakeCallerSubscriber{head: 100}
+	secondary := &fakeCallerSubscriber{head: 120}
+	f := newFailoverCallerSubscriber([]*ethEndpoint{
+		newEthEndpoint("ws://primary", primary),
+		newEthEndpoint("ws://secondary", secondary),
+	})
+
+	f.checkHealth()
+	health := f.Health()
+	assert.Equal(t, uint64(20), health[0].HeadLag)
+	assert.False(t, health[0].Healthy)
+	assert.True(t, health[1].Healthy)
+
+	require.NoError(t, f.Call(nil, "eth_chainId"))
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 2, secondary.calls)
+
+	primary.head = 120
+	f.checkHealth()
+	require.NoError(t, f.Call(nil, "eth_chainId"))
+	assert.Equal(t, 3, primary.calls)
+}
+
+func TestFailoverCallerSubscriber_EthSubscribe(t *testing.T) {
+	t.Parallel()
+
+	primary := &fakeCallerSubscriber{}
+	httpOnly := &fakeCallerSubscriber{}
+	secondary := &fakeCallerSubscriber{}
+	f := newFailoverCallerSubscriber([]*ethEndpoint{
+		newEthEndpoint("ws://primary", primary),
+		newEthEndpoint("http://secondary", httpOnly),
+		newEthEndpoint("wss://tertiary", secondary),
+	})
+
+	sub, err := f.EthSubscribe(context.Background(), make(chan models.BlockHeader), "newHeads")
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.subCalls)
+
+	for i := 0; i < EthEndpointMaxConsecutiveErrors; i++ {
+		primary.sub.errors <- errors.New("websocket closed")
+		assert.Error(t, <-sub.Err())
+
+		sub, err = f.EthSubscribe(context.Background(), make(chan models.BlockHeader), "newHeads")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, EthEndpointMaxConsecutiveErrors, primary.subCalls)
+	assert.Equal(t, 0, httpOnly.subCalls)
+	assert.Equal(t, 1, secondary.subCalls)
+}
+
+func TestFailoverCallerSubscriber_FailBack(t *testing.T) {
+	t.Parallel()
+
+	primary := &fakeCallerSubscriber{err: errors.New("connection refused")}
+	secondary := &fakeCallerSubscriber{}
+	f := newFailoverCallerSubscriber([]*ethEndpoint{
+		newEthEndpoint("ws://primary", primary),
+		newEthEndpoint("wss://secondary", secondary),
+	})
+
+	f.checkHealth()
+	f.checkHealth()
+	f.checkHealth()
+	sub, err := f.EthSubscribe(context.Background(), make(chan models.BlockHeader), "newHeads")
+	require.NoError(t, err)
+	assert.Equal(t, 0, primary.subCalls)
+	assert.Equal(t, 1, secondary.subCalls)
+
+	f.failBack()
+	select {
+	case err := <-sub.Err():
+		t.Fatalf("subscription ended while the primary is unhealthy: %v", err)
+	default:
+	}
+
+	primary.err = nil
+	f.checkHealth()
+	f.failBack()
+	assert.Equal(t, ErrEthEndpointFailBack, <-sub.Err())
+	sub.Unsubscribe()
+
+	_, err = f.EthSubscribe(context.Background(), make(chan models.BlockHeader), "newHeads")
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.subCalls)
+}
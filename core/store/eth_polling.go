@@ -0,0 +1,388 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// defaultPollInterval is the interval polled at when none, or a negative
+// one, is configured, which time.NewTicker does not accept.
+const defaultPollInterval = 5 * time.Second
+
+// maxPollFailures is how many polls in a row can fail before the
+// subscription errors, so that its subscriber moves to another endpoint.
+const maxPollFailures = 3
+
+// pollReorgWindow is how many of the latest delivered blocks are remembered
+// to detect that they were reorged out.
+const pollReorgWindow = 100
+
+// pollingCallerSubscriber is a CallerSubscriber for an Ethereum node served
+// over HTTP, which has no pubsub. It emulates the newHeads and logs
+// subscriptions by polling the node's block number, delivering the header
+// of, and the logs in, every new block once and in order.
+//
+// Like a websocket subscription, it follows reorgs: when a delivered block is
+// no longer on the chain, the logs delivered from the blocks past the fork
+// are delivered again with Removed set, and the heads and logs of the new
+// blocks are delivered.
+type pollingCallerSubscriber struct {
+	CallerSubscriber
+	interval   time.Duration
+	blockRange uint64
+}
+
+func newPollingCallerSubscriber(caller CallerSubscriber, interval time.Duration, blockRange uint64) *pollingCallerSubscriber {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	if blockRange == 0 {
+		blockRange = 1
+	}
+	return &pollingCallerSubscriber{
+		CallerSubscriber: caller,
+		interval:         interval,
+		blockRange:       blockRange,
+	}
+}
+
+// EthSubscribe starts polling for the heads or logs of blocks after the
+// current one, as a websocket subscription only pushes those of blocks
+// imported after it was registered.
+func (p *pollingCallerSubscriber) EthSubscribe(
+	ctx context.Context, channel interface{}, args ...interface{},
+) (models.EthSubscription, error) {
+	if len(args) == 0 {
+		return nil, errors.New("pollingCallerSubscriber#EthSubscribe: missing subscription name")
+	}
+
+	var deliver deliverFunc
+	var removed chan<- models.Log
+	switch args[0] {
+	case "newHeads":
+		heads, ok := channel.(chan<- models.BlockHeader)
+		if !ok {
+			return nil, fmt.Errorf("pollingCallerSubscriber#EthSubscribe: newHeads requires a chan<- models.BlockHeader, got %T", channel)
+		}
+		deliver = p.deliverHeads(heads)
+	case "logs":
+		logs, ok := channel.(chan<- models.Log)
+		if !ok {
+			return nil, fmt.Errorf("pollingCallerSubscriber#EthSubscribe: logs requires a chan<- models.Log, got %T", channel)
+		}
+		var filter map[string]interface{}
+		if len(args) > 1 {
+			filter, _ = args[1].(map[string]interface{})
+		}
+		if filter == nil {
+			return nil, errors.New("pollingCallerSubscriber#EthSubscribe: logs requires a filter")
+		}
+		deliver = p.deliverLogs(logs, filter)
+		removed = logs
+	default:
+		return nil, fmt.Errorf("pollingCallerSubscriber#EthSubscribe: unsupported subscription %v", args[0])
+	}
+
+	current, err := p.blockNumber()
+	if err != nil {
+		return nil, errors.Wrap(err, "pollingCallerSubscriber#EthSubscribe")
+	}
+
+	sub := &pollingSubscription{
+		poller:  p,
+		deliver: deliver,
+		removed: removed,
+		next:    current + 1,
+		blocks:  newPolledBlocks(),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	sub.wg.Add(1)
+	go sub.run(ctx)
+	return sub, nil
+}
+
+func (p *pollingCallerSubscriber) blockNumber() (uint64, error) {
+	var result hexutil.Uint64
+	err := p.Call(&result, "eth_blockNumber")
+	return uint64(result), err
+}
+
+func (p *pollingCallerSubscriber) blockHash(number uint64) (common.Hash, error) {
+	header, err := p.header(number)
+	return header.Hash(), err
+}
+
+func (p *pollingCallerSubscriber) header(number uint64) (models.BlockHeader, error) {
+	var header models.BlockHeader
+	if err := p.Call(&header, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false); err != nil {
+		return header, err
+	}
+	if header.Hash() == (common.Hash{}) {
+		return header, fmt.Errorf("eth_getBlockByNumber returned no block %d", number)
+	}
+	return header, nil
+}
+
+// deliverFunc delivers what the subscription emits for blocks from to to,
+// inclusive, recording the hashes of the blocks and the logs delivered,
+// and returns how many blocks were delivered before an error or the
+// subscription being stopped.
+type deliverFunc func(from, to uint64, blocks *polledBlocks, done <-chan struct{}) (uint64, error)
+
+func (p *pollingCallerSubscriber) deliverHeads(heads chan<- models.BlockHeader) deliverFunc {
+	return func(from, to uint64, blocks *polledBlocks, done <-chan struct{}) (uint64, error) {
+		for number := from; number <= to; number++ {
+			header, err := p.header(number)
+			if err != nil {
+				return number - from, err
+			}
+			blocks.record(number, header.Hash())
+			select {
+			case heads <- header:
+			case <-done:
+				return number - from, nil
+			}
+		}
+		return to - from + 1, nil
+	}
+}
+
+// deliverLogs requests the logs in chunks of at most blockRange blocks, so
+// that catching up on many blocks does not exceed the node's limits. The
+// hash of the last block of each chunk is recorded along with those of the
+// blocks with logs, so that a reorg is detected even without logs.
+func (p *pollingCallerSubscriber) deliverLogs(logs chan<- models.Log, filter map[string]interface{}) deliverFunc {
+	return func(from, to uint64, blocks *polledBlocks, done <-chan struct{}) (uint64, error) {
+		for start := from; start <= to; start += p.blockRange {
+			end := start + p.blockRange - 1
+			if end > to {
+				end = to
+			}
+
+			hash, err := p.blockHash(end)
+			if err != nil {
+				return start - from, err
+			}
+
+			arg := make(map[string]interface{}, len(filter))
+			for k, v := range filter {
+				arg[k] = v
+			}
+			arg["fromBlock"] = hexutil.EncodeUint64(start)
+			arg["toBlock"] = hexutil.EncodeUint64(end)
+
+			var results []models.Log
+			if err := p.Call(&results, "eth_getLogs", arg); err != nil {
+				return start - from, err
+			}
+			blocks.record(end, hash)
+			for _, log := range results {
+				select {
+				case logs <- log:
+					blocks.recordLog(log)
+				case <-done:
+					return start - from, nil
+				}
+			}
+		}
+		return to - from + 1, nil
+	}
+}
+
+// polledBlocks remembers the hashes of the latest delivered blocks, and the
+// logs delivered in them, to rewind them when they are reorged out.
+type polledBlocks struct {
+	hashes map[uint64]common.Hash
+	logs   []models.Log
+}
+
+func newPolledBlocks() *polledBlocks {
+	return &polledBlocks{hashes: map[uint64]common.Hash{}}
+}
+
+func (b *polledBlocks) record(number uint64, hash common.Hash) {
+	b.hashes[number] = hash
+}
+
+func (b *polledBlocks) recordLog(log models.Log) {
+	if log.BlockHash != (common.Hash{}) {
+		b.record(log.BlockNumber, log.BlockHash)
+	}
+	b.logs = append(b.logs, log)
+}
+
+// numbers returns the numbers of the remembered blocks, latest first.
+func (b *polledBlocks) numbers() []uint64 {
+	numbers := make([]uint64, 0, len(b.hashes))
+	for number := range b.hashes {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] > numbers[j] })
+	return numbers
+}
+
+// forgetBefore forgets the blocks, and their logs, before the given one.
+func (b *polledBlocks) forgetBefore(number uint64) {
+	for n := range b.hashes {
+		if n < number {
+			delete(b.hashes, n)
+		}
+	}
+	i := 0
+	for i < len(b.logs) && b.logs[i].BlockNumber < number {
+		i++
+	}
+	b.logs = b.logs[i:]
+}
+
+// rewind forgets the blocks after the fork, returning the logs delivered in
+// them, latest first.
+func (b *polledBlocks) rewind(fork uint64) []models.Log {
+	for n := range b.hashes {
+		if n > fork {
+			delete(b.hashes, n)
+		}
+	}
+	var removed []models.Log
+	for len(b.logs) > 0 && b.logs[len(b.logs)-1].BlockNumber > fork {
+		removed = append(removed, b.logs[len(b.logs)-1])
+		b.logs = b.logs[:len(b.logs)-1]
+	}
+	return removed
+}
+
+// pollingSubscription is the models.EthSubscription of a
+// pollingCallerSubscriber. Failed polls are logged and retried from the
+// first undelivered block on the next tick, until maxPollFailures polls in a
+// row have failed, which ends the subscription with the last error.
+type pollingSubscription struct {
+	poller   *pollingCallerSubscriber
+	deliver  deliverFunc
+	removed  chan<- models.Log
+	next     uint64
+	blocks   *polledBlocks
+	failures int
+	errors   chan error
+	done     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+}
+
+func (sub *pollingSubscription) run(ctx context.Context) {
+	defer sub.wg.Done()
+	defer close(sub.errors)
+	ticker := time.NewTicker(sub.poller.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sub.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := sub.poll(); err != nil {
+				sub.errors <- err
+				return
+			}
+		}
+	}
+}
+
+// poll delivers everything since the last successful poll, returning an
+// error once too many polls in a row have failed.
+func (sub *pollingSubscription) poll() error {
+	err := sub.pollOnce()
+	if err == nil {
+		sub.failures = 0
+		return nil
+	}
+
+	sub.failures++
+	if sub.failures >= maxPollFailures {
+		logger.Errorw(fmt.Sprintf("Unable to poll Ethereum node %d times in a row, ending subscription", sub.failures), "err", err)
+		return errors.Wrap(err, "pollingSubscription#poll")
+	}
+	logger.Warnw("Unable to poll Ethereum node, retrying on the next poll", "err", err, "fromBlock", sub.next)
+	return nil
+}
+
+func (sub *pollingSubscription) pollOnce() error {
+	latest, err := sub.poller.blockNumber()
+	if err != nil {
+		return errors.Wrap(err, "eth_blockNumber")
+	}
+	if err := sub.followReorg(); err != nil {
+		return err
+	}
+	if latest < sub.next {
+		return nil
+	}
+
+	delivered, err := sub.deliver(sub.next, latest, sub.blocks, sub.done)
+	sub.next += delivered
+	if sub.next > pollReorgWindow {
+		sub.blocks.forgetBefore(sub.next - pollReorgWindow)
+	}
+	return err
+}
+
+// followReorg checks that the latest delivered block is still on the chain,
+// and otherwise walks back to the latest one that is, delivers the logs past
+// it as removed, and moves the subscription back to the block after it.
+func (sub *pollingSubscription) followReorg() error {
+	numbers := sub.blocks.numbers()
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	fork := numbers[len(numbers)-1] - 1
+	for _, number := range numbers {
+		hash, err := sub.poller.blockHash(number)
+		if err != nil {
+			return errors.Wrap(err, "pollingSubscription#followReorg")
+		}
+		if hash == sub.blocks.hashes[number] {
+			fork = number
+			break
+		}
+	}
+	if fork == numbers[0] {
+		return nil
+	}
+
+	logger.Warnw(fmt.Sprintf("Blocks after %d were reorged out, polling them again", fork), "fromBlock", fork+1, "toBlock", sub.next-1)
+	for _, log := range sub.blocks.rewind(fork) {
+		log.Removed = true
+		select {
+		case sub.removed <- log:
+		case <-sub.done:
+			return nil
+		}
+	}
+	sub.next = fork + 1
+	return nil
+}
+
+// Err returns the error that ended the subscription after too many failed
+// polls, and is closed once the subscription ends.
+func (sub *pollingSubscription) Err() <-chan error {
+	return sub.errors
+}
+
+// Unsubscribe stops polling.
+func (sub *pollingSubscription) Unsubscribe() {
+	sub.once.Do(func() {
+		close(sub.done)
+	})
+	sub.wg.Wait()
+}
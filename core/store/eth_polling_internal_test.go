@@ -0,0 +1,316 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePollingNode is an HTTP node with a log in every block.
+type fakePollingNode struct {
+	mutex         sync.Mutex
+	head          uint64
+	ranges        [][2]uint64
+	getLogsErrors int
+	missingBlocks int
+	down          bool
+	forkedAt      uint64
+	forks         uint64
+}
+
+// reorg replaces the blocks from the given one on with blocks of a new fork.
+func (f *fakePollingNode) reorg(from uint64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.forkedAt = from
+	f.forks++
+}
+
+func (f *fakePollingNode) blockHash(number uint64) common.Hash {
+	hash := new(big.Int).SetUint64(number)
+	if f.forks > 0 && number >= f.forkedAt {
+		hash.Add(hash, new(big.Int).Lsh(new(big.Int).SetUint64(f.forks), 64))
+	}
+	return common.BigToHash(hash)
+}
+
+func (f *fakePollingNode) setDown(down bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.down = down
+}
+
+func (f *fakePollingNode) setHead(head uint64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.head = head
+}
+
+func (f *fakePollingNode) requestedRanges() [][2]uint64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([][2]uint64{}, f.ranges...)
+}
+
+func (f *fakePollingNode) Call(result interface{}, method string, args ...interface{}) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.down {
+		return errors.New("connection refused")
+	}
+	switch method {
+	case "eth_blockNumber":
+		*result.(*hexutil.Uint64) = hexutil.Uint64(f.head)
+	case "eth_getBlockByNumber":
+		number, err := hexutil.DecodeUint64(args[0].(string))
+		if err != nil {
+			return err
+		}
+		if f.missingBlocks > 0 {
+			f.missingBlocks--
+			*result.(*models.BlockHeader) = models.BlockHeader{}
+			return nil
+		}
+		*result.(*models.BlockHeader) = models.BlockHeader{
+			Number:     hexutil.Big(*new(big.Int).SetUint64(number)),
+			ParityHash: f.blockHash(number),
+		}
+	case "eth_getLogs":
+		if f.getLogsErrors > 0 {
+			f.getLogsErrors--
+			return errors.New("request timed out")
+		}
+		filter := args[0].(map[string]interface{})
+		from, _ := hexutil.DecodeUint64(filter["fromBlock"].(string))
+		to, _ := hexutil.DecodeUint64(filter["toBlock"].(string))
+		f.ranges = append(f.ranges, [2]uint64{from, to})
+		logs := []models.Log{}
+		for number := from; number <= to; number++ {
+			logs = append(logs, models.Log{BlockNumber: number, BlockHash: f.blockHash(number)})
+		}
+		*result.(*[]models.Log) = logs
+	}
+	return nil
+}
+
+func (f *fakePollingNode) EthSubscribe(context.Context, interface{}, ...interface{}) (models.EthSubscription, error) {
+	return nil, errors.New("notifications not supported")
+}
+
+func TestPollingCallerSubscriber_NewHeads(t *testing.T) {
+	t.Parallel()
+
+	node := &fakePollingNode{head: 10}
+	p := newPollingCallerSubscriber(node, 10*time.Millisecond, 100)
+
+	heads := make(chan models.BlockHeader, 10)
+	sub, err := p.EthSubscribe(context.Background(), (chan<- models.BlockHeader)(heads), "newHeads")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	node.setHead(13)
+	for _, want := range []int64{11, 12, 13} {
+		select {
+		case head := <-heads:
+			assert.Equal(t, want, head.Number.ToInt().Int64())
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for head %d", want)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, heads, 0)
+}
+
+func TestPollingCallerSubscriber_NewHeads_RetriesMissingBlock(t *testing.T) {
+	t.Parallel()
+
+	node := &fakePollingNode{head: 10, missingBlocks: 1}
+	p := newPollingCallerSubscriber(node, 10*time.Millisecond, 100)
+
+	heads := make(chan models.BlockHeader, 10)
+	sub, err := p.EthSubscribe(context.Background(), (chan<- models.BlockHeader)(heads), "newHeads")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	node.setHead(12)
+	for _, want := range []int64{11, 12} {
+		select {
+		case head := <-heads:
+			assert.Equal(t, want, head.Number.ToInt().Int64())
+			assert.NotEqual(t, common.Hash{}, head.Hash())
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for head %d", want)
+		}
+	}
+}
+
+func TestPollingCallerSubscriber_ErrorsAfterRepeatedFailures(t *testing.T) {
+	t.Parallel()
+
+	node := &fakePollingNode{head: 10}
+	p := newPollingCallerSubscriber(node, 10*time.Millisecond, 100)
+
+	heads := make(chan models.BlockHeader, 10)
+	sub, err := p.EthSubscribe(context.Background(), (chan<- models.BlockHeader)(heads), "newHeads")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	node.setDown(true)
+	select {
+	case err := <-sub.Err():
+		assert.EqualError(t, err, "pollingSubscription#poll: eth_blockNumber: connection refused")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the subscription to error")
+	}
+	_, open := <-sub.Err()
+	assert.False(t, open)
+}
+
+func TestNewPollingCallerSubscriber_DefaultsInterval(t *testing.T) {
+	t.Parallel()
+
+	p := newPollingCallerSubscriber(&fakePollingNode{}, 0, 0)
+	assert.Equal(t, defaultPollInterval, p.interval)
+	assert.Equal(t, uint64(1), p.blockRange)
+	p = newPollingCallerSubscriber(&fakePollingNode{}, -time.Second, 100)
+	assert.Equal(t, defaultPollInterval, p.interval)
+}
+
+func TestPollingCallerSubscriber_Logs_ChunksAndRetries(t *testing.T) {
+	t.Parallel()
+
+	node := &fakePollingNode{head: 10, getLogsErrors: 1}
+	p := newPollingCallerSubscriber(node, 10*time.Millisecond, 2)
+
+	logs := make(chan models.Log, 10)
+	filter := map[string]interface{}{"fromBlock": "0x0", "toBlock": "latest"}
+	sub, err := p.EthSubscribe(context.Background(), (chan<- models.Log)(logs), "logs", filter)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	node.setHead(15)
+	for _, want := range []uint64{11, 12, 13, 14, 15} {
+		select {
+		case log := <-logs:
+			assert.Equal(t, want, log.BlockNumber)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for log in block %d", want)
+		}
+	}
+
+	g := gomega.NewGomegaWithT(t)
+	g.Consistently(func() int { return len(logs) }).Should(gomega.Equal(0))
+	assert.Equal(t, [][2]uint64{{11, 12}, {13, 14}, {15, 15}}, node.requestedRanges())
+	assert.Equal(t, "0x0", filter["fromBlock"])
+}
+
+func TestPollingCallerSubscriber_NewHeads_FollowsReorg(t *testing.T) {
+	t.Parallel()
+
+	node := &fakePollingNode{head: 10}
+	p := newPollingCallerSubscriber(node, 10*time.Millisecond, 100)
+
+	heads := make(chan models.BlockHeader, 10)
+	sub, err := p.EthSubscribe(context.Background(), (chan<- models.BlockHeader)(heads), "newHeads")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	receive := func(want uint64) models.BlockHeader {
+		select {
+		case head := <-heads:
+			assert.Equal(t, int64(want), head.Number.ToInt().Int64())
+			return head
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for head %d", want)
+		}
+		return models.BlockHeader{}
+	}
+
+	node.setHead(13)
+	var orphaned []common.Hash
+	for _, want := range []uint64{11, 12, 13} {
+		orphaned = append(orphaned, receive(want).Hash())
+	}
+
+	node.reorg(12)
+	for _, want := range []uint64{12, 13} {
+		head := receive(want)
+		assert.NotContains(t, orphaned, head.Hash())
+	}
+}
+
+func TestPollingCallerSubscriber_Logs_FollowsReorg(t *testing.T) {
+	t.Parallel()
+
+	node := &fakePollingNode{head: 10}
+	p := newPollingCallerSubscriber(node, 10*time.Millisecond, 100)
+
+	logs := make(chan models.Log, 10)
+	filter := map[string]interface{}{}
+	sub, err := p.EthSubscribe(context.Background(), (chan<- models.Log)(logs), "logs", filter)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	receive := func(want uint64, removed bool) models.Log {
+		select {
+		case log := <-logs:
+			assert.Equal(t, want, log.BlockNumber)
+			assert.Equal(t, removed, log.Removed)
+			return log
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for log in block %d", want)
+		}
+		return models.Log{}
+	}
+
+	node.setHead(13)
+	delivered := map[uint64]common.Hash{}
+	for _, want := range []uint64{11, 12, 13} {
+		delivered[want] = receive(want, false).BlockHash
+	}
+
+	node.reorg(12)
+	node.setHead(14)
+	for _, want := range []uint64{13, 12} {
+		assert.Equal(t, delivered[want], receive(want, true).BlockHash)
+	}
+	for _, want := range []uint64{12, 13, 14} {
+		assert.NotEqual(t, delivered[want], receive(want, false).BlockHash)
+	}
+
+	g := gomega.NewGomegaWithT(t)
+	g.Consistently(func() int { return len(logs) }).Should(gomega.Equal(0))
+}
+
+func TestPollingCallerSubscriber_EthSubscribe_Errors(t *testing.T) {
+	t.Parallel()
+
+	p := newPollingCallerSubscriber(&fakePollingNode{}, time.Second, 100)
+
+	_, err := p.EthSubscribe(context.Background(), make(chan models.Log), "logs", map[string]interface{}{})
+	assert.EqualError(t, err, "pollingCallerSubscriber#EthSubscribe: logs requires a chan<- models.Log, got chan models.Log")
+	_, err = p.EthSubscribe(context.Background(), (chan<- models.Log)(make(chan models.Log)), "logs")
+	assert.EqualError(t, err, "pollingCallerSubscriber#EthSubscribe: logs requires a filter")
+	_, err = p.EthSubscribe(context.Background(), nil, "newPendingTransactions")
+	assert.EqualError(t, err, "pollingCallerSubscriber#EthSubscribe: unsupported subscription newPendingTransactions")
+}
+
+func TestNewEthEndpoint_PollingIsSubscribable(t *testing.T) {
+	t.Parallel()
+
+	polling := newEthEndpoint("http://node", newPollingCallerSubscriber(&fakePollingNode{}, time.Second, 100))
+	assert.True(t, polling.subscribable)
+	callOnly := newEthEndpoint("http://node", &fakePollingNode{})
+	assert.False(t, callOnly.subscribable)
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+)
+
+// GasPriceOracle suggests the gas price that new transactions are sent with.
+// It is told of every new head, so that it can follow the market.
+type GasPriceOracle interface {
+	GasPrice() *big.Int
+	OnNewHead(head *models.Head)
+}
+
+// NewGasPriceOracle returns a MarketGasPriceOracle sampling the configured
+// number of blocks, or a FixedGasPriceOracle if that number is zero.
+func NewGasPriceOracle(client EthClient, config orm.ConfigReader) GasPriceOracle {
+	if config.EthGasPriceOracleBlocks() == 0 {
+		return &FixedGasPriceOracle{config: config}
+	}
+	return NewMarketGasPriceOracle(client, config)
+}
+
+// FixedGasPriceOracle suggests EthGasPriceDefault, which the operator sets by
+// hand.
+type FixedGasPriceOracle struct {
+	config orm.ConfigReader
+}
+
+// GasPrice returns EthGasPriceDefault.
+func (o *FixedGasPriceOracle) GasPrice() *big.Int {
+	return o.config.EthGasPriceDefault()
+}
+
+// OnNewHead does nothing; exists to comply with interface.
+func (o *FixedGasPriceOracle) OnNewHead(*models.Head) {}
+
+// MarketGasPriceOracle suggests the EthGasPricePercentile percentile of the
+// gas prices paid by the transactions of the last EthGasPriceOracleBlocks
+// blocks. Until it has seen a block with transactions, it suggests
+// EthGasPriceDefault.
+//
+// Blocks are fetched and sampled in the background, so that heads are not
+// held up by the request; while a block is being fetched, only the latest of
+// the heads that arrive in the meantime is sampled next.
+type MarketGasPriceOracle struct {
+	client   EthClient
+	config   orm.ConfigReader
+	mutex    sync.Mutex
+	blocks   []blockGasPrices
+	gasPrice *big.Int
+	pending  *models.Head
+	sampling bool
+}
+
+type blockGasPrices struct {
+	number int64
+	prices []*big.Int
+}
+
+// NewMarketGasPriceOracle returns a MarketGasPriceOracle fetching blocks
+// through the given client.
+func NewMarketGasPriceOracle(client EthClient, config orm.ConfigReader) *MarketGasPriceOracle {
+	return &MarketGasPriceOracle{client: client, config: config}
+}
+
+// OnNewHead queues the head to have the gas prices of its transactions
+// sampled in the background.
+func (o *MarketGasPriceOracle) OnNewHead(head *models.Head) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.pending = head
+	if !o.sampling {
+		o.sampling = true
+		go o.sampleLoop()
+	}
+}
+
+func (o *MarketGasPriceOracle) sampleLoop() {
+	for {
+		o.mutex.Lock()
+		head := o.pending
+		o.pending = nil
+		if head == nil {
+			o.sampling = false
+			o.mutex.Unlock()
+			return
+		}
+		o.mutex.Unlock()
+
+		o.sample(head)
+	}
+}
+
+// sample records the gas prices of the head's transactions, forgets those of
+// blocks that are no longer recent, and recomputes the suggested gas price.
+func (o *MarketGasPriceOracle) sample(head *models.Head) {
+	block, err := o.client.GetBlockWithTransactions(hexutil.EncodeBig(head.ToInt()))
+	if err != nil {
+		logger.Warnw("Unable to sample gas prices of new head", "head", head.Number, "error", err)
+		return
+	}
+
+	sample := blockGasPrices{number: head.Number}
+	for i := range block.Transactions {
+		if price := block.Transactions[i].GasPrice.ToInt(); price.Sign() > 0 {
+			sample.prices = append(sample.prices, price)
+		}
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	oldest := head.Number - int64(o.config.EthGasPriceOracleBlocks())
+	blocks := []blockGasPrices{}
+	for _, b := range o.blocks {
+		if b.number > oldest && b.number < head.Number {
+			blocks = append(blocks, b)
+		}
+	}
+	o.blocks = append(blocks, sample)
+
+	var prices []*big.Int
+	for _, b := range o.blocks {
+		prices = append(prices, b.prices...)
+	}
+	if len(prices) == 0 {
+		o.gasPrice = nil
+		return
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	o.gasPrice = prices[percentileIndex(len(prices), o.config.EthGasPricePercentile())]
+}
+
+// GasPrice returns the configured percentile of the sampled gas prices, as
+// of the most recently sampled head.
+func (o *MarketGasPriceOracle) GasPrice() *big.Int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.gasPrice == nil {
+		return o.config.EthGasPriceDefault()
+	}
+	return new(big.Int).Set(o.gasPrice)
+}
+
+// percentileIndex returns the index of the given percentile in a sorted list
+// of count values, using the nearest rank.
+func percentileIndex(count int, percentile uint64) int {
+	if percentile > 100 {
+		percentile = 100
+	}
+	rank := (uint64(count)*percentile + 99) / 100
+	if rank == 0 {
+		return 0
+	}
+	return int(rank) - 1
+}
+
+// capGasPrice returns the gas price, lowered to EthMaxGasPriceWei if above it.
+func capGasPrice(gasPriceWei *big.Int, config orm.ConfigReader) *big.Int {
+	if max := config.EthMaxGasPriceWei(); gasPriceWei.Cmp(max) > 0 {
+		logger.Warnw(fmt.Sprintf("Gas price %v capped at the maximum of %v", gasPriceWei, max))
+		return max
+	}
+	return gasPriceWei
+}
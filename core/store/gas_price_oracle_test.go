@@ -0,0 +1,101 @@
+package store_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func blockWithGasPrices(prices ...int64) models.Block {
+	block := models.Block{}
+	for _, price := range prices {
+		block.Transactions = append(block.Transactions, models.Transaction{
+			Hash:     cltest.NewHash(),
+			GasPrice: hexutil.Big(*big.NewInt(price)),
+		})
+	}
+	return block
+}
+
+func TestNewGasPriceOracle(t *testing.T) {
+	t.Parallel()
+
+	config := cltest.NewTestConfig(t)
+	assert.IsType(t, &strpkg.FixedGasPriceOracle{}, strpkg.NewGasPriceOracle(nil, config))
+
+	config.Set("ETH_GAS_PRICE_ORACLE_BLOCKS", 10)
+	assert.IsType(t, &strpkg.MarketGasPriceOracle{}, strpkg.NewGasPriceOracle(nil, config))
+}
+
+func TestMarketGasPriceOracle_GasPrice(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eth := mocks.NewMockEthClient(ctrl)
+	config := cltest.NewTestConfig(t)
+	config.Set("ETH_GAS_PRICE_ORACLE_BLOCKS", 2)
+	config.Set("ETH_GAS_PRICE_PERCENTILE", 60)
+	oracle := strpkg.NewMarketGasPriceOracle(eth, config)
+	g := gomega.NewGomegaWithT(t)
+
+	assert.Equal(t, config.EthGasPriceDefault(), oracle.GasPrice())
+
+	eth.EXPECT().GetBlockWithTransactions("0x1").Return(blockWithGasPrices(50, 10, 0, 40), nil)
+	oracle.OnNewHead(cltest.Head(1))
+	g.Eventually(oracle.GasPrice).Should(gomega.Equal(big.NewInt(40)))
+
+	eth.EXPECT().GetBlockWithTransactions("0x2").Return(blockWithGasPrices(20, 30), nil)
+	oracle.OnNewHead(cltest.Head(2))
+	g.Eventually(oracle.GasPrice).Should(gomega.Equal(big.NewInt(30)))
+
+	// block 1 is no longer among the last 2 blocks
+	eth.EXPECT().GetBlockWithTransactions("0x3").Return(blockWithGasPrices(1, 2, 3), nil)
+	oracle.OnNewHead(cltest.Head(3))
+	g.Eventually(oracle.GasPrice).Should(gomega.Equal(big.NewInt(3)))
+
+	// a reorg replaces the prices of the orphaned block
+	eth.EXPECT().GetBlockWithTransactions("0x3").Return(blockWithGasPrices(100), nil)
+	oracle.OnNewHead(cltest.Head(3))
+	g.Eventually(oracle.GasPrice).Should(gomega.Equal(big.NewInt(30)))
+}
+
+func TestMarketGasPriceOracle_OnNewHead_DoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eth := mocks.NewMockEthClient(ctrl)
+	config := cltest.NewTestConfig(t)
+	config.Set("ETH_GAS_PRICE_ORACLE_BLOCKS", 10)
+	oracle := strpkg.NewMarketGasPriceOracle(eth, config)
+	g := gomega.NewGomegaWithT(t)
+
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	eth.EXPECT().GetBlockWithTransactions("0x1").DoAndReturn(func(string) (models.Block, error) {
+		close(fetching)
+		<-release
+		return blockWithGasPrices(10), nil
+	})
+	oracle.OnNewHead(cltest.Head(1))
+	<-fetching
+
+	// heads arriving while a block is fetched are coalesced into the latest
+	oracle.OnNewHead(cltest.Head(2))
+	oracle.OnNewHead(cltest.Head(3))
+	eth.EXPECT().GetBlockWithTransactions("0x3").Return(blockWithGasPrices(30), nil)
+	close(release)
+
+	g.Eventually(oracle.GasPrice).Should(gomega.Equal(big.NewInt(30)))
+}
@@ -71,6 +71,16 @@ func (ks *KeyStore) SignTx(account accounts.Account, tx *types.Transaction, chai
 	return ks.KeyStore.SignTx(account, tx, chainID)
 }
 
+// SignDynamicFeeTx uses the unlocked account to sign the given EIP-1559
+// dynamic fee transaction.
+func (ks *KeyStore) SignDynamicFeeTx(account accounts.Account, tx *models.DynamicFeeTx) (*models.DynamicFeeTx, error) {
+	signature, err := ks.KeyStore.SignHash(account, tx.SigningHash().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signature)
+}
+
 // Sign creates an HMAC from some input data using the account's private key
 func (ks *KeyStore) Sign(input []byte) (models.Signature, error) {
 	account, err := ks.GetFirstAccount()
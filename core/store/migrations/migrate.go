@@ -26,6 +26,18 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1568833756"
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1570087128"
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1570675883"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1571198486"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1571912544"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1572355870"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1572871243"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1573004618"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1573103751"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1573209537"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1573296514"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1573412089"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1573498305"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1573583972"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1573671829"
 	gormigrate "gopkg.in/gormigrate.v1"
 )
 
@@ -125,6 +137,54 @@ func MigrateTo(db *gorm.DB, migrationID string) error {
 			ID:      "1570675883",
 			Migrate: migration1570675883.Migrate,
 		},
+		{
+			ID:      "1571198486",
+			Migrate: migration1571198486.Migrate,
+		},
+		{
+			ID:      "1571912544",
+			Migrate: migration1571912544.Migrate,
+		},
+		{
+			ID:      "1572355870",
+			Migrate: migration1572355870.Migrate,
+		},
+		{
+			ID:      "1572871243",
+			Migrate: migration1572871243.Migrate,
+		},
+		{
+			ID:      "1573004618",
+			Migrate: migration1573004618.Migrate,
+		},
+		{
+			ID:      "1573103751",
+			Migrate: migration1573103751.Migrate,
+		},
+		{
+			ID:      "1573209537",
+			Migrate: migration1573209537.Migrate,
+		},
+		{
+			ID:      "1573296514",
+			Migrate: migration1573296514.Migrate,
+		},
+		{
+			ID:      "1573412089",
+			Migrate: migration1573412089.Migrate,
+		},
+		{
+			ID:      "1573498305",
+			Migrate: migration1573498305.Migrate,
+		},
+		{
+			ID:      "1573583972",
+			Migrate: migration1573583972.Migrate,
+		},
+		{
+			ID:      "1573671829",
+			Migrate: migration1573671829.Migrate,
+		},
 	}
 
 	m := gormigrate.New(db, &options, migrations)
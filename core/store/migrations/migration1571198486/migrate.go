@@ -0,0 +1,24 @@
+package migration1571198486
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Head represents a BlockNumber, BlockHash.
+type Head struct {
+	ID         uint64      `gorm:"primary_key;auto_increment"`
+	Hash       common.Hash `gorm:"not null"`
+	ParentHash common.Hash
+	Number     int64 `gorm:"index;not null"`
+}
+
+// Migrate adds the parent hash to heads so that the HeadTracker can detect
+// chain reorganizations.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&Head{}).Error; err != nil {
+		return errors.Wrap(err, "could not add parent_hash to the heads table")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package migration1571912544
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/store/assets"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// QueuedRun is a job run waiting for a JobRunner worker.
+type QueuedRun struct {
+	JobRunID     *models.ID   `gorm:"primary_key;type:varchar(36) REFERENCES job_runs(id) ON DELETE CASCADE"`
+	JobSpecID    *models.ID   `gorm:"index;not null"`
+	Payment      *assets.Link `gorm:"type:varchar(255)"`
+	RunCreatedAt time.Time
+	CreatedAt    time.Time `gorm:"index"`
+}
+
+// Migrate creates the queued_runs table backing the JobRunner's work queue.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&QueuedRun{}).Error; err != nil {
+		return errors.Wrap(err, "could not create queued_runs table")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package migration1572355870
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// TaskSpec is the task_specs table with the optional retry policy.
+type TaskSpec struct {
+	ID    uint   `gorm:"primary_key"`
+	Retry string `gorm:"type:text"`
+}
+
+// TaskRun is the task_runs table with the state of retried tasks.
+type TaskRun struct {
+	ID        string `gorm:"primary_key;not null"`
+	Attempts  uint32 `gorm:"not null;default:0"`
+	LastError null.String
+	RetryAt   null.Time
+}
+
+// Migrate adds retry policies to task specs, and attempt counts, last errors
+// and retry times to task runs.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&TaskSpec{}).Error; err != nil {
+		return errors.Wrap(err, "could not add retry to the task_specs table")
+	}
+	if err := tx.AutoMigrate(&TaskRun{}).Error; err != nil {
+		return errors.Wrap(err, "could not add retry state to the task_runs table")
+	}
+	return nil
+}
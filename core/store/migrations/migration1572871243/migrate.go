@@ -0,0 +1,23 @@
+package migration1572871243
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// TaskSpec is the task_specs table with the task's name and the names of the
+// tasks it depends on.
+type TaskSpec struct {
+	ID        uint `gorm:"primary_key"`
+	Name      string
+	DependsOn string `gorm:"type:text"`
+}
+
+// Migrate adds names and dependencies to task specs, for jobs whose tasks
+// form a graph.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&TaskSpec{}).Error; err != nil {
+		return errors.Wrap(err, "could not add name and depends_on to the task_specs table")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package migration1573004618
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// Tx is the txes table with the fee caps of dynamic fee transactions.
+type Tx struct {
+	ID                   uint64      `gorm:"primary_key;auto_increment"`
+	MaxFeePerGas         *models.Big `gorm:"type:varchar(78)"`
+	MaxPriorityFeePerGas *models.Big `gorm:"type:varchar(78)"`
+}
+
+// TxAttempt is the tx_attempts table with the fee caps of dynamic fee
+// transactions.
+type TxAttempt struct {
+	ID                   uint64      `gorm:"primary_key;auto_increment"`
+	MaxFeePerGas         *models.Big `gorm:"type:varchar(78)"`
+	MaxPriorityFeePerGas *models.Big `gorm:"type:varchar(78)"`
+}
+
+// Migrate adds the EIP-1559 fee caps to transactions and their attempts.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&Tx{}).Error; err != nil {
+		return errors.Wrap(err, "could not add fee caps to the txes table")
+	}
+	if err := tx.AutoMigrate(&TxAttempt{}).Error; err != nil {
+		return errors.Wrap(err, "could not add fee caps to the tx_attempts table")
+	}
+	return nil
+}
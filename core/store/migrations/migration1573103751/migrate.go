@@ -0,0 +1,21 @@
+package migration1573103751
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Tx is the txes table with whether the transaction was cancelled.
+type Tx struct {
+	ID        uint64 `gorm:"primary_key;auto_increment"`
+	Cancelled bool   `gorm:"not null;default:false"`
+}
+
+// Migrate adds the cancelled flag to transactions, set once they are
+// replaced by a zero value transfer to their sender.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&Tx{}).Error; err != nil {
+		return errors.Wrap(err, "could not add cancelled to the txes table")
+	}
+	return nil
+}
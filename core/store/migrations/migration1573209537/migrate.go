@@ -0,0 +1,24 @@
+package migration1573209537
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// RunRequest is the run_requests table with the expiration of the oracle
+// request.
+type RunRequest struct {
+	ID         uint `gorm:"primary_key"`
+	Expiration *time.Time
+}
+
+// Migrate adds the expiration of oracle requests to run requests, past which
+// the run is cancelled rather than fulfilled.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&RunRequest{}).Error; err != nil {
+		return errors.Wrap(err, "could not add expiration to the run_requests table")
+	}
+	return nil
+}
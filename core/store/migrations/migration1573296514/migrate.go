@@ -0,0 +1,40 @@
+package migration1573296514
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// LogCursor is the block an initiator resumes reading logs from.
+type LogCursor struct {
+	InitiatorID uint   `gorm:"primary_key;auto_increment:false;type:integer REFERENCES initiators(id) ON DELETE CASCADE"`
+	BlockNumber uint64 `gorm:"not null"`
+	UpdatedAt   time.Time
+}
+
+// ConsumedLog is a log an initiator has processed.
+type ConsumedLog struct {
+	ID          uint        `gorm:"primary_key"`
+	InitiatorID uint        `gorm:"not null;unique_index:idx_consumed_logs_initiator_log;type:integer REFERENCES initiators(id) ON DELETE CASCADE"`
+	TxHash      common.Hash `gorm:"not null;unique_index:idx_consumed_logs_initiator_log"`
+	LogIndex    uint        `gorm:"not null;unique_index:idx_consumed_logs_initiator_log"`
+	BlockNumber uint64      `gorm:"not null"`
+	CreatedAt   time.Time
+}
+
+// Migrate creates the log_cursors and consumed_logs tables, with which log
+// initiators resume after a restart from the last block they processed,
+// without processing any log twice. Both are removed along with their
+// initiator.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&LogCursor{}).Error; err != nil {
+		return errors.Wrap(err, "could not create log_cursors table")
+	}
+	if err := tx.AutoMigrate(&ConsumedLog{}).Error; err != nil {
+		return errors.Wrap(err, "could not create consumed_logs table")
+	}
+	return nil
+}
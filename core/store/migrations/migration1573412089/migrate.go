@@ -0,0 +1,27 @@
+package migration1573412089
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// Initiator is the initiators table with the parameters of flux monitor
+// initiators.
+type Initiator struct {
+	ID              uint         `gorm:"primary_key;auto_increment"`
+	Feeds           *models.JSON `gorm:"type:text"`
+	Threshold       float64
+	Precision       int32
+	PollingInterval models.Duration
+	Heartbeat       models.Duration
+}
+
+// Migrate adds the feeds, deviation threshold, precision, polling interval
+// and heartbeat of flux monitor initiators.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&Initiator{}).Error; err != nil {
+		return errors.Wrap(err, "could not add flux monitor parameters to the initiators table")
+	}
+	return nil
+}
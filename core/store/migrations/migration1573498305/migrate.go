@@ -0,0 +1,25 @@
+package migration1573498305
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Initiator is the initiators table with the parameters of blocks and
+// txconfirmations initiators.
+type Initiator struct {
+	ID            uint `gorm:"primary_key;auto_increment"`
+	BlockInterval uint64
+	TxHash        common.Hash
+	Confirmations uint64
+}
+
+// Migrate adds the block interval of blocks initiators, and the transaction
+// hash and confirmations of txconfirmations initiators.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&Initiator{}).Error; err != nil {
+		return errors.Wrap(err, "could not add block parameters to the initiators table")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package migration1573583972
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// Initiator is the initiators table with the time zone, jitter and catch-up
+// policy of cron initiators, and when they last fired.
+type Initiator struct {
+	ID          uint `gorm:"primary_key;auto_increment"`
+	TimeZone    string
+	Jitter      models.Duration
+	CatchUp     string
+	LastFiredAt null.Time
+}
+
+// Migrate adds the time zone, jitter, catch-up policy and last fire time of
+// cron initiators.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&Initiator{}).Error; err != nil {
+		return errors.Wrap(err, "could not add cron parameters to the initiators table")
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package migration1573671829
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Migrate adds the event ABI with which ethlog initiators decode the
+// arguments of their logs.
+func Migrate(tx *gorm.DB) error {
+	if err := tx.Exec(`ALTER TABLE initiators ADD COLUMN "event_abi" text`).Error; err != nil {
+		return errors.Wrap(err, "could not add event_abi column to initiators table")
+	}
+	return nil
+}
@@ -43,10 +43,18 @@ const (
 	RunStatusPendingBridge = RunStatus("pending_bridge")
 	// RunStatusPendingSleep is used for when a run is waiting on a sleep function to finish.
 	RunStatusPendingSleep = RunStatus("pending_sleep")
+	// RunStatusPendingRetry is used for when a run is waiting to retry a failed task.
+	RunStatusPendingRetry = RunStatus("pending_retry")
 	// RunStatusErrored is used for when a run has errored and will not complete.
 	RunStatusErrored = RunStatus("errored")
 	// RunStatusCompleted is used for when a run has successfully completed execution.
 	RunStatusCompleted = RunStatus("completed")
+	// RunStatusSkipped is used for when a task was not executed because a
+	// conditional task branched past it.
+	RunStatusSkipped = RunStatus("skipped")
+	// RunStatusCancelled is used for when a run was stopped because its
+	// request was cancelled, fulfilled by someone else, or has expired.
+	RunStatusCancelled = RunStatus("cancelled")
 )
 
 // Unstarted returns true if the status is the initial state.
@@ -74,29 +82,44 @@ func (s RunStatus) PendingSleep() bool {
 	return s == RunStatusPendingSleep
 }
 
+// PendingRetry returns true if the status is pending_retry.
+func (s RunStatus) PendingRetry() bool {
+	return s == RunStatusPendingRetry
+}
+
 // Completed returns true if the status is RunStatusCompleted.
 func (s RunStatus) Completed() bool {
 	return s == RunStatusCompleted
 }
 
+// Skipped returns true if the status is RunStatusSkipped.
+func (s RunStatus) Skipped() bool {
+	return s == RunStatusSkipped
+}
+
 // Errored returns true if the status is RunStatusErrored.
 func (s RunStatus) Errored() bool {
 	return s == RunStatusErrored
 }
 
+// Cancelled returns true if the status is RunStatusCancelled.
+func (s RunStatus) Cancelled() bool {
+	return s == RunStatusCancelled
+}
+
 // Pending returns true if the status is pending external or confirmations.
 func (s RunStatus) Pending() bool {
-	return s.PendingBridge() || s.PendingConfirmations() || s.PendingSleep() || s.PendingConnection()
+	return s.PendingBridge() || s.PendingConfirmations() || s.PendingSleep() || s.PendingConnection() || s.PendingRetry()
 }
 
 // Finished returns true if the status is final and can't be changed.
 func (s RunStatus) Finished() bool {
-	return s.Completed() || s.Errored()
+	return s.Completed() || s.Errored() || s.Cancelled()
 }
 
 // Runnable returns true if the status is ready to be run.
 func (s RunStatus) Runnable() bool {
-	return !s.Errored() && !s.Pending()
+	return !s.Errored() && !s.Cancelled() && !s.Pending()
 }
 
 // CanStart returns true if the run is ready to begin processed.
@@ -407,6 +430,34 @@ func (c Cron) String() string {
 	return string(c)
 }
 
+// Duration is a time.Duration that is represented in JSON as a string such
+// as "1m30s".
+type Duration time.Duration
+
+// MarshalJSON returns the duration formatted as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON parses a duration string such as "300ms" or "1h".
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("Duration: %v", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("Duration: %v", err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Duration returns the value as a time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
 // WithdrawalRequest request to withdraw LINK.
 type WithdrawalRequest struct {
 	DestinationAddress common.Address `json:"address"`
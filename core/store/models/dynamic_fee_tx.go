@@ -0,0 +1,218 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DynamicFeeTxType is the EIP-2718 type byte of an EIP-1559 dynamic fee
+// transaction.
+const DynamicFeeTxType = 0x02
+
+// SignedTx is a signed Ethereum transaction, either a legacy
+// types.Transaction or a DynamicFeeTx, ready to be persisted and sent.
+type SignedTx interface {
+	Hash() common.Hash
+	Nonce() uint64
+	To() *common.Address
+	Value() *big.Int
+	Gas() uint64
+	GasPrice() *big.Int
+	Data() []byte
+	EncodeRLP(w io.Writer) error
+}
+
+// GasFees are the fees a transaction is sent with: a gas price for a legacy
+// transaction, or the fee caps of an EIP-1559 dynamic fee transaction, whose
+// gas price is its MaxFeePerGas.
+type GasFees struct {
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// Dynamic returns true if the fees are those of a dynamic fee transaction.
+func (f GasFees) Dynamic() bool {
+	return f.MaxFeePerGas != nil && f.MaxPriorityFeePerGas != nil
+}
+
+// String implements Stringer for GasFees
+func (f GasFees) String() string {
+	if f.Dynamic() {
+		return "max fee " + f.MaxFeePerGas.String() + ", priority fee " + f.MaxPriorityFeePerGas.String()
+	}
+	return "gas price " + f.GasPrice.String()
+}
+
+// DynamicFeesOf returns the fee caps of a dynamic fee transaction, or nil for
+// a legacy transaction.
+func DynamicFeesOf(tx SignedTx) (maxFeePerGas *Big, maxPriorityFeePerGas *Big) {
+	dtx, ok := tx.(*DynamicFeeTx)
+	if !ok {
+		return nil, nil
+	}
+	return NewBig(dtx.MaxFeePerGas()), NewBig(dtx.MaxPriorityFeePerGas())
+}
+
+// DynamicFeeTx is an EIP-1559 dynamic fee transaction, which pays the
+// block's base fee plus a priority fee to the miner, up to MaxFeePerGas.
+type DynamicFeeTx struct {
+	data dynamicFeeTxData
+}
+
+type dynamicFeeTxData struct {
+	ChainID              *big.Int
+	Nonce                uint64
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+	Gas                  uint64
+	To                   common.Address
+	Value                *big.Int
+	Data                 []byte
+	AccessList           []accessTuple
+	V, R, S              *big.Int
+}
+
+// accessTuple is an EIP-2930 access list entry; transactions are always sent
+// with an empty access list.
+type accessTuple struct {
+	Address     common.Address
+	StorageKeys []common.Hash
+}
+
+// NewDynamicFeeTx returns an unsigned dynamic fee transaction.
+func NewDynamicFeeTx(
+	chainID *big.Int,
+	nonce uint64,
+	to common.Address,
+	amount *big.Int,
+	gasLimit uint64,
+	maxPriorityFeePerGas *big.Int,
+	maxFeePerGas *big.Int,
+	data []byte,
+) *DynamicFeeTx {
+	if amount == nil {
+		amount = new(big.Int)
+	}
+	return &DynamicFeeTx{data: dynamicFeeTxData{
+		ChainID:              new(big.Int).Set(chainID),
+		Nonce:                nonce,
+		MaxPriorityFeePerGas: new(big.Int).Set(maxPriorityFeePerGas),
+		MaxFeePerGas:         new(big.Int).Set(maxFeePerGas),
+		Gas:                  gasLimit,
+		To:                   to,
+		Value:                new(big.Int).Set(amount),
+		Data:                 common.CopyBytes(data),
+		AccessList:           []accessTuple{},
+		V:                    new(big.Int),
+		R:                    new(big.Int),
+		S:                    new(big.Int),
+	}}
+}
+
+// SigningHash returns the hash that the sender signs, which covers every
+// field but the signature.
+func (tx *DynamicFeeTx) SigningHash() common.Hash {
+	d := tx.data
+	payload, err := rlp.EncodeToBytes([]interface{}{
+		d.ChainID,
+		d.Nonce,
+		d.MaxPriorityFeePerGas,
+		d.MaxFeePerGas,
+		d.Gas,
+		d.To,
+		d.Value,
+		d.Data,
+		d.AccessList,
+	})
+	if err != nil {
+		// Encoding only fails for unsupported types, which these are not
+		panic(err)
+	}
+	return crypto.Keccak256Hash([]byte{DynamicFeeTxType}, payload)
+}
+
+// WithSignature returns a copy of the transaction signed with the given
+// 65 byte [R || S || V] signature, where V is 0 or 1.
+func (tx *DynamicFeeTx) WithSignature(sig []byte) (*DynamicFeeTx, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, errors.New("wrong size for signature")
+	}
+	cpy := &DynamicFeeTx{data: tx.data}
+	cpy.data.R = new(big.Int).SetBytes(sig[:32])
+	cpy.data.S = new(big.Int).SetBytes(sig[32:64])
+	cpy.data.V = new(big.Int).SetBytes(sig[64:])
+	return cpy, nil
+}
+
+// MarshalBinary returns the EIP-2718 envelope of the transaction, the type
+// byte followed by its RLP encoded fields, as accepted by
+// eth_sendRawTransaction.
+func (tx *DynamicFeeTx) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(DynamicFeeTxType)
+	if err := rlp.Encode(&buf, &tx.data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeRLP implements rlp.Encoder, encoding the transaction's envelope as
+// an RLP string, the way typed transactions appear in block bodies.
+func (tx *DynamicFeeTx) EncodeRLP(w io.Writer) error {
+	envelope, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, envelope)
+}
+
+// Hash returns the transaction hash, the hash of its envelope.
+func (tx *DynamicFeeTx) Hash() common.Hash {
+	envelope, err := tx.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return crypto.Keccak256Hash(envelope)
+}
+
+// ChainID returns the id of the chain the transaction is signed for.
+func (tx *DynamicFeeTx) ChainID() *big.Int { return new(big.Int).Set(tx.data.ChainID) }
+
+// Nonce returns the sender's nonce.
+func (tx *DynamicFeeTx) Nonce() uint64 { return tx.data.Nonce }
+
+// To returns the recipient of the transaction.
+func (tx *DynamicFeeTx) To() *common.Address {
+	to := tx.data.To
+	return &to
+}
+
+// Value returns the wei sent with the transaction.
+func (tx *DynamicFeeTx) Value() *big.Int { return new(big.Int).Set(tx.data.Value) }
+
+// Gas returns the gas limit of the transaction.
+func (tx *DynamicFeeTx) Gas() uint64 { return tx.data.Gas }
+
+// Data returns the transaction's input data.
+func (tx *DynamicFeeTx) Data() []byte { return common.CopyBytes(tx.data.Data) }
+
+// GasPrice returns the most the transaction can pay per gas, its
+// MaxFeePerGas.
+func (tx *DynamicFeeTx) GasPrice() *big.Int { return tx.MaxFeePerGas() }
+
+// MaxFeePerGas returns the most the transaction pays per gas, base fee
+// included.
+func (tx *DynamicFeeTx) MaxFeePerGas() *big.Int { return new(big.Int).Set(tx.data.MaxFeePerGas) }
+
+// MaxPriorityFeePerGas returns the most the transaction pays the miner per
+// gas, on top of the base fee.
+func (tx *DynamicFeeTx) MaxPriorityFeePerGas() *big.Int {
+	return new(big.Int).Set(tx.data.MaxPriorityFeePerGas)
+}
@@ -0,0 +1,63 @@
+package models_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicFeeTx_Signing(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	to := cltest.NewAddress()
+	unsigned := models.NewDynamicFeeTx(big.NewInt(3), 7, to, big.NewInt(1), 500000, big.NewInt(2), big.NewInt(30), []byte{0xab})
+	sig, err := crypto.Sign(unsigned.SigningHash().Bytes(), key)
+	require.NoError(t, err)
+	tx, err := unsigned.WithSignature(sig)
+	require.NoError(t, err)
+
+	sender, err := crypto.SigToPub(tx.SigningHash().Bytes(), sig)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*sender))
+
+	envelope, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, byte(models.DynamicFeeTxType), envelope[0])
+	var fields []rlp.RawValue
+	require.NoError(t, rlp.DecodeBytes(envelope[1:], &fields))
+	assert.Len(t, fields, 12)
+
+	assert.Equal(t, crypto.Keccak256Hash(envelope), tx.Hash())
+	assert.NotEqual(t, unsigned.Hash(), tx.Hash())
+	raw, err := utils.EncodeTxToHex(tx)
+	require.NoError(t, err)
+	assert.Equal(t, hexutil.Encode(envelope), raw)
+
+	assert.Equal(t, uint64(7), tx.Nonce())
+	assert.Equal(t, &to, tx.To())
+	assert.Equal(t, big.NewInt(30), tx.GasPrice())
+	assert.Equal(t, big.NewInt(2), tx.MaxPriorityFeePerGas())
+
+	maxFee, priorityFee := models.DynamicFeesOf(tx)
+	assert.Equal(t, models.NewBig(big.NewInt(30)), maxFee)
+	assert.Equal(t, models.NewBig(big.NewInt(2)), priorityFee)
+}
+
+func TestDynamicFeeTx_WithSignature_WrongSize(t *testing.T) {
+	t.Parallel()
+
+	tx := models.NewDynamicFeeTx(big.NewInt(1), 0, cltest.NewAddress(), nil, 21000, big.NewInt(1), big.NewInt(2), nil)
+	_, err := tx.WithSignature([]byte{1, 2, 3})
+	assert.EqualError(t, err, "wrong size for signature")
+}
@@ -85,12 +85,20 @@ type Tx struct {
 	Value    *Big           `gorm:"type:varchar(78);not null"`
 	GasLimit uint64         `gorm:"not null"`
 
+	// Cancelled is set once the transaction is replaced by a zero value
+	// transfer to its sender at the same nonce, which later attempts send.
+	Cancelled bool `gorm:"not null;default:false"`
+
 	// TxAttempt fields manually included; can't embed another primary_key
 	Hash        common.Hash `gorm:"not null"`
 	GasPrice    *Big        `gorm:"type:varchar(78);not null"`
 	Confirmed   bool        `gorm:"not null"`
 	SentAt      uint64      `gorm:"not null"`
 	SignedRawTx string      `gorm:"type:text;not null"`
+
+	// Fee caps of an EIP-1559 dynamic fee transaction, nil for legacy ones
+	MaxFeePerGas         *Big `gorm:"type:varchar(78)"`
+	MaxPriorityFeePerGas *Big `gorm:"type:varchar(78)"`
 }
 
 // String implements Stringer for Tx
@@ -133,6 +141,20 @@ type TxAttempt struct {
 	Confirmed   bool        `gorm:"not null"`
 	SentAt      uint64      `gorm:"not null"`
 	SignedRawTx string      `gorm:"type:text;not null"`
+
+	// Fee caps of an EIP-1559 dynamic fee transaction, nil for legacy ones
+	MaxFeePerGas         *Big `gorm:"type:varchar(78)"`
+	MaxPriorityFeePerGas *Big `gorm:"type:varchar(78)"`
+}
+
+// Fees returns the fees the attempt was sent with.
+func (txa *TxAttempt) Fees() GasFees {
+	fees := GasFees{GasPrice: txa.GasPrice.ToInt()}
+	if txa.MaxFeePerGas != nil && txa.MaxPriorityFeePerGas != nil {
+		fees.MaxFeePerGas = txa.MaxFeePerGas.ToInt()
+		fees.MaxPriorityFeePerGas = txa.MaxPriorityFeePerGas.ToInt()
+	}
+	return fees
 }
 
 // String implements Stringer for TxAttempt
@@ -290,6 +312,9 @@ type BlockHeader struct {
 	Nonce       types.BlockNonce `json:"nonce"`
 	GethHash    common.Hash      `json:"mixHash"`
 	ParityHash  common.Hash      `json:"hash"`
+
+	// BaseFeePerGas is only present on chains with EIP-1559 dynamic fees
+	BaseFeePerGas *hexutil.Big `json:"baseFeePerGas"`
 }
 
 var emptyHash = common.Hash{}
@@ -304,14 +329,33 @@ func (h BlockHeader) Hash() common.Hash {
 
 // ToHead converts a given BlockHeader to a Head instance.
 func (h BlockHeader) ToHead() *Head {
-	return NewHead(h.Number.ToInt(), h.Hash())
+	head := NewHead(h.Number.ToInt(), h.Hash())
+	if head != nil {
+		head.ParentHash = h.ParentHash
+	}
+	return head
+}
+
+// Block is a block in the Ethereum blockchain along with its transactions,
+// as returned when asking for full transaction objects.
+type Block struct {
+	Number       hexutil.Big   `json:"number"`
+	Hash         common.Hash   `json:"hash"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Transaction is a transaction included in a Block.
+type Transaction struct {
+	Hash     common.Hash `json:"hash"`
+	GasPrice hexutil.Big `json:"gasPrice"`
 }
 
 // Head represents a BlockNumber, BlockHash.
 type Head struct {
-	ID     uint64      `gorm:"primary_key;auto_increment"`
-	Hash   common.Hash `gorm:"not null"`
-	Number int64       `gorm:"index;not null"`
+	ID         uint64      `gorm:"primary_key;auto_increment"`
+	Hash       common.Hash `gorm:"not null"`
+	ParentHash common.Hash
+	Number     int64 `gorm:"index;not null"`
 }
 
 // AfterCreate is a gorm hook that trims heads after its creation
@@ -378,6 +422,38 @@ func (l *Head) NextInt() *big.Int {
 	return new(big.Int).Add(l.ToInt(), big.NewInt(1))
 }
 
+// Reorg describes a chain reorganization observed by the HeadTracker, where
+// previously tracked heads have been replaced by a competing chain.
+type Reorg struct {
+	// Head is the new head of the canonical chain.
+	Head *Head
+	// CommonAncestor is the most recent block shared by the old and the new
+	// chain, or nil if the fork is older than the tracked heads.
+	CommonAncestor *Head
+	// Orphaned holds the previously tracked heads that are no longer part of
+	// the canonical chain, in ascending order.
+	Orphaned []Head
+}
+
+// OrphanedHashes returns the hashes of all orphaned blocks.
+func (r *Reorg) OrphanedHashes() []common.Hash {
+	hashes := make([]common.Hash, len(r.Orphaned))
+	for i, head := range r.Orphaned {
+		hashes[i] = head.Hash
+	}
+	return hashes
+}
+
+// Orphans returns true if the block with the given hash was orphaned.
+func (r *Reorg) Orphans(hash common.Hash) bool {
+	for _, head := range r.Orphaned {
+		if head.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
 // EthSubscription should implement Err() <-chan error and Unsubscribe()
 type EthSubscription interface {
 	Err() <-chan error
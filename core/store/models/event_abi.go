@@ -0,0 +1,250 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+var eventSignatureRegexp = regexp.MustCompile(`^\s*(\w+)\s*\(([^()]*)\)\s*$`)
+
+// EventABI is the ABI of the event an ethlog initiator decodes the arguments
+// of. It is given either as an event fragment of a contract's JSON ABI, or as
+// a signature such as
+// "Transfer(address indexed from, address indexed to, uint256 value)".
+type EventABI struct {
+	abi.Event
+	raw []byte
+}
+
+// UnmarshalJSON parses the event from a JSON ABI fragment or a signature.
+func (e *EventABI) UnmarshalJSON(b []byte) error {
+	var signature string
+	if err := json.Unmarshal(b, &signature); err == nil {
+		event, err := parseEventSignature(signature)
+		if err != nil {
+			return err
+		}
+		*e = EventABI{Event: event, raw: b}
+		return nil
+	}
+
+	var fragment struct {
+		Type      string
+		Name      string
+		Anonymous bool
+		Inputs    abi.Arguments
+	}
+	if err := json.Unmarshal(b, &fragment); err != nil {
+		return fmt.Errorf("EventABI: %v", err)
+	}
+	if fragment.Type != "" && fragment.Type != "event" {
+		return fmt.Errorf("EventABI: type %s is not event", fragment.Type)
+	}
+	if fragment.Name == "" {
+		return errors.New("EventABI: must have a name")
+	}
+	*e = EventABI{
+		Event: abi.Event{
+			Name:      fragment.Name,
+			RawName:   fragment.Name,
+			Anonymous: fragment.Anonymous,
+			Inputs:    fragment.Inputs,
+		},
+		raw: b,
+	}
+	return nil
+}
+
+// parseEventSignature parses a signature such as
+// "Transfer(address indexed from, address indexed to, uint256 value)".
+// Tuples are not supported.
+func parseEventSignature(signature string) (abi.Event, error) {
+	matches := eventSignatureRegexp.FindStringSubmatch(signature)
+	if matches == nil {
+		return abi.Event{}, fmt.Errorf("EventABI: invalid signature %q", signature)
+	}
+
+	event := abi.Event{Name: matches[1], RawName: matches[1]}
+	if strings.TrimSpace(matches[2]) == "" {
+		return event, nil
+	}
+	for i, param := range strings.Split(matches[2], ",") {
+		fields := strings.Fields(param)
+		if len(fields) == 0 || len(fields) > 3 {
+			return abi.Event{}, fmt.Errorf("EventABI: invalid argument %d %q", i, param)
+		}
+		typ, err := abi.NewType(fields[0], nil)
+		if err != nil {
+			return abi.Event{}, fmt.Errorf("EventABI: argument %d: %v", i, err)
+		}
+		arg := abi.Argument{Type: typ}
+		rest := fields[1:]
+		if len(rest) > 0 && rest[0] == "indexed" {
+			arg.Indexed = true
+			rest = rest[1:]
+		}
+		if len(rest) > 1 {
+			return abi.Event{}, fmt.Errorf("EventABI: invalid argument %d %q", i, param)
+		} else if len(rest) == 1 {
+			arg.Name = rest[0]
+		}
+		event.Inputs = append(event.Inputs, arg)
+	}
+	return event, nil
+}
+
+// MarshalJSON returns the event as it was given.
+func (e EventABI) MarshalJSON() ([]byte, error) {
+	if len(e.raw) == 0 {
+		return []byte("null"), nil
+	}
+	return e.raw, nil
+}
+
+// Value returns this instance serialized for database storage.
+func (e EventABI) Value() (driver.Value, error) {
+	return string(e.raw), nil
+}
+
+// Scan reads the database value and returns an instance.
+func (e *EventABI) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return e.UnmarshalJSON([]byte(v))
+	case []byte:
+		return e.UnmarshalJSON(v)
+	default:
+		return fmt.Errorf("Unable to convert %v of %T to EventABI", value, value)
+	}
+}
+
+// Decode returns the arguments of the event in the log as named fields.
+// Unnamed arguments are named after their position, as in "arg0". Indexed
+// arguments of dynamic types are only available as the hash in the topic.
+func (e EventABI) Decode(log Log) (JSON, error) {
+	topics := log.Topics
+	if !e.Anonymous {
+		if len(topics) == 0 || topics[0] != e.ID() {
+			return JSON{}, fmt.Errorf("log is not a %s event", e.Sig())
+		}
+		topics = topics[1:]
+	}
+
+	nonIndexed, err := e.Inputs.NonIndexed().UnpackValues(log.Data)
+	if err != nil {
+		return JSON{}, errors.Wrapf(err, "decoding %s event data", e.Sig())
+	}
+
+	args := map[string]interface{}{}
+	for i, input := range e.Inputs {
+		name := ABIArgumentName(input, i)
+
+		if !input.Indexed {
+			args[name] = ABIValueToJSON(nonIndexed[0])
+			nonIndexed = nonIndexed[1:]
+			continue
+		}
+
+		if len(topics) == 0 {
+			return JSON{}, fmt.Errorf("log is missing the topic of indexed argument %s", name)
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		if !isStaticABIType(input.Type) {
+			args[name] = topic.Hex()
+			continue
+		}
+		values, err := abi.Arguments{{Type: input.Type}}.UnpackValues(topic.Bytes())
+		if err != nil {
+			return JSON{}, errors.Wrapf(err, "decoding indexed argument %s", name)
+		}
+		args[name] = ABIValueToJSON(values[0])
+	}
+
+	b, err := json.Marshal(args)
+	if err != nil {
+		return JSON{}, err
+	}
+	return ParseJSON(b)
+}
+
+// ABIArgumentName returns the name of the argument, or one after its
+// position, as in "arg0", if it has none.
+func ABIArgumentName(arg abi.Argument, position int) string {
+	if arg.Name == "" {
+		return fmt.Sprintf("arg%d", position)
+	}
+	return arg.Name
+}
+
+// isStaticABIType returns true if the type fits in a single topic, rather
+// than being hashed into it.
+func isStaticABIType(t abi.Type) bool {
+	switch t.T {
+	case abi.IntTy, abi.UintTy, abi.BoolTy, abi.AddressTy, abi.FixedBytesTy:
+		return true
+	default:
+		return false
+	}
+}
+
+// ABIValueToJSON converts a value decoded by the abi package to its JSON
+// representation. Integers are decimal strings, so that they keep their
+// precision, bytes are hex strings and tuples are objects.
+func ABIValueToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case string, bool:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(rv.Uint())
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		values := make([]interface{}, rv.Len())
+		for i := range values {
+			values[i] = ABIValueToJSON(rv.Index(i).Interface())
+		}
+		return values
+	case reflect.Struct:
+		// Tuples are decoded to structs whose fields are tagged with the
+		// names of their components
+		values := map[string]interface{}{}
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			name := field.Tag.Get("json")
+			if name == "" {
+				name = field.Name
+			}
+			values[name] = ABIValueToJSON(rv.Field(i).Interface())
+		}
+		return values
+	}
+	return value
+}
@@ -0,0 +1,157 @@
+package models_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustEventABI(t *testing.T, s string) models.EventABI {
+	var e models.EventABI
+	require.NoError(t, json.Unmarshal([]byte(s), &e))
+	return e
+}
+
+func TestEventABI_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	transferSig := "Transfer(address,address,uint256)"
+	tests := []struct {
+		name    string
+		input   string
+		wantSig string
+		wantErr bool
+	}{
+		{"signature", `"Transfer(address indexed from, address indexed to, uint256 value)"`, transferSig, false},
+		{"signature without names", `"Transfer(address indexed,address indexed,uint256)"`, transferSig, false},
+		{"signature without arguments", `"Paused()"`, "Paused()", false},
+		{"fragment", `{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256"}]}`, transferSig, false},
+		{"invalid signature", `"Transfer(address"`, "", true},
+		{"invalid type", `"Transfer(addr from)"`, "", true},
+		{"function fragment", `{"type":"function","name":"transfer","inputs":[]}`, "", true},
+		{"fragment without name", `{"type":"event","inputs":[]}`, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var e models.EventABI
+			err := json.Unmarshal([]byte(test.input), &e)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantSig, e.Sig())
+
+			b, err := json.Marshal(e)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.input, string(b))
+		})
+	}
+}
+
+func TestEventABI_Decode(t *testing.T) {
+	t.Parallel()
+
+	event := mustEventABI(t, `"Report(address indexed oracle, uint8 indexed round, string indexed label, int256 answer, bytes data, bytes4 tag, uint256[] history, address[2] signers, bool final)"`)
+
+	oracle := cltest.NewAddress()
+	signers := [2]common.Address{cltest.NewAddress(), cltest.NewAddress()}
+	answer, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	// Packing converts the answer to two's complement in place
+	data, err := event.Inputs.NonIndexed().Pack(
+		new(big.Int).Set(answer),
+		[]byte{0xde, 0xad},
+		[4]byte{1, 2, 3, 4},
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		signers,
+		true,
+	)
+	require.NoError(t, err)
+
+	labelHash := crypto.Keccak256Hash([]byte("ETH/USD"))
+	log := models.Log{
+		Topics: []common.Hash{
+			event.ID(),
+			common.BytesToHash(oracle.Bytes()),
+			common.BigToHash(big.NewInt(7)),
+			labelHash,
+		},
+		Data: data,
+	}
+
+	decoded, err := event.Decode(log)
+	require.NoError(t, err)
+
+	assert.Equal(t, oracle.Hex(), decoded.Get("oracle").String())
+	assert.Equal(t, "7", decoded.Get("round").String())
+	assert.Equal(t, labelHash.Hex(), decoded.Get("label").String())
+	assert.Equal(t, answer.String(), decoded.Get("answer").String())
+	assert.Equal(t, "0xdead", decoded.Get("data").String())
+	assert.Equal(t, "0x01020304", decoded.Get("tag").String())
+	assert.Equal(t, `["1","2"]`, decoded.Get("history").Raw)
+	assert.Equal(t, signers[1].Hex(), decoded.Get("signers.1").String())
+	assert.True(t, decoded.Get("final").Bool())
+}
+
+func TestEventABI_Decode_Errors(t *testing.T) {
+	t.Parallel()
+
+	event := mustEventABI(t, `"Transfer(address indexed from, address indexed to, uint256 value)"`)
+	value, err := abi.Arguments{event.Inputs[2]}.Pack(big.NewInt(1))
+	require.NoError(t, err)
+
+	_, err = event.Decode(models.Log{Topics: []common.Hash{cltest.NewHash()}, Data: value})
+	assert.Error(t, err, "other event")
+
+	_, err = event.Decode(models.Log{Topics: []common.Hash{event.ID(), cltest.NewHash()}, Data: value})
+	assert.Error(t, err, "missing indexed topic")
+
+	_, err = event.Decode(models.Log{Topics: []common.Hash{event.ID(), cltest.NewHash(), cltest.NewHash()}})
+	assert.Error(t, err, "missing data")
+}
+
+func TestEthLogEvent_JSON_DecodesEvent(t *testing.T) {
+	t.Parallel()
+
+	event := mustEventABI(t, `"Transfer(address indexed from, address indexed to, uint256 value, address address)"`)
+	from, to, address := cltest.NewAddress(), cltest.NewAddress(), cltest.NewAddress()
+	value, err := abi.Arguments{event.Inputs[2], event.Inputs[3]}.Pack(big.NewInt(100), address)
+	require.NoError(t, err)
+
+	initr := models.Initiator{
+		Type:            models.InitiatorEthLog,
+		InitiatorParams: models.InitiatorParams{EventABI: &event},
+	}
+	emitter := cltest.NewAddress()
+	le := models.InitiatorLogEvent{
+		Initiator: initr,
+		Log: models.Log{
+			Address: emitter,
+			Topics:  []common.Hash{event.ID(), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:    value,
+		},
+	}.LogRequest()
+
+	out, err := le.JSON()
+	require.NoError(t, err)
+	assert.Equal(t, from.Hex(), out.Get("args.from").String())
+	assert.Equal(t, to.Hex(), out.Get("args.to").String())
+	assert.Equal(t, "100", out.Get("args.value").String())
+	assert.Equal(t, address.Hex(), out.Get("args.address").String())
+	assert.Equal(t, emitter, common.HexToAddress(out.Get("address").String()))
+	assert.True(t, out.Get("topics").Exists())
+	assert.False(t, out.Get("from").Exists())
+
+	q, err := models.FilterQueryFactory(initr, nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]common.Hash{{event.ID()}}, q.Topics)
+}
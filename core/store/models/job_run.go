@@ -107,6 +107,36 @@ func (jr *JobRun) PreviousTaskRun() *TaskRun {
 	return nil
 }
 
+// IsTaskGraph returns true if the run's tasks form a graph, see IsTaskGraph.
+func (jr *JobRun) IsTaskGraph() bool {
+	for i := range jr.TaskRuns {
+		if len(jr.TaskRuns[i].TaskSpec.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskRunNamed returns the task run of the task with the given name, if any.
+func (jr *JobRun) TaskRunNamed(name string) *TaskRun {
+	for i := range jr.TaskRuns {
+		if jr.TaskRuns[i].TaskSpec.Name == name {
+			return &jr.TaskRuns[i]
+		}
+	}
+	return nil
+}
+
+// TaskRunWithStatus returns the first task run with the given status, if any.
+func (jr *JobRun) TaskRunWithStatus(status RunStatus) *TaskRun {
+	for i := range jr.TaskRuns {
+		if jr.TaskRuns[i].Status == status {
+			return &jr.TaskRuns[i]
+		}
+	}
+	return nil
+}
+
 // TasksRemain returns true if there are unfinished tasks left for this job run
 func (jr *JobRun) TasksRemain() bool {
 	_, runnable := jr.NextTaskRunIndex()
@@ -121,6 +151,26 @@ func (jr *JobRun) SetError(err error) {
 	jr.FinishedAt = null.TimeFrom(time.Now())
 }
 
+// Cancel stops the job run and its unfinished task runs, saving the reason
+// it was cancelled.
+func (jr *JobRun) Cancel(reason string) {
+	for i := range jr.TaskRuns {
+		if tr := &jr.TaskRuns[i]; !tr.Status.Finished() && !tr.Status.Skipped() {
+			tr.Status = RunStatusCancelled
+			tr.Result.Status = RunStatusCancelled
+		}
+	}
+	// A new result, as the current one can be shared with the last task run.
+	jr.Result = RunResult{
+		CachedJobRunID: jr.ID,
+		Data:           jr.Result.Data,
+		ErrorMessage:   null.StringFrom(reason),
+		Status:         RunStatusCancelled,
+	}
+	jr.Status = jr.Result.Status
+	jr.FinishedAt = null.TimeFrom(time.Now())
+}
+
 // ApplyResult updates the JobRun's Result and Status
 func (jr *JobRun) ApplyResult(result RunResult) error {
 	data, err := jr.Result.Data.Merge(result.Data)
@@ -152,13 +202,18 @@ func JobRunsWithStatus(runs []JobRun, status RunStatus) []JobRun {
 
 // RunRequest stores the fields used to initiate the parent job run.
 type RunRequest struct {
-	ID        uint `gorm:"primary_key"`
-	RequestID *string
-	TxHash    *common.Hash
-	BlockHash *common.Hash
-	Requester *common.Address
-	CreatedAt time.Time
-	Payment   *assets.Link
+	ID         uint `gorm:"primary_key"`
+	RequestID  *string
+	TxHash     *common.Hash
+	BlockHash  *common.Hash
+	Requester  *common.Address
+	CreatedAt  time.Time
+	Payment    *assets.Link
+	Expiration *time.Time
+
+	// ConsumedLog is the log that requested the run, recorded as consumed
+	// along with the run so that the log never starts another run.
+	ConsumedLog *ConsumedLog `gorm:"-" json:"-"`
 }
 
 // NewRunRequest returns a new RunRequest instance.
@@ -166,6 +221,12 @@ func NewRunRequest() RunRequest {
 	return RunRequest{CreatedAt: time.Now()}
 }
 
+// Expired returns true if the request has an expiration, after which the
+// requester can cancel it, and it has passed.
+func (rr RunRequest) Expired(now time.Time) bool {
+	return rr.Expiration != nil && !now.Before(*rr.Expiration)
+}
+
 // TaskRun stores the Task and represents the status of the
 // Task to be ran.
 type TaskRun struct {
@@ -178,6 +239,9 @@ type TaskRun struct {
 	TaskSpecID           uint          `json:"-" gorm:"index;not null REFERENCES task_specs(id)"`
 	MinimumConfirmations clnull.Uint32 `json:"minimumConfirmations"`
 	Confirmations        clnull.Uint32 `json:"confirmations"`
+	Attempts             uint32        `json:"attempts"`
+	LastError            null.String   `json:"lastError"`
+	RetryAt              null.Time     `json:"retryAt"`
 	CreatedAt            time.Time     `json:"-" gorm:"index"`
 }
 
@@ -215,6 +279,23 @@ func (tr *TaskRun) ApplyResult(result RunResult) {
 	tr.Status = result.Status
 }
 
+// MarkPendingRetry records the failed attempt's error and marks the task as
+// waiting to be attempted again at the given time.
+func (tr *TaskRun) MarkPendingRetry(err string, at time.Time) {
+	tr.LastError = null.StringFrom(err)
+	tr.RetryAt = null.TimeFrom(at)
+	tr.Status = RunStatusPendingRetry
+	tr.Result.Status = RunStatusPendingRetry
+	tr.Result.ErrorMessage = null.String{}
+}
+
+// MarkSkipped marks the task as not executed because a conditional task
+// branched past it.
+func (tr *TaskRun) MarkSkipped() {
+	tr.Status = RunStatusSkipped
+	tr.Result.Status = RunStatusSkipped
+}
+
 // MarkPendingConfirmations marks the task's status as blocked.
 func (tr *TaskRun) MarkPendingConfirmations() {
 	tr.Status = RunStatusPendingConfirmations
@@ -230,6 +311,7 @@ type RunResult struct {
 	Data            JSON        `json:"data" gorm:"type:text"`
 	Status          RunStatus   `json:"status"`
 	ErrorMessage    null.String `json:"error"`
+	ErrorKind       ErrorKind   `json:"-" gorm:"-"`
 }
 
 func RunResultComplete(resultVal interface{}) RunResult {
@@ -268,6 +350,7 @@ func (rr *RunResult) Add(key string, result interface{}) {
 // SetError marks the result as errored and saves the specified error message
 func (rr *RunResult) SetError(err error) {
 	rr.ErrorMessage = null.StringFrom(err.Error())
+	rr.ErrorKind = ClassifyError(err)
 	rr.Status = RunStatusErrored
 }
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
@@ -376,3 +377,36 @@ func TestJobRun_ApplyResult_ErrorSetsFinishedAt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, jobRun.FinishedAt.Valid)
 }
+
+func TestJobRun_Cancel(t *testing.T) {
+	t.Parallel()
+
+	job := cltest.NewJobWithWebInitiator()
+	job.Tasks = []models.TaskSpec{cltest.NewTask(t, "noop"), cltest.NewTask(t, "ethtx")}
+	run := job.NewRun(job.Initiators[0])
+	run.TaskRuns[0].ApplyResult(models.RunResult{Status: models.RunStatusCompleted})
+	run.Status = models.RunStatusPendingConfirmations
+
+	run.Cancel("Request 0x01 was cancelled by its requester")
+
+	assert.Equal(t, models.RunStatusCancelled, run.Status)
+	assert.True(t, run.Status.Finished())
+	assert.False(t, run.Status.Runnable())
+	assert.True(t, run.FinishedAt.Valid)
+	assert.Equal(t, "Request 0x01 was cancelled by its requester", run.Result.Error())
+	assert.Equal(t, models.RunStatusCompleted, run.TaskRuns[0].Status)
+	assert.Equal(t, models.RunStatusCancelled, run.TaskRuns[1].Status)
+	assert.False(t, run.TasksRemain())
+}
+
+func TestRunRequest_Expired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	past, future := now.Add(-time.Second), now.Add(time.Second)
+
+	assert.False(t, models.RunRequest{}.Expired(now))
+	assert.True(t, models.RunRequest{Expiration: &past}.Expired(now))
+	assert.True(t, models.RunRequest{Expiration: &now}.Expired(now))
+	assert.False(t, models.RunRequest{Expiration: &future}.Expired(now))
+}
@@ -58,6 +58,9 @@ type TaskSpecRequest struct {
 	Type          TaskType      `json:"type"`
 	Confirmations clnull.Uint32 `json:"confirmations"`
 	Params        JSON          `json:"params"`
+	Retry         *RetryPolicy  `json:"retry,omitempty"`
+	Name          string        `json:"name,omitempty"`
+	DependsOn     TaskNames     `json:"dependsOn,omitempty"`
 }
 
 // JobSpec is the definition for all the work to be carried out by the node
@@ -113,6 +116,9 @@ func NewJobFromRequest(jsr JobSpecRequest) JobSpec {
 			Type:          task.Type,
 			Confirmations: task.Confirmations,
 			Params:        task.Params,
+			Retry:         task.Retry,
+			Name:          task.Name,
+			DependsOn:     task.DependsOn,
 		})
 	}
 
@@ -204,6 +210,12 @@ func (j JobSpec) IsLogInitiated() bool {
 	return false
 }
 
+// IsBlockInitiated returns true if any of the job's initiators are
+// triggered by new heads.
+func (j JobSpec) IsBlockInitiated() bool {
+	return len(j.InitiatorsFor(InitiatorBlocks, InitiatorTxConfirmations)) > 0
+}
+
 // Ended returns true if the job has ended.
 func (j JobSpec) Ended(t time.Time) bool {
 	if !j.EndAt.Valid {
@@ -238,6 +250,14 @@ const (
 	InitiatorServiceAgreementExecutionLog = "execagreement"
 	// InitiatorExternal for tasks in a job to be trigger by an external party.
 	InitiatorExternal = "external"
+	// InitiatorFluxMonitor for tasks in a job to be ran when the value of
+	// polled feeds deviates from the one on chain, or it is too old.
+	InitiatorFluxMonitor = "fluxmonitor"
+	// InitiatorBlocks for tasks in a job to be ran every so many blocks.
+	InitiatorBlocks = "blocks"
+	// InitiatorTxConfirmations for tasks in a job to be ran once a
+	// transaction has enough confirmations.
+	InitiatorTxConfirmations = "txconfirmations"
 )
 
 // Initiator could be thought of as a trigger, defines how a Job can be
@@ -267,8 +287,37 @@ type InitiatorParams struct {
 	FromBlock  *Big              `json:"fromBlock,omitempty" gorm:"type:varchar(255)"`
 	ToBlock    *Big              `json:"toBlock,omitempty" gorm:"type:varchar(255)"`
 	Topics     Topics            `json:"topics,omitempty" gorm:"type:text"`
+	EventABI   *EventABI         `json:"eventABI,omitempty" gorm:"type:text"`
+
+	Feeds           *JSON    `json:"feeds,omitempty" gorm:"type:text"`
+	Threshold       float64  `json:"threshold,omitempty"`
+	Precision       int32    `json:"precision,omitempty"`
+	PollingInterval Duration `json:"pollingInterval,omitempty"`
+	Heartbeat       Duration `json:"heartbeat,omitempty"`
+
+	BlockInterval uint64      `json:"blockInterval,omitempty"`
+	TxHash        common.Hash `json:"txHash,omitempty"`
+	Confirmations uint64      `json:"confirmations,omitempty"`
+
+	TimeZone    string        `json:"timeZone,omitempty"`
+	Jitter      Duration      `json:"jitter,omitempty"`
+	CatchUp     CatchUpPolicy `json:"catchUp,omitempty"`
+	LastFiredAt null.Time     `json:"-"`
 }
 
+// CatchUpPolicy is how a cron initiator makes up for the fires it missed
+// while the node was down.
+type CatchUpPolicy string
+
+const (
+	// CatchUpSkip drops the missed fires, and is the default.
+	CatchUpSkip = CatchUpPolicy("skip")
+	// CatchUpRunOnce runs the job once if any fire was missed.
+	CatchUpRunOnce = CatchUpPolicy("run-once")
+	// CatchUpRunAll runs the job once for every missed fire.
+	CatchUpRunAll = CatchUpPolicy("run-all")
+)
+
 type Topics [][]common.Hash
 
 func (t Topics) Scan(value interface{}) error {
@@ -327,6 +376,51 @@ type TaskSpec struct {
 	Type          TaskType      `json:"type" gorm:"index;not null"`
 	Confirmations clnull.Uint32 `json:"confirmations"`
 	Params        JSON          `json:"params" gorm:"type:text"`
+	Retry         *RetryPolicy  `json:"retry,omitempty" gorm:"type:text"`
+	Name          string        `json:"name,omitempty"`
+	DependsOn     TaskNames     `json:"dependsOn,omitempty" gorm:"type:text"`
+}
+
+// IsTaskGraph returns true if any of the tasks declares dependencies. The
+// tasks of such a job form a directed acyclic graph: a task runs once every
+// task it depends on has completed, tasks without dependencies run as soon
+// as the run starts, and independent tasks run concurrently.
+func IsTaskGraph(tasks []TaskSpec) bool {
+	for _, task := range tasks {
+		if len(task.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskNames is a list of task names serializable to and from a database.
+type TaskNames []string
+
+// Value returns the string value to be written to the database.
+func (t TaskNames) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+// Scan parses the database value as a string.
+func (t *TaskNames) Scan(value interface{}) error {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("Unable to convert %v of %T to TaskNames", value, value)
+	}
+
+	if len(str) == 0 {
+		*t = nil
+		return nil
+	}
+	*t = strings.Split(str, ",")
+	return nil
 }
 
 // TaskType defines what Adapter a TaskSpec will use.
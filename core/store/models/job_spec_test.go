@@ -103,6 +103,24 @@ func TestUnmarshalInitiatorRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "CronInitiatorIgnoresLastFiredAt",
+			JSON: map[string]interface{}{
+				"type": "cron",
+				"params": map[string]string{
+					"schedule":    "* * * * *",
+					"catchUp":     "run-all",
+					"lastFiredAt": "2000-01-01T00:00:00Z",
+				},
+			},
+			Expect: models.InitiatorRequest{
+				Type: models.InitiatorCron,
+				InitiatorParams: models.InitiatorParams{
+					Schedule: "* * * * *",
+					CatchUp:  models.CatchUpRunAll,
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
@@ -150,6 +168,32 @@ func TestNewJobFromRequest(t *testing.T) {
 	assert.Equal(t, fetched2.MinPayment, assets.NewLink(5))
 }
 
+func TestNewJobFromRequest_RetryPolicy(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	var jsr models.JobSpecRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"initiators": [{"type": "web"}],
+		"tasks": [
+			{"type": "HttpGet", "params": {"get": "https://example.com"}, "retry": {"maxAttempts": 3, "initial": "2s"}},
+			{"type": "NoOp"}
+		]
+	}`), &jsr))
+
+	j := models.NewJobFromRequest(jsr)
+	require.NoError(t, store.CreateJob(&j))
+
+	fetched, err := store.FindJob(j.ID)
+	require.NoError(t, err)
+	require.Len(t, fetched.Tasks, 2)
+	require.NotNil(t, fetched.Tasks[0].Retry)
+	assert.Equal(t, uint32(3), fetched.Tasks[0].Retry.MaxAttempts)
+	assert.Equal(t, 2*time.Second, fetched.Tasks[0].Retry.Initial.Duration())
+	assert.Nil(t, fetched.Tasks[1].Retry)
+}
+
 func TestJobSpec_Save(t *testing.T) {
 	t.Parallel()
 	store, cleanup := cltest.NewStore(t)
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LogCursor is the block an initiator resumes reading logs from after a
+// restart. Every log of the initiator in an earlier block has been
+// processed.
+type LogCursor struct {
+	InitiatorID uint   `gorm:"primary_key;auto_increment:false"`
+	BlockNumber uint64 `gorm:"not null"`
+	UpdatedAt   time.Time
+}
+
+// ConsumedLog records that an initiator processed a log, so that it is not
+// processed again when it is delivered a second time, by a backfill or
+// after resubscribing.
+type ConsumedLog struct {
+	ID          uint        `gorm:"primary_key"`
+	InitiatorID uint        `gorm:"not null;unique_index:idx_consumed_logs_initiator_log"`
+	TxHash      common.Hash `gorm:"not null;unique_index:idx_consumed_logs_initiator_log"`
+	LogIndex    uint        `gorm:"not null;unique_index:idx_consumed_logs_initiator_log"`
+	BlockNumber uint64      `gorm:"not null"`
+	CreatedAt   time.Time
+}
+
+// NewConsumedLog returns a ConsumedLog of the log for the initiator.
+func NewConsumedLog(initiatorID uint, log Log) ConsumedLog {
+	return ConsumedLog{
+		InitiatorID: initiatorID,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		BlockNumber: log.BlockNumber,
+	}
+}
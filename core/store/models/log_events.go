@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -52,6 +53,9 @@ var (
 	// ChainlinkClient.validateChainlinkCallback(requestId).
 	// https://github.com/smartcontractkit/chainlink/blob/master/evm/contracts/ChainlinkClient.sol
 	ChainlinkFulfilledTopic = utils.MustHash("ChainlinkFulfilled(bytes32)")
+	// CancelOracleRequestLogTopic is the signature for the event emitted by
+	// the oracle when a requester cancels a request after its expiration.
+	CancelOracleRequestLogTopic = utils.MustHash("CancelOracleRequest(bytes32)")
 	// OracleFullfillmentFunctionID0original is the original function selector for fulfilling Ethereum requests.
 	OracleFullfillmentFunctionID0original = utils.MustHash("fulfillData(uint256,bytes32)").Hex()[:10]
 	// OracleFulfillmentFunctionID20190123withFulfillmentParams is the function selector for fulfilling Ethereum requests,
@@ -65,6 +69,7 @@ var (
 type logRequestParser interface {
 	parseJSON(Log) (JSON, error)
 	parseRequestID(Log) string
+	parseExpiration(Log) *time.Time
 }
 
 // topicFactoryMap maps the log topic to a factory method that returns an
@@ -100,6 +105,9 @@ func FilterQueryFactory(i Initiator, from *big.Int) (ethereum.FilterQuery, error
 		}
 		q.ToBlock = i.InitiatorParams.ToBlock.ToInt()
 		q.Topics = i.Topics
+		if len(q.Topics) == 0 && i.EventABI != nil && !i.EventABI.Anonymous {
+			q.Topics = [][]common.Hash{{i.EventABI.ID()}}
+		}
 		return q, nil
 
 	case InitiatorRunLog:
@@ -117,6 +125,36 @@ func FilterQueryFactory(i Initiator, from *big.Int) (ethereum.FilterQuery, error
 	}
 }
 
+// RequestCancelledFilterQuery returns the ethereum FilterQuery for the
+// cancellations of the requests of a run log initiator's oracle.
+func RequestCancelledFilterQuery(i Initiator, from *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: from,
+		Addresses: utils.WithoutZeroAddresses([]common.Address{i.Address}),
+		Topics:    [][]common.Hash{{CancelOracleRequestLogTopic}},
+	}
+}
+
+// RequestFulfilledFilterQuery returns the ethereum FilterQuery for the
+// ChainlinkFulfilled events of every requester, which are emitted by the
+// requester, not the oracle, and must be matched against a run's requester.
+func RequestFulfilledFilterQuery(from *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: from,
+		Topics:    [][]common.Hash{{ChainlinkFulfilledTopic}},
+	}
+}
+
+// LifecycleRequestID returns the ID of the request that a CancelOracleRequest
+// or ChainlinkFulfilled log is for, formatted like RunRequest.RequestID.
+func LifecycleRequestID(log Log) (string, error) {
+	topic, err := log.getTopic(1)
+	if err != nil {
+		return "", errors.Wrap(err, "log#getTopic(1)")
+	}
+	return topic.Hex(), nil
+}
+
 // LogRequest is the interface to allow polymorphic functionality of different
 // types of LogEvents.
 // i.e. EthLogEvent, RunLogEvent, ServiceAgreementLogEvent, OracleLogEvent
@@ -240,6 +278,21 @@ type EthLogEvent struct {
 	InitiatorLogEvent
 }
 
+// JSON returns the eth log as JSON, along with the arguments of the
+// initiator's event, if it has one, decoded into named fields under "args",
+// so that they cannot overwrite the fields of the log.
+func (le EthLogEvent) JSON() (JSON, error) {
+	out, err := le.InitiatorLogEvent.JSON()
+	if err != nil || le.Initiator.EventABI == nil {
+		return out, err
+	}
+	args, err := le.Initiator.EventABI.Decode(le.Log)
+	if err != nil {
+		return out, err
+	}
+	return out.Add("args", args)
+}
+
 // RunLogEvent provides functionality specific to a log event emitted
 // for a run log initiator.
 type RunLogEvent struct {
@@ -332,11 +385,12 @@ func (le RunLogEvent) RunRequest() (RunRequest, error) {
 	str := parser.parseRequestID(le.Log)
 	requester := le.Requester()
 	return RunRequest{
-		RequestID: &str,
-		TxHash:    &txHash,
-		BlockHash: &blockHash,
-		Requester: &requester,
-		Payment:   payment,
+		RequestID:  &str,
+		TxHash:     &txHash,
+		BlockHash:  &blockHash,
+		Requester:  &requester,
+		Payment:    payment,
+		Expiration: parser.parseExpiration(le.Log),
 	}, nil
 }
 
@@ -395,6 +449,11 @@ func (parseRunLog0original) parseRequestID(log Log) string {
 	return common.BytesToHash(log.Data[:idSize]).Hex()
 }
 
+// parseExpiration returns nothing, the original format has no expiration.
+func (parseRunLog0original) parseExpiration(Log) *time.Time {
+	return nil
+}
+
 // parseRunLog20190123withFulfillmentParams parses the OracleRequest log format
 // which includes the callback, the payment amount, and expiration time
 // The fulfillment also includes the callback, payment amount, and expiration,
@@ -432,6 +491,11 @@ func (parseRunLog20190123withFulfillmentParams) parseRequestID(log Log) string {
 	return common.BytesToHash(log.Data[:idSize]).Hex()
 }
 
+func (parseRunLog20190123withFulfillmentParams) parseExpiration(log Log) *time.Time {
+	start := idSize + versionSize + callbackAddrSize + callbackFuncSize
+	return parseExpirationAt(log.Data, start)
+}
+
 // parseRunLog20190207withoutIndexes parses the OracleRequest log format after
 // the sender and payment amount indexes were removed.
 // Additionally, the version field for the data payload was moved next to the
@@ -467,6 +531,25 @@ func (parseRunLog20190207withoutIndexes) parseRequestID(log Log) string {
 	return common.BytesToHash(log.Data[start : start+idSize]).Hex()
 }
 
+func (parseRunLog20190207withoutIndexes) parseExpiration(log Log) *time.Time {
+	start := requesterSize + idSize + paymentSize + callbackAddrSize + callbackFuncSize
+	return parseExpirationAt(log.Data, start)
+}
+
+// parseExpirationAt decodes the expiration, in seconds since the epoch, from
+// the word of the log's data at the given offset.
+func parseExpirationAt(data []byte, start int) *time.Time {
+	if len(data) < start+expirationSize {
+		return nil
+	}
+	seconds := new(big.Int).SetBytes(data[start : start+expirationSize])
+	if !seconds.IsInt64() {
+		return nil
+	}
+	expiration := time.Unix(seconds.Int64(), 0)
+	return &expiration
+}
+
 func bytesToHex(data []byte) string {
 	return utils.AddHexPrefix(hex.EncodeToString(data))
 }
@@ -424,6 +424,7 @@ func TestRunLogEvent_RunRequest(t *testing.T) {
 		wantTxHash    string
 		wantBlockHash string
 		wantRequester common.Address
+		wantExpiry    int64
 	}{
 		{
 			name:          "old non-commitment",
@@ -440,6 +441,7 @@ func TestRunLogEvent_RunRequest(t *testing.T) {
 			wantTxHash:    "0x04250548cd0b5d03b3bf1331aa83f32b35879440db31a6008d151260a5f3cc76",
 			wantBlockHash: "0xfa0c0d01ce8bd7100b73b1609ababc020e7f51dac75186bb799277c6b4b71e1c",
 			wantRequester: common.HexToAddress("0x9fbda871d559710256a2502a2517b794b482db41"),
+			wantExpiry:    1548383032,
 		},
 		{
 			name:          "20190207 without indexes",
@@ -448,6 +450,7 @@ func TestRunLogEvent_RunRequest(t *testing.T) {
 			wantTxHash:    "0x04250548cd0b5d03b3bf1331aa83f32b35879440db31a6008d151260a5f3cc76",
 			wantBlockHash: "0x000c0d01ce8bd7100b73b1609ababc020e7f51dac75186bb799277c6b4b71e1c",
 			wantRequester: common.HexToAddress("0x9FBDa871d559710256a2502A2517b794B482Db40"),
+			wantExpiry:    1548383032,
 		},
 	}
 
@@ -461,10 +464,50 @@ func TestRunLogEvent_RunRequest(t *testing.T) {
 			assert.Equal(t, test.wantTxHash, rr.TxHash.Hex())
 			assert.Equal(t, test.wantBlockHash, rr.BlockHash.Hex())
 			assert.Equal(t, &test.wantRequester, rr.Requester)
+			if test.wantExpiry == 0 {
+				assert.Nil(t, rr.Expiration)
+			} else {
+				require.NotNil(t, rr.Expiration)
+				assert.Equal(t, test.wantExpiry, rr.Expiration.Unix())
+			}
 		})
 	}
 }
 
+func TestRequestCancelledFilterQuery(t *testing.T) {
+	t.Parallel()
+
+	address := cltest.NewAddress()
+	initr := models.Initiator{Type: models.InitiatorRunLog, InitiatorParams: models.InitiatorParams{Address: address}}
+	filter := models.RequestCancelledFilterQuery(initr, big.NewInt(42))
+
+	assert.Equal(t, big.NewInt(42), filter.FromBlock)
+	assert.Equal(t, []common.Address{address}, filter.Addresses)
+	assert.Equal(t, [][]common.Hash{{models.CancelOracleRequestLogTopic}}, filter.Topics)
+}
+
+func TestRequestFulfilledFilterQuery(t *testing.T) {
+	t.Parallel()
+
+	filter := models.RequestFulfilledFilterQuery(big.NewInt(42))
+
+	assert.Equal(t, big.NewInt(42), filter.FromBlock)
+	assert.Empty(t, filter.Addresses)
+	assert.Equal(t, [][]common.Hash{{models.ChainlinkFulfilledTopic}}, filter.Topics)
+}
+
+func TestLifecycleRequestID(t *testing.T) {
+	t.Parallel()
+
+	requestID := cltest.NewHash()
+	id, err := models.LifecycleRequestID(models.Log{Topics: []common.Hash{models.CancelOracleRequestLogTopic, requestID}})
+	require.NoError(t, err)
+	assert.Equal(t, requestID.Hex(), id)
+
+	_, err = models.LifecycleRequestID(models.Log{Topics: []common.Hash{models.CancelOracleRequestLogTopic}})
+	assert.Error(t, err)
+}
+
 func TestIDToTopic(t *testing.T) {
 	id, err := models.NewIDFromString("ffffffffffffffffffffffffffffffff")
 	require.NoError(t, err)
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Backoff is the strategy used to space out the attempts of a retried task.
+type Backoff string
+
+const (
+	// BackoffExponential doubles the delay after every failed attempt.
+	BackoffExponential = Backoff("exponential")
+	// BackoffLinear grows the delay by the initial delay after every failed attempt.
+	BackoffLinear = Backoff("linear")
+	// BackoffConstant waits the initial delay between every attempt.
+	BackoffConstant = Backoff("constant")
+)
+
+// ErrorKind classifies task errors so that a RetryPolicy can decide whether
+// they are worth retrying.
+type ErrorKind string
+
+const (
+	// ErrorKindTimeout is an error caused by a request or deadline timing out.
+	ErrorKindTimeout = ErrorKind("timeout")
+	// ErrorKind4xx is an HTTP response with a client error status code.
+	ErrorKind4xx = ErrorKind("4xx")
+	// ErrorKind5xx is an HTTP response with a server error status code.
+	ErrorKind5xx = ErrorKind("5xx")
+	// ErrorKindOther is any error not covered by a more specific kind.
+	ErrorKindOther = ErrorKind("other")
+)
+
+// DefaultRetryInitialDelay is the delay before the first retry when the
+// policy does not specify one.
+const DefaultRetryInitialDelay = time.Second
+
+// RetryPolicy describes how many times and how often a failed task is
+// attempted again before its run is marked as errored.
+type RetryPolicy struct {
+	MaxAttempts uint32      `json:"maxAttempts"`
+	Backoff     Backoff     `json:"backoff,omitempty"`
+	Initial     Duration    `json:"initial,omitempty"`
+	Max         Duration    `json:"max,omitempty"`
+	RetryOn     []ErrorKind `json:"retryOn,omitempty"`
+}
+
+// Validate returns an error if the policy cannot be applied.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return errors.New("retry maxAttempts must be at least 1")
+	}
+	switch p.Backoff {
+	case "", BackoffExponential, BackoffLinear, BackoffConstant:
+	default:
+		return fmt.Errorf("retry backoff %q is not one of exponential, linear or constant", p.Backoff)
+	}
+	if p.Initial < 0 || p.Max < 0 {
+		return errors.New("retry delays cannot be negative")
+	}
+	if p.Max > 0 && p.Max.Duration() < p.initial() {
+		return errors.New("retry max cannot be less than initial")
+	}
+	for _, kind := range p.RetryOn {
+		switch kind {
+		case ErrorKindTimeout, ErrorKind4xx, ErrorKind5xx, ErrorKindOther:
+		default:
+			return fmt.Errorf("retryOn %q is not one of timeout, 4xx, 5xx or other", kind)
+		}
+	}
+	return nil
+}
+
+func (p RetryPolicy) initial() time.Duration {
+	if p.Initial == 0 {
+		return DefaultRetryInitialDelay
+	}
+	return p.Initial.Duration()
+}
+
+// Delay returns how long to wait before the attempt following the given
+// failed attempt, counting from 1.
+func (p RetryPolicy) Delay(failedAttempt uint32) time.Duration {
+	if failedAttempt < 1 {
+		failedAttempt = 1
+	}
+	initial := p.initial()
+	delay := initial
+	switch p.Backoff {
+	case BackoffConstant:
+	case BackoffLinear:
+		delay = initial * time.Duration(failedAttempt)
+	default:
+		for i := uint32(1); i < failedAttempt && (p.Max == 0 || delay < p.Max.Duration()); i++ {
+			delay *= 2
+		}
+	}
+	if p.Max > 0 && delay > p.Max.Duration() {
+		return p.Max.Duration()
+	}
+	return delay
+}
+
+// ShouldRetry returns true if another attempt is allowed after the given
+// number of attempts failed with an error of the given kind. An empty RetryOn
+// list retries every kind of error.
+func (p RetryPolicy) ShouldRetry(attempts uint32, kind ErrorKind) bool {
+	if attempts >= p.MaxAttempts {
+		return false
+	}
+	if len(p.RetryOn) == 0 {
+		return true
+	}
+	for _, k := range p.RetryOn {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns this instance serialized for database storage.
+func (p RetryPolicy) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan reads the database value and returns an instance.
+func (p *RetryPolicy) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	case []byte:
+		return json.Unmarshal(v, p)
+	default:
+		return fmt.Errorf("Unable to convert %v of %T to RetryPolicy", value, value)
+	}
+}
+
+// HTTPResponseError is returned when a request receives an error status
+// code. Its message is the body of the response.
+type HTTPResponseError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e HTTPResponseError) Error() string {
+	return e.Body
+}
+
+// ClassifyError returns the ErrorKind of err, looking through any wrapping.
+func ClassifyError(err error) ErrorKind {
+	switch cause := errors.Cause(err).(type) {
+	case HTTPResponseError:
+		if cause.StatusCode >= 500 {
+			return ErrorKind5xx
+		}
+		return ErrorKind4xx
+	case net.Error:
+		if cause.Timeout() {
+			return ErrorKindTimeout
+		}
+	}
+	if errors.Cause(err) == context.DeadlineExceeded {
+		return ErrorKindTimeout
+	}
+	return ErrorKindOther
+}
@@ -0,0 +1,141 @@
+package models_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var p models.RetryPolicy
+	input := `{"maxAttempts":5,"backoff":"exponential","initial":"1s","max":"1m","retryOn":["timeout","5xx"]}`
+	require.NoError(t, json.Unmarshal([]byte(input), &p))
+
+	assert.Equal(t, models.RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     models.BackoffExponential,
+		Initial:     models.Duration(time.Second),
+		Max:         models.Duration(time.Minute),
+		RetryOn:     []models.ErrorKind{models.ErrorKindTimeout, models.ErrorKind5xx},
+	}, p)
+
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"maxAttempts":5,"backoff":"exponential","initial":"1s","max":"1m0s","retryOn":["timeout","5xx"]}`, string(b))
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy models.RetryPolicy
+		want   string
+	}{
+		{"defaults", models.RetryPolicy{MaxAttempts: 1}, ""},
+		{"no attempts", models.RetryPolicy{}, "retry maxAttempts must be at least 1"},
+		{"unknown backoff", models.RetryPolicy{MaxAttempts: 2, Backoff: "fibonacci"}, `retry backoff "fibonacci" is not one of exponential, linear or constant`},
+		{"negative delay", models.RetryPolicy{MaxAttempts: 2, Initial: models.Duration(-time.Second)}, "retry delays cannot be negative"},
+		{"max below initial", models.RetryPolicy{MaxAttempts: 2, Initial: models.Duration(time.Minute), Max: models.Duration(time.Second)}, "retry max cannot be less than initial"},
+		{"unknown error kind", models.RetryPolicy{MaxAttempts: 2, RetryOn: []models.ErrorKind{"3xx"}}, `retryOn "3xx" is not one of timeout, 4xx, 5xx or other`},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := test.policy.Validate()
+			if test.want == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		backoff models.Backoff
+		max     time.Duration
+		want    []time.Duration
+	}{
+		{"exponential", models.BackoffExponential, 0, []time.Duration{1, 2, 4, 8}},
+		{"exponential capped", models.BackoffExponential, 5 * time.Second, []time.Duration{1, 2, 4, 5}},
+		{"default is exponential", "", 0, []time.Duration{1, 2, 4, 8}},
+		{"linear", models.BackoffLinear, 0, []time.Duration{1, 2, 3, 4}},
+		{"constant", models.BackoffConstant, 0, []time.Duration{1, 1, 1, 1}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			p := models.RetryPolicy{
+				MaxAttempts: 5,
+				Backoff:     test.backoff,
+				Max:         models.Duration(test.max),
+			}
+			for i, want := range test.want {
+				assert.Equal(t, want*time.Second, p.Delay(uint32(i+1)))
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	t.Parallel()
+
+	any := models.RetryPolicy{MaxAttempts: 2}
+	assert.True(t, any.ShouldRetry(1, models.ErrorKindOther))
+	assert.True(t, any.ShouldRetry(1, models.ErrorKind4xx))
+	assert.False(t, any.ShouldRetry(2, models.ErrorKindOther))
+
+	some := models.RetryPolicy{MaxAttempts: 3, RetryOn: []models.ErrorKind{models.ErrorKindTimeout, models.ErrorKind5xx}}
+	assert.True(t, some.ShouldRetry(1, models.ErrorKindTimeout))
+	assert.True(t, some.ShouldRetry(2, models.ErrorKind5xx))
+	assert.False(t, some.ShouldRetry(1, models.ErrorKind4xx))
+	assert.False(t, some.ShouldRetry(1, models.ErrorKindOther))
+	assert.False(t, some.ShouldRetry(3, models.ErrorKind5xx))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want models.ErrorKind
+	}{
+		{"server error", models.HTTPResponseError{StatusCode: http.StatusBadGateway}, models.ErrorKind5xx},
+		{"client error", models.HTTPResponseError{StatusCode: http.StatusNotFound}, models.ErrorKind4xx},
+		{"wrapped server error", pkgerrors.Wrap(models.HTTPResponseError{StatusCode: 500}, "POST response"), models.ErrorKind5xx},
+		{"network timeout", timeoutError{}, models.ErrorKindTimeout},
+		{"deadline exceeded", context.DeadlineExceeded, models.ErrorKindTimeout},
+		{"other", errors.New("boom"), models.ErrorKindOther},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, models.ClassifyError(test.err))
+		})
+	}
+}
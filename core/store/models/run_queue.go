@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store/assets"
+)
+
+// QueuedRun is a job run waiting for, or being processed by, one of the
+// JobRunner's workers. It is kept until the run stops being runnable, so that
+// the queue survives restarts.
+type QueuedRun struct {
+	JobRunID     *ID          `json:"id" gorm:"primary_key;type:varchar(36) REFERENCES job_runs(id) ON DELETE CASCADE"`
+	JobSpecID    *ID          `json:"jobId" gorm:"index;not null"`
+	Payment      *assets.Link `json:"payment,omitempty" gorm:"type:varchar(255)"`
+	RunCreatedAt time.Time    `json:"runCreatedAt"`
+	CreatedAt    time.Time    `json:"createdAt" gorm:"index"`
+}
+
+// NewQueuedRun returns a QueuedRun for the given run.
+func NewQueuedRun(run JobRun) QueuedRun {
+	return QueuedRun{
+		JobRunID:     run.ID,
+		JobSpecID:    run.JobSpecID,
+		Payment:      run.Payment,
+		RunCreatedAt: run.CreatedAt,
+	}
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (qr QueuedRun) GetID() string {
+	return qr.JobRunID.String()
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (qr QueuedRun) GetName() string {
+	return "queuedRuns"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (qr *QueuedRun) SetID(value string) error {
+	qr.JobRunID = &ID{}
+	return qr.JobRunID.UnmarshalText([]byte(value))
+}
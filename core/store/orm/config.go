@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -142,6 +143,24 @@ func (c Config) MaxRPCCallsPerSecond() uint64 {
 	return c.viper.GetUint64(EnvVarName("MaxRPCCallsPerSecond"))
 }
 
+// MaxConcurrentRuns is the number of workers the JobRunner uses to process
+// runs, and so the most runs that can be processed at the same time.
+func (c Config) MaxConcurrentRuns() uint64 {
+	return c.viper.GetUint64(EnvVarName("MaxConcurrentRuns"))
+}
+
+// MaxConcurrentRunsPerJob is the most runs of a single job that can be
+// processed at the same time. Zero means no limit beyond MaxConcurrentRuns.
+func (c Config) MaxConcurrentRunsPerJob() uint64 {
+	return c.viper.GetUint64(EnvVarName("MaxConcurrentRunsPerJob"))
+}
+
+// MetricsPublic serves the Prometheus /metrics endpoint without
+// authentication, so that a scraper without a session can read it.
+func (c Config) MetricsPublic() bool {
+	return c.viper.GetBool(EnvVarName("MetricsPublic"))
+}
+
 // MaximumServiceDuration is the maximum time that a service agreement can run
 // from after the time it is created. Default 1 year = 365 * 24h = 8760h
 func (c Config) MaximumServiceDuration() time.Duration {
@@ -154,6 +173,12 @@ func (c Config) MinimumServiceDuration() time.Duration {
 	return c.viper.GetDuration(EnvVarName("MinimumServiceDuration"))
 }
 
+// EthDynamicFees sends transactions as EIP-1559 dynamic fee transactions,
+// priced from the latest block's base fee, instead of with a gas price.
+func (c Config) EthDynamicFees() bool {
+	return c.viper.GetBool(EnvVarName("EthDynamicFees"))
+}
+
 // EthGasBumpThreshold represents the maximum amount a transaction's ETH amount
 // should be increased in order to facilitate a transaction.
 func (c Config) EthGasBumpThreshold() uint64 {
@@ -166,6 +191,12 @@ func (c Config) EthGasBumpWei() *big.Int {
 	return c.getWithFallback("EthGasBumpWei", parseBigInt).(*big.Int)
 }
 
+// EthGasLimitMargin is the percentage added to the gas estimate of a
+// simulated transaction to set its gas limit.
+func (c Config) EthGasLimitMargin() uint64 {
+	return c.viper.GetUint64(EnvVarName("EthGasLimitMargin"))
+}
+
 // EthGasPriceDefault represents the default gas price for transactions.
 func (c Config) EthGasPriceDefault() *big.Int {
 	if c.runtimeStore != nil {
@@ -179,6 +210,62 @@ func (c Config) EthGasPriceDefault() *big.Int {
 	return c.getWithFallback("EthGasPriceDefault", parseBigInt).(*big.Int)
 }
 
+// EthGasPriceOracleBlocks is the number of recent blocks whose transactions'
+// gas prices are sampled to price new transactions. When zero, transactions
+// are sent with EthGasPriceDefault.
+func (c Config) EthGasPriceOracleBlocks() uint64 {
+	return c.viper.GetUint64(EnvVarName("EthGasPriceOracleBlocks"))
+}
+
+// EthGasPricePercentile is the percentile of the gas prices sampled from
+// recent blocks that new transactions are sent with.
+func (c Config) EthGasPricePercentile() uint64 {
+	return c.viper.GetUint64(EnvVarName("EthGasPricePercentile"))
+}
+
+// EthLogBlockRange is the largest range of blocks requested with a single
+// eth_getLogs call when polling an HTTP Ethereum node for logs, or when
+// backfilling the logs of initiators.
+func (c Config) EthLogBlockRange() uint64 {
+	return c.viper.GetUint64(EnvVarName("EthLogBlockRange"))
+}
+
+// EthMaxGasPriceWei is the highest gas price a transaction is ever sent with,
+// including when its gas is bumped.
+func (c Config) EthMaxGasPriceWei() *big.Int {
+	return c.getWithFallback("EthMaxGasPriceWei", parseBigInt).(*big.Int)
+}
+
+// EthPollInterval is how often an HTTP Ethereum node, which cannot push
+// heads and logs over a subscription, is polled for them instead.
+func (c Config) EthPollInterval() time.Duration {
+	return c.viper.GetDuration(EnvVarName("EthPollInterval"))
+}
+
+// EthPriorityFeeWei is the priority fee per gas that dynamic fee
+// transactions pay the miner on top of the base fee, unless the job sets
+// its own.
+func (c Config) EthPriorityFeeWei() *big.Int {
+	return c.getWithFallback("EthPriorityFeeWei", parseBigInt).(*big.Int)
+}
+
+// EthStuckTxBlocks is the number of blocks after its first attempt that an
+// unconfirmed transaction is considered stuck, and its account's nonces are
+// checked for recovery. Zero disables recovery.
+func (c Config) EthStuckTxBlocks() uint64 {
+	return c.viper.GetUint64(EnvVarName("EthStuckTxBlocks"))
+}
+
+// EthTxSimulation runs transactions against the pending state with eth_call
+// and eth_estimateGas before sending them, so that those that would revert
+// fail without spending gas. It is off by default, as it adds two requests
+// to every transaction, and sends those without a gas limit of their own
+// with their estimate plus ETH_GAS_LIMIT_MARGIN percent rather than
+// DefaultGasLimit.
+func (c Config) EthTxSimulation() bool {
+	return c.viper.GetBool(EnvVarName("EthTxSimulation"))
+}
+
 // SetEthGasPriceDefault saves a runtime value for the default gas price for transactions
 func (c Config) SetEthGasPriceDefault(value *big.Int) error {
 	if c.runtimeStore == nil {
@@ -188,10 +275,25 @@ func (c Config) SetEthGasPriceDefault(value *big.Int) error {
 }
 
 // EthereumURL represents the URL of the Ethereum node to connect Chainlink to.
+// A node served over HTTP is polled every EthPollInterval for heads and logs.
 func (c Config) EthereumURL() string {
 	return c.viper.GetString(EnvVarName("EthereumURL"))
 }
 
+// EthereumSecondaryURLs represents the comma separated list of fallback
+// Ethereum node URLs, in order of preference, to fail over to when the
+// primary EthereumURL is unhealthy. HTTP URLs are polled like an HTTP
+// EthereumURL.
+func (c Config) EthereumSecondaryURLs() []string {
+	urls := []string{}
+	for _, u := range strings.Split(c.viper.GetString(EnvVarName("EthereumSecondaryURLs")), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
 // JSONConsole enables the JSON console.
 func (c Config) JSONConsole() bool {
 	return c.viper.GetBool(EnvVarName("JSONConsole"))
@@ -289,6 +391,11 @@ func (c Config) ReaperExpiration() time.Duration {
 	return c.viper.GetDuration(EnvVarName("ReaperExpiration"))
 }
 
+// RunQueuePriority determines which queued run the JobRunner processes next.
+func (c Config) RunQueuePriority() RunQueuePriority {
+	return c.getWithFallback("RunQueuePriority", parseRunQueuePriority).(RunQueuePriority)
+}
+
 // RootDir represents the location on the file system where Chainlink should
 // keep its files.
 func (c Config) RootDir() string {
@@ -477,6 +584,14 @@ func parsePort(str string) (interface{}, error) {
 	return uint16(d), err
 }
 
+func parseRunQueuePriority(str string) (interface{}, error) {
+	switch p := RunQueuePriority(strings.ToLower(str)); p {
+	case RunQueuePriorityAge, RunQueuePriorityPayment:
+		return p, nil
+	}
+	return nil, fmt.Errorf("Unable to parse '%s' into a run queue priority, must be one of: %s, %s", str, RunQueuePriorityAge, RunQueuePriorityPayment)
+}
+
 func parseURL(s string) (interface{}, error) {
 	return url.Parse(s)
 }
@@ -497,6 +612,17 @@ func parseHomeDir(str string) (interface{}, error) {
 	return filepath.ToSlash(exp), nil
 }
 
+// RunQueuePriority is the order in which the JobRunner processes queued runs.
+type RunQueuePriority string
+
+const (
+	// RunQueuePriorityAge processes the oldest runs first.
+	RunQueuePriorityAge = RunQueuePriority("age")
+	// RunQueuePriorityPayment processes the best paid runs first, oldest
+	// first among runs with equal payment.
+	RunQueuePriorityPayment = RunQueuePriority("payment")
+)
+
 // LogLevel determines the verbosity of the events to be logged.
 type LogLevel struct {
 	zapcore.Level
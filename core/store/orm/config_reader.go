@@ -23,11 +23,22 @@ type ConfigReader interface {
 	Dev() bool
 	MaximumServiceDuration() time.Duration
 	MinimumServiceDuration() time.Duration
+	EthDynamicFees() bool
 	EthGasBumpThreshold() uint64
 	EthGasBumpWei() *big.Int
+	EthGasLimitMargin() uint64
 	EthGasPriceDefault() *big.Int
 	SetEthGasPriceDefault(value *big.Int) error
+	EthGasPriceOracleBlocks() uint64
+	EthGasPricePercentile() uint64
+	EthLogBlockRange() uint64
+	EthMaxGasPriceWei() *big.Int
+	EthPollInterval() time.Duration
+	EthPriorityFeeWei() *big.Int
+	EthStuckTxBlocks() uint64
+	EthTxSimulation() bool
 	EthereumURL() string
+	EthereumSecondaryURLs() []string
 	JSONConsole() bool
 	LinkContractAddress() string
 	ExplorerURL() *url.URL
@@ -41,8 +52,12 @@ type ConfigReader interface {
 	MinOutgoingConfirmations() uint64
 	MinimumContractPayment() *assets.Link
 	MinimumRequestExpiration() uint64
+	MaxConcurrentRuns() uint64
+	MaxConcurrentRunsPerJob() uint64
+	MetricsPublic() bool
 	Port() uint16
 	ReaperExpiration() time.Duration
+	RunQueuePriority() RunQueuePriority
 	RootDir() string
 	SecureCookies() bool
 	SessionTimeout() time.Duration
@@ -26,6 +26,7 @@ func TestStore_ConfigDefaults(t *testing.T) {
 	assert.Equal(t, assets.NewLink(1000000000000000000), config.MinimumContractPayment())
 	assert.Equal(t, 15*time.Minute, config.SessionTimeout())
 	assert.Equal(t, new(url.URL), config.BridgeResponseURL())
+	assert.False(t, config.EthTxSimulation())
 }
 
 func TestConfig_sessionSecret(t *testing.T) {
@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres" // http://doc.gorm.io/database.html#connecting-to-a-database
 	_ "github.com/jinzhu/gorm/dialects/sqlite"   // http://doc.gorm.io/database.html#connecting-to-a-database
@@ -28,6 +27,12 @@ import (
 var (
 	// ErrorNotFound is returned when finding a single value fails.
 	ErrorNotFound = gorm.ErrRecordNotFound
+	// ErrorRunCancelled is returned when saving a run that has been cancelled
+	// since it was loaded.
+	ErrorRunCancelled = errors.New("run has been cancelled")
+	// ErrorLogConsumed is returned when creating a run for a log that has
+	// already started one.
+	ErrorLogConsumed = errors.New("log has already been consumed")
 )
 
 // DialectName is a compiler enforced type used that maps to gorm's dialect
@@ -281,16 +286,142 @@ func (orm *ORM) convenientTransaction(callback func(*gorm.DB) error) error {
 	return dbtx.Commit().Error
 }
 
-// SaveJobRun updates UpdatedAt for a JobRun and saves it
+// SaveJobRun updates UpdatedAt for a JobRun and saves it. A cancelled run is
+// never overwritten by a copy that has not been cancelled, in which case
+// ErrorRunCancelled is returned, and a run that has already finished cannot
+// be cancelled.
 func (orm *ORM) SaveJobRun(run *models.JobRun) error {
 	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		status, err := lockJobRunStatus(dbtx, run.ID)
+		if err == nil {
+			if status.Cancelled() && !run.Status.Cancelled() {
+				return ErrorRunCancelled
+			} else if run.Status.Cancelled() && status.Finished() && !status.Cancelled() {
+				return fmt.Errorf("cannot cancel run %s, it has already %s", run.ID.String(), status)
+			}
+		} else if err != ErrorNotFound {
+			return err
+		}
 		return dbtx.Unscoped().Omit("deleted_at").Save(run).Error
 	})
 }
 
-// CreateJobRun inserts a new JobRun
+// JobRunStatus returns the persisted status of a run.
+func (orm *ORM) JobRunStatus(id *models.ID) (models.RunStatus, error) {
+	var run models.JobRun
+	err := orm.DB.Unscoped().Select("status").Where("id = ?", id).First(&run).Error
+	return run.Status, err
+}
+
+// lockJobRunStatus returns the persisted status of a run, locking its row
+// until the end of the transaction.
+func lockJobRunStatus(dbtx *gorm.DB, id *models.ID) (models.RunStatus, error) {
+	if dbutil.IsPostgres(dbtx) {
+		dbtx = dbtx.Set("gorm:query_option", "FOR UPDATE")
+	}
+	var run models.JobRun
+	err := dbtx.Unscoped().Select("status").Where("id = ?", id).First(&run).Error
+	return run.Status, err
+}
+
+// CreateJobRun inserts a new JobRun. A run requested by a log is created
+// along with the log's ConsumedLog, unless the log has already been
+// consumed, in which case ErrorLogConsumed is returned.
 func (orm *ORM) CreateJobRun(run *models.JobRun) error {
-	return orm.DB.Create(run).Error
+	cl := run.RunRequest.ConsumedLog
+	if cl == nil {
+		return orm.DB.Create(run).Error
+	}
+
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		consumed, err := logConsumed(dbtx, cl)
+		if err != nil {
+			return err
+		} else if consumed {
+			return ErrorLogConsumed
+		}
+		if err := dbtx.Create(cl).Error; err != nil {
+			return err
+		}
+		return dbtx.Create(run).Error
+	})
+}
+
+// CreateQueuedRun adds the run to the JobRunner's work queue, unless it is
+// already queued.
+func (orm *ORM) CreateQueuedRun(qr *models.QueuedRun) error {
+	return orm.DB.Where(models.QueuedRun{JobRunID: qr.JobRunID}).FirstOrCreate(qr).Error
+}
+
+// DeleteQueuedRun removes the run from the JobRunner's work queue.
+func (orm *ORM) DeleteQueuedRun(runID *models.ID) error {
+	return orm.DB.Where("job_run_id = ?", runID.String()).Delete(models.QueuedRun{}).Error
+}
+
+// AllQueuedRuns returns every run in the JobRunner's work queue.
+func (orm *ORM) AllQueuedRuns() ([]models.QueuedRun, error) {
+	var qrs []models.QueuedRun
+	return qrs, orm.DB.Order("created_at asc").Find(&qrs).Error
+}
+
+// QueuedRuns returns a page of the JobRunner's work queue, oldest first.
+func (orm *ORM) QueuedRuns(offset, limit int) ([]models.QueuedRun, int, error) {
+	count, err := orm.countOf(&models.QueuedRun{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var qrs []models.QueuedRun
+	err = orm.getRecords(&qrs, "created_at asc", offset, limit)
+	return qrs, count, err
+}
+
+// FindLogCursor returns the block the initiator resumes reading logs from.
+func (orm *ORM) FindLogCursor(initiatorID uint) (models.LogCursor, error) {
+	var cursor models.LogCursor
+	return cursor, orm.DB.First(&cursor, "initiator_id = ?", initiatorID).Error
+}
+
+// AdvanceLogCursor moves the initiator's log cursor forward to the block,
+// creating it if need be. A cursor is never moved back.
+func (orm *ORM) AdvanceLogCursor(initiatorID uint, blockNumber uint64) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		var cursor models.LogCursor
+		err := dbtx.First(&cursor, "initiator_id = ?", initiatorID).Error
+		if err == gorm.ErrRecordNotFound {
+			return dbtx.Create(&models.LogCursor{InitiatorID: initiatorID, BlockNumber: blockNumber}).Error
+		} else if err != nil {
+			return err
+		}
+
+		if cursor.BlockNumber >= blockNumber {
+			return nil
+		}
+		cursor.BlockNumber = blockNumber
+		return dbtx.Save(&cursor).Error
+	})
+}
+
+// LogConsumed returns true if the initiator has already started a run for
+// the log.
+func (orm *ORM) LogConsumed(cl models.ConsumedLog) (bool, error) {
+	return logConsumed(orm.DB, &cl)
+}
+
+func logConsumed(db *gorm.DB, cl *models.ConsumedLog) (bool, error) {
+	var count int
+	err := db.Model(&models.ConsumedLog{}).
+		Where("initiator_id = ? AND tx_hash = ? AND log_index = ?", cl.InitiatorID, cl.TxHash, cl.LogIndex).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteConsumedLogsBefore removes the initiator's consumed logs in blocks
+// before blockNumber, which it is never delivered again.
+func (orm *ORM) DeleteConsumedLogsBefore(initiatorID uint, blockNumber uint64) error {
+	return orm.DB.
+		Where("initiator_id = ? AND block_number < ?", initiatorID, blockNumber).
+		Delete(&models.ConsumedLog{}).Error
 }
 
 // LinkEarnedFor shows the total link earnings for a job
@@ -313,6 +444,38 @@ func (orm *ORM) LinkEarnedFor(spec *models.JobSpec) (*assets.Link, error) {
 	return earned, nil
 }
 
+// LinkEarnedByJob shows the total link earnings for every job that has
+// finished runs, keyed by job spec ID.
+func (orm *ORM) LinkEarnedByJob() (map[string]*assets.Link, error) {
+	query := orm.DB.Table("job_runs").
+		Joins("JOIN job_specs ON job_runs.job_spec_id = job_specs.id").
+		Where("job_runs.finished_at IS NOT NULL").
+		Group("job_specs.id")
+
+	if dbutil.IsPostgres(orm.DB) {
+		query = query.Select("job_specs.id, SUM(payment)")
+	} else {
+		query = query.Select("job_specs.id, CAST(SUM(CAST(SUBSTR(payment, 1, 10) as BIGINT)) as varchar(255))")
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "error obtaining link earned from job_runs")
+	}
+	defer rows.Close()
+
+	earned := map[string]*assets.Link{}
+	for rows.Next() {
+		var id models.ID
+		var link *assets.Link
+		if err := rows.Scan(&id, &link); err != nil {
+			return nil, errors.Wrap(err, "error obtaining link earned from job_runs")
+		}
+		earned[id.String()] = link
+	}
+	return earned, rows.Err()
+}
+
 // CreateExternalInitiator inserts a new external initiator
 func (orm *ORM) CreateExternalInitiator(externalInitiator *models.ExternalInitiator) error {
 	return orm.DB.Create(externalInitiator).Error
@@ -389,6 +552,20 @@ func (orm *ORM) JobRunsFor(jobSpecID *models.ID, limit ...int) ([]models.JobRun,
 	return runs, err
 }
 
+// UnfinishedJobRunsForRequest returns the runs of the job initiated by the
+// oracle request with the given ID that have yet to finish.
+func (orm *ORM) UnfinishedJobRunsForRequest(jobSpecID *models.ID, requestID string) ([]models.JobRun, error) {
+	runs := []models.JobRun{}
+	finished := []models.RunStatus{models.RunStatusCompleted, models.RunStatusErrored, models.RunStatusCancelled}
+	err := orm.preloadJobRuns().
+		Joins("JOIN run_requests ON run_requests.id = job_runs.run_request_id").
+		Where("job_runs.job_spec_id = ? AND run_requests.request_id = ?", jobSpecID, requestID).
+		Where("job_runs.status NOT IN (?)", finished).
+		Order("job_runs.created_at asc").
+		Find(&runs).Error
+	return runs, err
+}
+
 // JobRunsCountFor returns the current number of runs for the job
 func (orm *ORM) JobRunsCountFor(jobSpecID *models.ID) (int, error) {
 	var count int
@@ -463,8 +640,15 @@ func (orm *ORM) ArchiveJob(ID *models.ID) error {
 		return err
 	}
 
+	initiatorIDs := []uint{}
+	for _, initr := range j.Initiators {
+		initiatorIDs = append(initiatorIDs, initr.ID)
+	}
+
 	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
 		return multierr.Combine(
+			dbtx.Where("initiator_id IN (?)", initiatorIDs).Delete(&models.ConsumedLog{}).Error,
+			dbtx.Where("initiator_id IN (?)", initiatorIDs).Delete(&models.LogCursor{}).Error,
 			dbtx.Where("job_spec_id = ?", ID).Delete(&models.Initiator{}).Error,
 			dbtx.Where("job_spec_id = ?", ID).Delete(&models.TaskSpec{}).Error,
 			dbtx.Where("job_spec_id = ?", ID).Delete(&models.JobRun{}).Error,
@@ -526,7 +710,7 @@ func (orm *ORM) AnyJobWithType(taskTypeName string) (bool, error) {
 // creates it and its attempts
 func (orm *ORM) CreateTx(
 	surrogateID null.String,
-	ethTx *types.Transaction,
+	ethTx models.SignedTx,
 	from *common.Address,
 	sentAt uint64,
 ) (*models.Tx, error) {
@@ -555,16 +739,19 @@ func (orm *ORM) CreateTx(
 		tx.Value = models.NewBig(ethTx.Value())
 		tx.GasLimit = ethTx.Gas()
 		tx.GasPrice = models.NewBig(ethTx.GasPrice())
+		tx.MaxFeePerGas, tx.MaxPriorityFeePerGas = models.DynamicFeesOf(ethTx)
 		tx.Hash = ethTx.Hash()
 		tx.SentAt = sentAt
 		tx.SignedRawTx = signedRawTx
 		if err == gorm.ErrRecordNotFound {
 			attempt := models.TxAttempt{
-				TxID:        tx.ID,
-				Hash:        tx.Hash,
-				GasPrice:    tx.GasPrice,
-				SentAt:      tx.SentAt,
-				SignedRawTx: tx.SignedRawTx,
+				TxID:                 tx.ID,
+				Hash:                 tx.Hash,
+				GasPrice:             tx.GasPrice,
+				MaxFeePerGas:         tx.MaxFeePerGas,
+				MaxPriorityFeePerGas: tx.MaxPriorityFeePerGas,
+				SentAt:               tx.SentAt,
+				SignedRawTx:          tx.SignedRawTx,
 			}
 			tx.Attempts = []*models.TxAttempt{&attempt}
 			return dbtx.Create(tx).Error
@@ -582,7 +769,7 @@ func (orm *ORM) CreateTx(
 // failed Eth transaction attempt
 func (orm *ORM) UpdateTx(
 	tx *models.Tx,
-	ethTx *types.Transaction,
+	ethTx models.SignedTx,
 	from *common.Address,
 	sentAt uint64,
 ) error {
@@ -594,12 +781,15 @@ func (orm *ORM) UpdateTx(
 	tx.From = *from
 	tx.Nonce = ethTx.Nonce()
 	tx.GasPrice = models.NewBig(ethTx.GasPrice())
+	tx.MaxFeePerGas, tx.MaxPriorityFeePerGas = models.DynamicFeesOf(ethTx)
 	tx.Hash = ethTx.Hash()
 	tx.SentAt = sentAt
 	tx.SignedRawTx = signedRawTx
 	txAttempt := tx.Attempts[0]
 	txAttempt.Hash = tx.Hash
 	txAttempt.GasPrice = tx.GasPrice
+	txAttempt.MaxFeePerGas = tx.MaxFeePerGas
+	txAttempt.MaxPriorityFeePerGas = tx.MaxPriorityFeePerGas
 	txAttempt.SentAt = tx.SentAt
 	txAttempt.SignedRawTx = tx.SignedRawTx
 
@@ -614,6 +804,8 @@ func (orm *ORM) MarkTxSafe(tx *models.Tx, txAttempt *models.TxAttempt) error {
 	txAttempt.Confirmed = true
 	tx.Hash = txAttempt.Hash
 	tx.GasPrice = txAttempt.GasPrice
+	tx.MaxFeePerGas = txAttempt.MaxFeePerGas
+	tx.MaxPriorityFeePerGas = txAttempt.MaxPriorityFeePerGas
 	tx.Confirmed = txAttempt.Confirmed
 	tx.SentAt = txAttempt.SentAt
 	tx.SignedRawTx = txAttempt.SignedRawTx
@@ -660,7 +852,7 @@ func (orm *ORM) FindTxAttempt(hash common.Hash) (*models.TxAttempt, error) {
 // in the database.
 func (orm *ORM) AddTxAttempt(
 	tx *models.Tx,
-	etx *types.Transaction,
+	etx models.SignedTx,
 	blkNum uint64,
 ) (*models.TxAttempt, error) {
 	signedRawTx, err := utils.EncodeTxToHex(etx)
@@ -675,8 +867,11 @@ func (orm *ORM) AddTxAttempt(
 		SentAt:      blkNum,
 		SignedRawTx: signedRawTx,
 	}
+	txAttempt.MaxFeePerGas, txAttempt.MaxPriorityFeePerGas = models.DynamicFeesOf(etx)
 	tx.Hash = txAttempt.Hash
 	tx.GasPrice = txAttempt.GasPrice
+	tx.MaxFeePerGas = txAttempt.MaxFeePerGas
+	tx.MaxPriorityFeePerGas = txAttempt.MaxPriorityFeePerGas
 	tx.Confirmed = txAttempt.Confirmed
 	tx.SentAt = txAttempt.SentAt
 	tx.SignedRawTx = txAttempt.SignedRawTx
@@ -688,6 +883,27 @@ func (orm *ORM) AddTxAttempt(
 	return txAttempt, errors.Wrap(err, "AddTxAttempt#Save(tx) failed")
 }
 
+// SafeTxsSince returns the transactions marked as safe whose last attempt
+// was sent at or after the passed block height.
+func (orm *ORM) SafeTxsSince(blockHeight uint64) ([]models.Tx, error) {
+	var txs []models.Tx
+	err := preloadAttempts(orm.DB).
+		Where("confirmed = ? AND sent_at >= ?", true, blockHeight).
+		Order("id asc").
+		Find(&txs).Error
+	return txs, err
+}
+
+// MarkTxUnsafe reverts a transaction and its attempts to unconfirmed, so
+// that the transaction is tracked and rebroadcast again.
+func (orm *ORM) MarkTxUnsafe(tx *models.Tx) error {
+	tx.Confirmed = false
+	for _, attempt := range tx.Attempts {
+		attempt.Confirmed = false
+	}
+	return orm.DB.Save(tx).Error
+}
+
 // GetLastNonce retrieves the last known nonce in the database for an account
 func (orm *ORM) GetLastNonce(address common.Address) (uint64, error) {
 	var transaction models.Tx
@@ -715,6 +931,11 @@ func (orm *ORM) MarkRan(i *models.Initiator, ran bool) error {
 	})
 }
 
+// MarkFired records when a cron initiator last fired.
+func (orm *ORM) MarkFired(i *models.Initiator, at time.Time) error {
+	return orm.DB.Model(i).UpdateColumn("last_fired_at", null.TimeFrom(at)).Error
+}
+
 // FindUser will return the one API user, or an error.
 func (orm *ORM) FindUser() (models.User, error) {
 	user := models.User{}
@@ -880,6 +1101,17 @@ func (orm *ORM) TxAttempts(offset, limit int) ([]models.TxAttempt, int, error) {
 	return attempts, count, err
 }
 
+// UnconfirmedTxs returns the transactions that are not yet confirmed, in
+// nonce order.
+func (orm *ORM) UnconfirmedTxs() ([]models.Tx, error) {
+	var txs []models.Tx
+	err := preloadAttempts(orm.DB).
+		Where("confirmed = ?", false).
+		Order("nonce asc, id asc").
+		Find(&txs).Error
+	return txs, err
+}
+
 // UnconfirmedTxAttempts returns all TxAttempts for which the associated Tx is still unconfirmed.
 func (orm *ORM) UnconfirmedTxAttempts() ([]models.TxAttempt, error) {
 	var items []models.TxAttempt
@@ -997,6 +1229,25 @@ func (orm *ORM) LastHead() (*models.Head, error) {
 	return number, err
 }
 
+// LastHeads returns up to limit of the most recently persisted heads, in
+// ascending order.
+func (orm *ORM) LastHeads(limit int) ([]models.Head, error) {
+	var heads []models.Head
+	err := orm.DB.Order("number desc").Limit(limit).Find(&heads).Error
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(heads)-1; i < j; i, j = i+1, j-1 {
+		heads[i], heads[j] = heads[j], heads[i]
+	}
+	return heads, nil
+}
+
+// DeleteHeadsAfter deletes all persisted heads above the passed block number.
+func (orm *ORM) DeleteHeadsAfter(number int64) error {
+	return orm.DB.Where("number > ?", number).Delete(models.Head{}).Error
+}
+
 // DeleteStaleSessions deletes all sessions before the passed time.
 func (orm *ORM) DeleteStaleSessions(before time.Time) error {
 	return orm.DB.Where("last_used < ?", before).Delete(models.Session{}).Error
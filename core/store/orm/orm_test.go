@@ -2,6 +2,7 @@ package orm_test
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"io"
 	"math/big"
 	"os"
@@ -64,6 +65,29 @@ func TestORM_CreateJob(t *testing.T) {
 	assert.Equal(t, j2.ID, j2.Initiators[0].JobSpecID)
 }
 
+func TestORM_CreateJob_EventABI(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	var event models.EventABI
+	require.NoError(t, json.Unmarshal([]byte(`"Transfer(address indexed from, address indexed to, uint256 value)"`), &event))
+	j1 := cltest.NewJobWithLogInitiator()
+	j1.Initiators[0].EventABI = &event
+	require.NoError(t, store.CreateJob(&j1))
+
+	j2, err := store.FindJob(j1.ID)
+	require.NoError(t, err)
+	require.NotNil(t, j2.Initiators[0].EventABI)
+	assert.Equal(t, event.ID(), j2.Initiators[0].EventABI.ID())
+
+	j3 := cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&j3))
+	j4, err := store.FindJob(j3.ID)
+	require.NoError(t, err)
+	assert.Nil(t, j4.Initiators[0].EventABI)
+}
+
 func TestORM_Unscoped(t *testing.T) {
 	t.Parallel()
 	store, cleanup := cltest.NewStore(t)
@@ -236,6 +260,39 @@ func TestORM_LinkEarnedFor(t *testing.T) {
 	assert.Equal(t, assets.NewLink(10), totalEarned)
 }
 
+func TestORM_LinkEarnedByJob(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job1 := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job1))
+	job2 := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job2))
+
+	for _, payment := range []int64{2, 3} {
+		jr := job1.NewRun(job1.Initiators[0])
+		jr.Payment = assets.NewLink(payment)
+		jr.FinishedAt = null.TimeFrom(time.Now())
+		require.NoError(t, store.CreateJobRun(&jr))
+	}
+	jr := job2.NewRun(job2.Initiators[0])
+	jr.Payment = assets.NewLink(7)
+	jr.FinishedAt = null.TimeFrom(time.Now())
+	require.NoError(t, store.CreateJobRun(&jr))
+	unfinished := job2.NewRun(job2.Initiators[0])
+	unfinished.Payment = assets.NewLink(5)
+	require.NoError(t, store.CreateJobRun(&unfinished))
+
+	earned, err := store.LinkEarnedByJob()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*assets.Link{
+		job1.ID.String(): assets.NewLink(5),
+		job2.ID.String(): assets.NewLink(7),
+	}, earned)
+}
+
 func TestORM_JobRunsSortedFor(t *testing.T) {
 	t.Parallel()
 
@@ -814,6 +871,24 @@ func TestORM_MarkRan(t *testing.T) {
 	assert.Error(t, store.MarkRan(&initr, true))
 }
 
+func TestORM_MarkFired(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	js := cltest.NewJobWithSchedule("* * * * *")
+	require.NoError(t, store.CreateJob(&js))
+	initr := js.Initiators[0]
+
+	firedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, store.MarkFired(&initr, firedAt))
+	ir, err := store.FindInitiator(initr.ID)
+	require.NoError(t, err)
+	require.True(t, ir.LastFiredAt.Valid)
+	assert.True(t, firedAt.Equal(ir.LastFiredAt.Time))
+}
+
 func TestORM_FindUser(t *testing.T) {
 	t.Parallel()
 
@@ -1396,3 +1471,128 @@ func TestORM_UnconfirmedTxAttempts(t *testing.T) {
 
 	assert.Len(t, attempts, 7)
 }
+
+func TestORM_AdvanceLogCursor(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job1, job2 := cltest.NewJobWithLogInitiator(), cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job1))
+	require.NoError(t, store.CreateJob(&job2))
+	initr1, initr2 := job1.Initiators[0].ID, job2.Initiators[0].ID
+
+	_, err := store.FindLogCursor(initr1)
+	assert.Equal(t, orm.ErrorNotFound, err)
+
+	require.NoError(t, store.AdvanceLogCursor(initr1, 10))
+	require.NoError(t, store.AdvanceLogCursor(initr2, 20))
+	require.NoError(t, store.AdvanceLogCursor(initr1, 15))
+	require.NoError(t, store.AdvanceLogCursor(initr1, 12))
+
+	cursor, err := store.FindLogCursor(initr1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(15), cursor.BlockNumber)
+	cursor, err = store.FindLogCursor(initr2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), cursor.BlockNumber)
+}
+
+func TestORM_CreateJobRun_ConsumesLog(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job1, job2 := cltest.NewJobWithLogInitiator(), cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job1))
+	require.NoError(t, store.CreateJob(&job2))
+
+	log := models.Log{TxHash: cltest.NewHash(), Index: 3, BlockNumber: 7}
+
+	tests := []struct {
+		name string
+		job  models.JobSpec
+		log  models.Log
+		want error
+	}{
+		{"first time", job1, log, nil},
+		{"again", job1, log, orm.ErrorLogConsumed},
+		{"another initiator", job2, log, nil},
+		{"another log in the transaction", job1, models.Log{TxHash: log.TxHash, Index: 4}, nil},
+	}
+
+	for _, tt := range tests {
+		initr := tt.job.Initiators[0]
+		cl := models.NewConsumedLog(initr.ID, tt.log)
+		run := tt.job.NewRun(initr)
+		run.RunRequest.ConsumedLog = &cl
+
+		assert.Equal(t, tt.want, store.CreateJobRun(&run), tt.name)
+		consumed, err := store.LogConsumed(cl)
+		require.NoError(t, err, tt.name)
+		assert.True(t, consumed, tt.name)
+
+		_, err = store.FindJobRun(run.ID)
+		if tt.want == nil {
+			assert.NoError(t, err, tt.name)
+		} else {
+			assert.Equal(t, orm.ErrorNotFound, err, tt.name)
+		}
+	}
+}
+
+func TestORM_DeleteConsumedLogsBefore(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job1, job2 := cltest.NewJobWithLogInitiator(), cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job1))
+	require.NoError(t, store.CreateJob(&job2))
+	initr1, initr2 := job1.Initiators[0].ID, job2.Initiators[0].ID
+
+	consume := func(initiatorID uint, blockNumber uint64) models.ConsumedLog {
+		cl := models.NewConsumedLog(initiatorID, models.Log{TxHash: cltest.NewHash(), BlockNumber: blockNumber})
+		require.NoError(t, store.ORM.DB.Create(&cl).Error)
+		return cl
+	}
+	old, kept, other := consume(initr1, 10), consume(initr1, 20), consume(initr2, 10)
+
+	require.NoError(t, store.DeleteConsumedLogsBefore(initr1, 20))
+
+	for _, tt := range []struct {
+		name string
+		cl   models.ConsumedLog
+		want bool
+	}{
+		{"before block", old, false},
+		{"at block", kept, true},
+		{"another initiator", other, true},
+	} {
+		consumed, err := store.LogConsumed(tt.cl)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, consumed, tt.name)
+	}
+}
+
+func TestORM_ArchiveJob_DeletesLogCursor(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	initr := job.Initiators[0]
+
+	cl := models.NewConsumedLog(initr.ID, models.Log{TxHash: cltest.NewHash(), BlockNumber: 10})
+	require.NoError(t, store.ORM.DB.Create(&cl).Error)
+	require.NoError(t, store.AdvanceLogCursor(initr.ID, 10))
+
+	require.NoError(t, store.ArchiveJob(job.ID))
+
+	consumed, err := store.LogConsumed(cl)
+	require.NoError(t, err)
+	assert.False(t, consumed)
+	_, err = store.FindLogCursor(initr.ID)
+	assert.Equal(t, orm.ErrorNotFound, err)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/utils"
 	"github.com/tidwall/gjson"
 	"go.uber.org/multierr"
+	null "gopkg.in/guregu/null.v3"
 )
 
 type requestType int
@@ -130,9 +131,20 @@ type Whitelist struct {
 	DatabaseTimeout          time.Duration   `json:"databaseTimeout"`
 	Dev                      bool            `json:"chainlinkDev"`
 	EthereumURL              string          `json:"ethUrl"`
+	EthereumSecondaryURLs    []string        `json:"ethSecondaryUrls"`
+	EthDynamicFees           bool            `json:"ethDynamicFees"`
 	EthGasBumpThreshold      uint64          `json:"ethGasBumpThreshold"`
 	EthGasBumpWei            *big.Int        `json:"ethGasBumpWei"`
+	EthGasLimitMargin        uint64          `json:"ethGasLimitMargin"`
 	EthGasPriceDefault       *big.Int        `json:"ethGasPriceDefault"`
+	EthGasPriceOracleBlocks  uint64          `json:"ethGasPriceOracleBlocks"`
+	EthGasPricePercentile    uint64          `json:"ethGasPricePercentile"`
+	EthLogBlockRange         uint64          `json:"ethLogBlockRange"`
+	EthMaxGasPriceWei        *big.Int        `json:"ethMaxGasPriceWei"`
+	EthPollInterval          time.Duration   `json:"ethPollInterval"`
+	EthPriorityFeeWei        *big.Int        `json:"ethPriorityFeeWei"`
+	EthStuckTxBlocks         uint64          `json:"ethStuckTxBlocks"`
+	EthTxSimulation          bool            `json:"ethTxSimulation"`
 	ExplorerURL              string          `json:"explorerUrl"`
 	JSONConsole              bool            `json:"jsonConsole"`
 	LinkContractAddress      string          `json:"linkContractAddress"`
@@ -141,6 +153,9 @@ type Whitelist struct {
 	LogSQLStatements         bool            `json:"logSqlStatements"`
 	LogToDisk                bool            `json:"logToDisk"`
 	MaxRPCCallsPerSecond     uint64          `json:"maxRPCCallsPerSecond"`
+	MaxConcurrentRuns        uint64          `json:"maxConcurrentRuns"`
+	MaxConcurrentRunsPerJob  uint64          `json:"maxConcurrentRunsPerJob"`
+	MetricsPublic            bool            `json:"metricsPublic"`
 	MinimumContractPayment   *assets.Link    `json:"minimumContractPayment"`
 	MinimumRequestExpiration uint64          `json:"minimumRequestExpiration"`
 	MinIncomingConfirmations uint32          `json:"minIncomingConfirmations"`
@@ -148,6 +163,7 @@ type Whitelist struct {
 	OracleContractAddress    *common.Address `json:"oracleContractAddress"`
 	Port                     uint16          `json:"chainlinkPort"`
 	ReaperExpiration         time.Duration   `json:"reaperExpiration"`
+	RunQueuePriority         string          `json:"runQueuePriority"`
 	RootDir                  string          `json:"root"`
 	SessionTimeout           time.Duration   `json:"sessionTimeout"`
 	TLSHost                  string          `json:"chainlinkTLSHost"`
@@ -178,9 +194,20 @@ func NewConfigWhitelist(store *store.Store) (ConfigWhitelist, error) {
 			Dev:                      config.Dev(),
 			DatabaseTimeout:          config.DatabaseTimeout(),
 			EthereumURL:              config.EthereumURL(),
+			EthereumSecondaryURLs:    config.EthereumSecondaryURLs(),
+			EthDynamicFees:           config.EthDynamicFees(),
 			EthGasBumpThreshold:      config.EthGasBumpThreshold(),
 			EthGasBumpWei:            config.EthGasBumpWei(),
+			EthGasLimitMargin:        config.EthGasLimitMargin(),
 			EthGasPriceDefault:       config.EthGasPriceDefault(),
+			EthGasPriceOracleBlocks:  config.EthGasPriceOracleBlocks(),
+			EthGasPricePercentile:    config.EthGasPricePercentile(),
+			EthLogBlockRange:         config.EthLogBlockRange(),
+			EthMaxGasPriceWei:        config.EthMaxGasPriceWei(),
+			EthPollInterval:          config.EthPollInterval(),
+			EthPriorityFeeWei:        config.EthPriorityFeeWei(),
+			EthStuckTxBlocks:         config.EthStuckTxBlocks(),
+			EthTxSimulation:          config.EthTxSimulation(),
 			JSONConsole:              config.JSONConsole(),
 			LinkContractAddress:      config.LinkContractAddress(),
 			ExplorerURL:              explorerURL,
@@ -189,6 +216,9 @@ func NewConfigWhitelist(store *store.Store) (ConfigWhitelist, error) {
 			LogSQLStatements:         config.LogSQLStatements(),
 			LogSQLMigrations:         config.LogSQLMigrations(),
 			MaxRPCCallsPerSecond:     config.MaxRPCCallsPerSecond(),
+			MaxConcurrentRuns:        config.MaxConcurrentRuns(),
+			MaxConcurrentRunsPerJob:  config.MaxConcurrentRunsPerJob(),
+			MetricsPublic:            config.MetricsPublic(),
 			MinimumContractPayment:   config.MinimumContractPayment(),
 			MinimumRequestExpiration: config.MinimumRequestExpiration(),
 			MinIncomingConfirmations: config.MinIncomingConfirmations(),
@@ -196,6 +226,7 @@ func NewConfigWhitelist(store *store.Store) (ConfigWhitelist, error) {
 			OracleContractAddress:    config.OracleContractAddress(),
 			Port:                     config.Port(),
 			ReaperExpiration:         config.ReaperExpiration(),
+			RunQueuePriority:         string(config.RunQueuePriority()),
 			RootDir:                  config.RootDir(),
 			SessionTimeout:           config.SessionTimeout(),
 			TLSHost:                  config.TLSHost(),
@@ -357,15 +388,22 @@ func initiatorParams(i Initiator) (interface{}, error) {
 		return struct{}{}, nil
 	case models.InitiatorCron:
 		return struct {
-			Schedule models.Cron `json:"schedule"`
-		}{i.Schedule}, nil
+			Schedule    models.Cron          `json:"schedule"`
+			TimeZone    string               `json:"timeZone,omitempty"`
+			Jitter      models.Duration      `json:"jitter,omitempty"`
+			CatchUp     models.CatchUpPolicy `json:"catchUp,omitempty"`
+			LastFiredAt null.Time            `json:"lastFiredAt"`
+		}{i.Schedule, i.TimeZone, i.Jitter, i.CatchUp, i.LastFiredAt}, nil
 	case models.InitiatorRunAt:
 		return struct {
 			Time models.AnyTime `json:"time"`
 			Ran  bool           `json:"ran"`
 		}{models.NewAnyTime(i.Time.Time), i.Ran}, nil
 	case models.InitiatorEthLog:
-		fallthrough
+		return struct {
+			Address  common.Address   `json:"address"`
+			EventABI *models.EventABI `json:"eventABI,omitempty"`
+		}{i.Address, i.EventABI}, nil
 	case models.InitiatorRunLog:
 		return struct {
 			Address common.Address `json:"address"`
@@ -374,6 +412,25 @@ func initiatorParams(i Initiator) (interface{}, error) {
 		return struct {
 			Name string `json:"name"`
 		}{i.Name}, nil
+	case models.InitiatorFluxMonitor:
+		return struct {
+			Address         common.Address  `json:"address"`
+			Feeds           *models.JSON    `json:"feeds"`
+			Threshold       float64         `json:"threshold"`
+			Precision       int32           `json:"precision"`
+			PollingInterval models.Duration `json:"pollingInterval"`
+			Heartbeat       models.Duration `json:"heartbeat"`
+		}{i.Address, i.Feeds, i.Threshold, i.Precision, i.PollingInterval, i.Heartbeat}, nil
+	case models.InitiatorBlocks:
+		return struct {
+			BlockInterval uint64 `json:"blockInterval"`
+		}{i.BlockInterval}, nil
+	case models.InitiatorTxConfirmations:
+		return struct {
+			TxHash        common.Hash `json:"txHash"`
+			Confirmations uint64      `json:"confirmations"`
+			Ran           bool        `json:"ran"`
+		}{i.TxHash, i.Confirmations, i.Ran}, nil
 	default:
 		return nil, fmt.Errorf("Cannot marshal unsupported initiator type '%v'", i.Type)
 	}
@@ -529,22 +586,25 @@ func (a NewAccount) GetName() string {
 
 // Tx is a jsonapi wrapper for an Ethereum Transaction.
 type Tx struct {
-	Confirmed bool            `json:"confirmed,omitempty"`
-	Data      hexutil.Bytes   `json:"data,omitempty"`
-	From      *common.Address `json:"from,omitempty"`
-	GasLimit  string          `json:"gasLimit,omitempty"`
-	GasPrice  string          `json:"gasPrice,omitempty"`
-	Hash      common.Hash     `json:"hash,omitempty"`
-	Hex       string          `json:"rawHex,omitempty"`
-	Nonce     string          `json:"nonce,omitempty"`
-	SentAt    string          `json:"sentAt,omitempty"`
-	To        *common.Address `json:"to,omitempty"`
-	Value     string          `json:"value,omitempty"`
+	Confirmed            bool            `json:"confirmed,omitempty"`
+	Data                 hexutil.Bytes   `json:"data,omitempty"`
+	From                 *common.Address `json:"from,omitempty"`
+	GasLimit             string          `json:"gasLimit,omitempty"`
+	GasPrice             string          `json:"gasPrice,omitempty"`
+	MaxFeePerGas         string          `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string          `json:"maxPriorityFeePerGas,omitempty"`
+	Cancelled            bool            `json:"cancelled,omitempty"`
+	Hash                 common.Hash     `json:"hash,omitempty"`
+	Hex                  string          `json:"rawHex,omitempty"`
+	Nonce                string          `json:"nonce,omitempty"`
+	SentAt               string          `json:"sentAt,omitempty"`
+	To                   *common.Address `json:"to,omitempty"`
+	Value                string          `json:"value,omitempty"`
 }
 
 // NewTx builds a transaction presenter.
 func NewTx(tx *models.Tx) Tx {
-	return Tx{
+	presented := Tx{
 		Confirmed: tx.Confirmed,
 		Data:      hexutil.Bytes(tx.Data),
 		From:      &tx.From,
@@ -556,7 +616,13 @@ func NewTx(tx *models.Tx) Tx {
 		SentAt:    strconv.FormatUint(tx.SentAt, 10),
 		To:        &tx.To,
 		Value:     tx.Value.String(),
+		Cancelled: tx.Cancelled,
+	}
+	if tx.MaxFeePerGas != nil && tx.MaxPriorityFeePerGas != nil {
+		presented.MaxFeePerGas = tx.MaxFeePerGas.String()
+		presented.MaxPriorityFeePerGas = tx.MaxPriorityFeePerGas.String()
 	}
+	return presented
 }
 
 // NewTxFromAttempt builds a transaction presenter from a TxAttempt
@@ -575,6 +641,8 @@ func NewTxFromAttempt(txAttempt models.TxAttempt) Tx {
 	tx := txAttempt.Tx
 	tx.Hash = txAttempt.Hash
 	tx.GasPrice = txAttempt.GasPrice
+	tx.MaxFeePerGas = txAttempt.MaxFeePerGas
+	tx.MaxPriorityFeePerGas = txAttempt.MaxPriorityFeePerGas
 	tx.Confirmed = txAttempt.Confirmed
 	tx.SentAt = txAttempt.SentAt
 	tx.SignedRawTx = txAttempt.SignedRawTx
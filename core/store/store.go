@@ -32,6 +32,7 @@ type Store struct {
 	RunChannel  RunChannel
 	TxManager   TxManager
 	StatsPusher *synchronization.StatsPusher
+	ethrpc      CallerSubscriber
 }
 
 type lazyRPCWrapper struct {
@@ -47,8 +48,8 @@ func newLazyRPCWrapper(urlString string, limiter *rate.Limiter) (CallerSubscribe
 	if err != nil {
 		return nil, err
 	}
-	if parsed.Scheme != "ws" && parsed.Scheme != "wss" {
-		return nil, fmt.Errorf("Ethereum url scheme must be websocket: %s", parsed.String())
+	if !isWebsocketURL(urlString) && !isHTTPURL(urlString) {
+		return nil, fmt.Errorf("Ethereum url scheme must be websocket or http: %s", parsed.String())
 	}
 	return &lazyRPCWrapper{
 		url:         parsed,
@@ -140,7 +141,7 @@ func NewStoreWithDialer(config *orm.Config, dialer Dialer) *Store {
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Unable to initialize ORM: %+v", err))
 	}
-	ethrpc, err := dialer.Dial(config.EthereumURL())
+	ethrpc, err := dialEthereum(config, dialer)
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Unable to dial ETH RPC port: %+v", err))
 	}
@@ -157,16 +158,53 @@ func NewStoreWithDialer(config *orm.Config, dialer Dialer) *Store {
 		RunChannel:  NewQueuedRunChannel(),
 		TxManager:   NewEthTxManager(&EthCallerSubscriber{ethrpc}, config, keyStore, orm),
 		StatsPusher: synchronization.NewStatsPusher(orm, config.ExplorerURL(), config.ExplorerAccessKey(), config.ExplorerSecret()),
+		ethrpc:      ethrpc,
 	}
 	return store
 }
 
+// dialEthereum dials the primary Ethereum node and, when fallback nodes are
+// configured, each of those, returning a CallerSubscriber that fails over
+// between them.
+func dialEthereum(config *orm.Config, dialer Dialer) (CallerSubscriber, error) {
+	primary, err := dialEthereumNode(config, dialer, config.EthereumURL())
+	if err != nil {
+		return nil, err
+	}
+
+	secondaryURLs := config.EthereumSecondaryURLs()
+	if len(secondaryURLs) == 0 {
+		return primary, nil
+	}
+
+	endpoints := []*ethEndpoint{newEthEndpoint(config.EthereumURL(), primary)}
+	for _, u := range secondaryURLs {
+		secondary, err := dialEthereumNode(config, dialer, u)
+		if err != nil {
+			return nil, errors.Wrapf(err, "dialing secondary %s", u)
+		}
+		endpoints = append(endpoints, newEthEndpoint(u, secondary))
+	}
+	return newFailoverCallerSubscriber(endpoints), nil
+}
+
+// dialEthereumNode dials a single Ethereum node, polling it for heads and
+// logs if it is served over HTTP.
+func dialEthereumNode(config *orm.Config, dialer Dialer, urlString string) (CallerSubscriber, error) {
+	caller, err := dialer.Dial(urlString)
+	if err != nil || !isHTTPURL(urlString) {
+		return caller, err
+	}
+	return newPollingCallerSubscriber(caller, config.EthPollInterval(), config.EthLogBlockRange()), nil
+}
+
 // Start initiates all of Store's dependencies including the TxManager.
 func (s *Store) Start() error {
 	s.TxManager.Register(s.KeyStore.Accounts())
 	return multierr.Combine(
 		s.SyncDiskKeyStoreToDB(),
 		s.StatsPusher.Start(),
+		s.startEthEndpoints(),
 	)
 }
 
@@ -176,9 +214,33 @@ func (s *Store) Close() error {
 	return multierr.Combine(
 		s.ORM.Close(),
 		s.StatsPusher.Close(),
+		s.closeEthEndpoints(),
 	)
 }
 
+// EthEndpointsHealth returns the health of each configured Ethereum
+// endpoint, or nil when only a single endpoint is configured.
+func (s *Store) EthEndpointsHealth() []EthEndpointHealth {
+	if f, ok := s.ethrpc.(*failoverCallerSubscriber); ok {
+		return f.Health()
+	}
+	return nil
+}
+
+func (s *Store) startEthEndpoints() error {
+	if f, ok := s.ethrpc.(*failoverCallerSubscriber); ok {
+		return f.Start()
+	}
+	return nil
+}
+
+func (s *Store) closeEthEndpoints() error {
+	if f, ok := s.ethrpc.(*failoverCallerSubscriber); ok {
+		return f.Close()
+	}
+	return nil
+}
+
 // Unscoped returns a shallow copy of the store, with an unscoped ORM allowing
 // one to work with soft deleted records.
 func (s *Store) Unscoped() *Store {
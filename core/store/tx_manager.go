@@ -6,10 +6,13 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/gobuffalo/packr"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/tidwall/gjson"
 	"gopkg.in/guregu/null.v3"
 
@@ -32,9 +35,29 @@ import (
 const DefaultGasLimit uint64 = 500000
 const nonceReloadLimit int = 1
 
+var (
+	promTxAttempts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tx_manager_tx_attempts_total",
+		Help: "The total number of transaction attempts broadcast, including gas bumps",
+	})
+	promGasBumps = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tx_manager_gas_bumps_total",
+		Help: "The total number of times a transaction was rebroadcast with a higher gas price",
+	})
+	promConfirmationLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "tx_manager_confirmation_latency_seconds",
+		Help:    "Seconds from a transaction's first attempt until it reached the minimum outgoing confirmations",
+		Buckets: prometheus.ExponentialBuckets(15, 2, 10),
+	})
+)
+
 // ErrPendingConnection is the error returned if TxManager is not connected.
 var ErrPendingConnection = errors.New("Cannot talk to chain, pending connection")
 
+// ErrTxCancelled is returned for a transaction whose nonce was taken by its
+// cancellation, so that it will never be mined.
+var ErrTxCancelled = errors.New("Transaction was cancelled")
+
 // TxManager represents an interface for interacting with the blockchain
 type TxManager interface {
 	HeadTrackable
@@ -43,10 +66,13 @@ type TxManager interface {
 
 	CreateTx(to common.Address, data []byte) (*models.Tx, error)
 	CreateTxWithGas(surrogateID null.String, to common.Address, data []byte, gasPriceWei *big.Int, gasLimit uint64) (*models.Tx, error)
+	CreateTxWithFees(surrogateID null.String, to common.Address, data []byte, maxFeePerGas, maxPriorityFeePerGas *big.Int, gasLimit uint64) (*models.Tx, error)
 	CreateTxWithEth(from, to common.Address, value *assets.Eth) (*models.Tx, error)
 	CheckAttempt(txAttempt *models.TxAttempt, blockHeight uint64) (*models.TxReceipt, AttemptState, error)
 
 	BumpGasUntilSafe(hash common.Hash) (*models.TxReceipt, AttemptState, error)
+	CancelTx(hash common.Hash) (*models.Tx, error)
+	SpeedUpTx(hash common.Hash) (*models.Tx, error)
 
 	ContractLINKBalance(wr models.WithdrawalRequest) (assets.Link, error)
 	WithdrawLINK(wr models.WithdrawalRequest) (common.Hash, error)
@@ -60,6 +86,8 @@ type TxManager interface {
 	GetLogs(q ethereum.FilterQuery) ([]models.Log, error)
 	GetTxReceipt(common.Hash) (*models.TxReceipt, error)
 	GetChainID() (*big.Int, error)
+	CallContract(msg CallMsg) ([]byte, error)
+	CallContractAt(msg CallMsg, block string) ([]byte, error)
 }
 
 //go:generate mockgen -package=mocks -destination=../internal/mocks/tx_manager_mocks.go github.com/smartcontractkit/chainlink/core/store TxManager
@@ -77,19 +105,28 @@ type EthTxManager struct {
 	accountsMutex       *sync.Mutex
 	connected           *abool.AtomicBool
 	currentHead         models.Head
+	gasPriceOracle      GasPriceOracle
+	recoveryMutex       sync.Mutex
+	pendingRecovery     *models.Head
+	recovering          bool
+	recoveredAt         map[common.Address]uint64
 }
 
 // NewEthTxManager constructs an EthTxManager using the passed variables and
 // initializing internal variables.
 func NewEthTxManager(client EthClient, config orm.ConfigReader, keyStore *KeyStore, orm *orm.ORM) *EthTxManager {
-	return &EthTxManager{
+	txm := &EthTxManager{
 		EthClient:     client,
 		config:        config,
 		keyStore:      keyStore,
 		orm:           orm,
 		accountsMutex: &sync.Mutex{},
 		connected:     abool.New(),
+		recoveredAt:   map[common.Address]uint64{},
 	}
+	// The oracle goes through the manager, whose client can be replaced.
+	txm.gasPriceOracle = NewGasPriceOracle(txm, config)
+	return txm
 }
 
 // Register activates accounts for outgoing transactions and client side
@@ -166,25 +203,135 @@ func (txm *EthTxManager) Disconnect() {
 	txm.connected.UnSet()
 }
 
-// OnNewHead does nothing; exists to comply with interface.
+// OnNewHead keeps track of the current head, lets the gas price oracle
+// sample it, and queues the recovery of accounts with stuck transactions.
 func (txm *EthTxManager) OnNewHead(head *models.Head) {
 	txm.currentHead = *head
+	txm.gasPriceOracle.OnNewHead(head)
+	txm.queueRecovery(head)
+}
+
+// OnReorg moves the current head to the new canonical chain and re-validates
+// transactions that were marked safe recently, since they may have been
+// mined in one of the orphaned blocks. Those no longer on the canonical chain
+// are marked unconfirmed again and rebroadcast.
+func (txm *EthTxManager) OnReorg(reorg *models.Reorg) {
+	txm.currentHead = *reorg.Head
+	txm.gasPriceOracle.OnNewHead(reorg.Head)
+
+	if len(reorg.Orphaned) == 0 {
+		return
+	}
+
+	// An attempt is superseded by a gas bump after EthGasBumpThreshold blocks,
+	// so any attempt mined in an orphaned block was sent no earlier than that
+	// many blocks before the first orphaned block.
+	since := uint64(0)
+	firstOrphaned := uint64(reorg.Orphaned[0].Number)
+	if threshold := txm.config.EthGasBumpThreshold(); firstOrphaned > threshold {
+		since = firstOrphaned - threshold
+	}
+
+	txs, err := txm.orm.SafeTxsSince(since)
+	if err != nil {
+		logger.Errorw("Unable to load safe transactions after reorg", "err", err)
+		return
+	}
+
+	for i := range txs {
+		if err := txm.revalidateSafeTx(&txs[i], reorg); err != nil {
+			logger.Warnw("Unable to re-validate transaction after reorg", "txID", txs[i].ID, "err", err)
+		}
+	}
+}
+
+func (txm *EthTxManager) revalidateSafeTx(tx *models.Tx, reorg *models.Reorg) error {
+	if tx.Hash == utils.EmptyHash {
+		// Confirmed by nonce rather than by receipt, see checkAccountForConfirmation
+		return nil
+	}
+
+	receipt, err := txm.GetTxReceipt(tx.Hash)
+	if err != nil {
+		return errors.Wrap(err, "revalidateSafeTx GetTxReceipt failed")
+	}
+
+	if !receipt.Unconfirmed() && receipt.BlockHash != nil && !reorg.Orphans(*receipt.BlockHash) {
+		return nil
+	}
+
+	logger.Warnw(
+		fmt.Sprintf("Tx %v is no longer on the canonical chain, rebroadcasting", tx.Hash.Hex()),
+		"txID", tx.ID,
+		"nonce", tx.Nonce,
+	)
+
+	if err := txm.orm.MarkTxUnsafe(tx); err != nil {
+		return errors.Wrap(err, "revalidateSafeTx MarkTxUnsafe failed")
+	}
+
+	if _, err := txm.SendRawTx(tx.SignedRawTx); err != nil && !isNonceTooLowError(err) {
+		return errors.Wrap(err, "revalidateSafeTx SendRawTx failed")
+	}
+	return nil
 }
 
 // CreateTx signs and sends a transaction to the Ethereum blockchain.
 func (txm *EthTxManager) CreateTx(to common.Address, data []byte) (*models.Tx, error) {
-	return txm.CreateTxWithGas(null.String{}, to, data, txm.config.EthGasPriceDefault(), DefaultGasLimit)
+	return txm.CreateTxWithGas(null.String{}, to, data, nil, 0)
 }
 
 // CreateTxWithGas signs and sends a transaction to the Ethereum blockchain.
+// If EthTxSimulation is set, the transaction is first simulated and not sent
+// if it reverts, and, unless a gas limit is given, its gas limit is set from
+// its gas estimate.
 func (txm *EthTxManager) CreateTxWithGas(surrogateID null.String, to common.Address, data []byte, gasPriceWei *big.Int, gasLimit uint64) (*models.Tx, error) {
 	ma, err := txm.nextAccount()
 	if err != nil {
 		return nil, err
 	}
 
-	gasPriceWei, gasLimit = normalizeGasParams(gasPriceWei, gasLimit, txm.config)
-	return txm.createTx(surrogateID, ma, to, data, gasPriceWei, gasLimit, nil)
+	estimateGas := gasLimit == 0
+	fees, gasLimit, err := txm.normalizeGasParams(gasPriceWei, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if gasLimit, err = txm.simulateTx(ma.Address, to, data, gasLimit, estimateGas); err != nil {
+		return nil, err
+	}
+	return txm.createTx(surrogateID, ma, to, data, fees, gasLimit, nil)
+}
+
+// CreateTxWithFees signs and sends an EIP-1559 dynamic fee transaction to
+// the Ethereum blockchain. Its fees are derived from the latest block's base
+// fee, within the given caps, either of which can be nil. It is simulated
+// first, like CreateTxWithGas.
+func (txm *EthTxManager) CreateTxWithFees(
+	surrogateID null.String,
+	to common.Address,
+	data []byte,
+	maxFeePerGas, maxPriorityFeePerGas *big.Int,
+	gasLimit uint64,
+) (*models.Tx, error) {
+	ma, err := txm.nextAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	estimateGas := gasLimit == 0
+	if gasLimit == 0 || !txm.config.Dev() {
+		gasLimit = DefaultGasLimit
+	}
+	fees, err := txm.dynamicFees(maxFeePerGas, maxPriorityFeePerGas)
+	if err != nil {
+		return nil, err
+	}
+
+	if gasLimit, err = txm.simulateTx(ma.Address, to, data, gasLimit, estimateGas); err != nil {
+		return nil, err
+	}
+	return txm.createTx(surrogateID, ma, to, data, fees, gasLimit, nil)
 }
 
 // CreateTxWithEth signs and sends a transaction with some ETH to transfer.
@@ -194,7 +341,11 @@ func (txm *EthTxManager) CreateTxWithEth(from, to common.Address, value *assets.
 		return nil, errors.New("account does not exist")
 	}
 
-	return txm.createTx(null.String{}, ma, to, []byte{}, txm.config.EthGasPriceDefault(), DefaultGasLimit, value)
+	fees, err := txm.suggestedFees()
+	if err != nil {
+		return nil, err
+	}
+	return txm.createTx(null.String{}, ma, to, []byte{}, fees, DefaultGasLimit, value)
 }
 
 func (txm *EthTxManager) nextAccount() (*ManagedAccount, error) {
@@ -210,20 +361,71 @@ func (txm *EthTxManager) nextAccount() (*ManagedAccount, error) {
 	return ma, nil
 }
 
-func normalizeGasParams(gasPriceWei *big.Int, gasLimit uint64, config orm.ConfigReader) (*big.Int, uint64) {
-	if !config.Dev() {
-		return config.EthGasPriceDefault(), DefaultGasLimit
+func (txm *EthTxManager) normalizeGasParams(gasPriceWei *big.Int, gasLimit uint64) (models.GasFees, uint64, error) {
+	if !txm.config.Dev() {
+		fees, err := txm.suggestedFees()
+		return fees, DefaultGasLimit, err
+	}
+
+	if gasLimit == 0 {
+		gasLimit = DefaultGasLimit
 	}
 
 	if gasPriceWei == nil {
-		gasPriceWei = config.EthGasPriceDefault()
+		fees, err := txm.suggestedFees()
+		return fees, gasLimit, err
 	}
 
-	if gasLimit == 0 {
-		gasLimit = DefaultGasLimit
+	return models.GasFees{GasPrice: capGasPrice(gasPriceWei, txm.config)}, gasLimit, nil
+}
+
+// suggestedFees returns dynamic fees if EthDynamicFees is set, otherwise the
+// gas price of the oracle.
+func (txm *EthTxManager) suggestedFees() (models.GasFees, error) {
+	if txm.config.EthDynamicFees() {
+		return txm.dynamicFees(nil, nil)
+	}
+	return models.GasFees{GasPrice: txm.suggestedGasPrice()}, nil
+}
+
+// suggestedGasPrice returns the gas price of the oracle, within the maximum.
+func (txm *EthTxManager) suggestedGasPrice() *big.Int {
+	return capGasPrice(txm.gasPriceOracle.GasPrice(), txm.config)
+}
+
+// dynamicFees returns the fees of a dynamic fee transaction. It offers the
+// priority fee on top of twice the latest base fee, so that the transaction
+// stays includable while the base fee rises over the next blocks, within
+// the given caps and EthMaxGasPriceWei. On a chain without a base fee, it
+// falls back to the oracle's gas price.
+func (txm *EthTxManager) dynamicFees(maxFeePerGas, maxPriorityFeePerGas *big.Int) (models.GasFees, error) {
+	header, err := txm.GetBlockByNumber("latest")
+	if err != nil {
+		return models.GasFees{}, errors.Wrap(err, "TxManager#dynamicFees GetBlockByNumber")
+	}
+	if header.BaseFeePerGas == nil {
+		logger.Warnw("Latest block has no base fee, sending a legacy transaction instead of a dynamic fee one")
+		gasPrice := txm.suggestedGasPrice()
+		if maxFeePerGas != nil && gasPrice.Cmp(maxFeePerGas) > 0 {
+			gasPrice = maxFeePerGas
+		}
+		return models.GasFees{GasPrice: gasPrice}, nil
 	}
 
-	return gasPriceWei, gasLimit
+	tip := txm.config.EthPriorityFeeWei()
+	if maxPriorityFeePerGas != nil {
+		tip = maxPriorityFeePerGas
+	}
+	maxFee := new(big.Int).Mul(header.BaseFeePerGas.ToInt(), big.NewInt(2))
+	maxFee.Add(maxFee, tip)
+	if maxFeePerGas != nil && maxFee.Cmp(maxFeePerGas) > 0 {
+		maxFee = maxFeePerGas
+	}
+	maxFee = capGasPrice(maxFee, txm.config)
+	if tip.Cmp(maxFee) > 0 {
+		tip = maxFee
+	}
+	return models.GasFees{GasPrice: maxFee, MaxFeePerGas: maxFee, MaxPriorityFeePerGas: tip}, nil
 }
 
 // createTx creates an ethereum transaction, and retries to submit the
@@ -233,11 +435,11 @@ func (txm *EthTxManager) createTx(
 	ma *ManagedAccount,
 	to common.Address,
 	data []byte,
-	gasPriceWei *big.Int,
+	fees models.GasFees,
 	gasLimit uint64,
 	value *assets.Eth) (*models.Tx, error) {
 
-	tx, err := txm.sendInitialTx(surrogateID, ma, to, data, gasPriceWei, gasLimit, value)
+	tx, err := txm.sendInitialTx(surrogateID, ma, to, data, fees, gasLimit, value)
 	for nrc := 0; isNonceTooLowError(err); nrc++ {
 		logger.Warnw("Tx #0: nonce too low, retrying with network nonce")
 
@@ -249,7 +451,7 @@ func (txm *EthTxManager) createTx(
 			return nil, err
 		}
 
-		err = txm.retryInitialTx(tx, ma, gasPriceWei)
+		err = txm.retryInitialTx(tx, ma, fees)
 	}
 
 	return tx, err
@@ -262,7 +464,7 @@ func (txm *EthTxManager) sendInitialTx(
 	ma *ManagedAccount,
 	to common.Address,
 	data []byte,
-	gasPriceWei *big.Int,
+	fees models.GasFees,
 	gasLimit uint64,
 	value *assets.Eth) (*models.Tx, error) {
 
@@ -274,7 +476,7 @@ func (txm *EthTxManager) sendInitialTx(
 			to,
 			value.ToInt(),
 			gasLimit,
-			gasPriceWei,
+			fees,
 			data)
 		if err != nil {
 			return errors.Wrap(err, "TxManager#sendInitialTx newEthTx")
@@ -292,6 +494,7 @@ func (txm *EthTxManager) sendInitialTx(
 		if err != nil {
 			return errors.Wrap(err, "TxManager#sendInitialTx SendRawTx")
 		}
+		promTxAttempts.Inc()
 
 		return nil
 	})
@@ -308,7 +511,7 @@ func (txm *EthTxManager) sendInitialTx(
 func (txm *EthTxManager) retryInitialTx(
 	tx *models.Tx,
 	ma *ManagedAccount,
-	gasPriceWei *big.Int) error {
+	fees models.GasFees) error {
 
 	err := ma.ReloadNonce(txm)
 	if err != nil {
@@ -322,7 +525,7 @@ func (txm *EthTxManager) retryInitialTx(
 			tx.To,
 			tx.Value.ToInt(),
 			tx.GasLimit,
-			gasPriceWei,
+			fees,
 			tx.Data)
 		if err != nil {
 			return errors.Wrap(err, "TxManager#retryInitialTx newEthTx")
@@ -356,17 +559,35 @@ func isNonceTooLowError(err error) bool {
 	return err != nil && nonceTooLowRegex.MatchString(err.Error())
 }
 
-// newEthTx returns a newly signed Ethereum Transaction
+// newEthTx returns a newly signed Ethereum Transaction, a dynamic fee one if
+// the fees are dynamic
 func (txm *EthTxManager) newEthTx(
 	account accounts.Account,
 	nonce uint64,
 	to common.Address,
 	amount *big.Int,
 	gasLimit uint64,
-	gasPrice *big.Int,
-	data []byte) (*types.Transaction, error) {
+	fees models.GasFees,
+	data []byte) (models.SignedTx, error) {
 
-	ethTx := types.NewTransaction(nonce, to, amount, gasLimit, gasPrice, data)
+	if fees.Dynamic() {
+		dynamicTx := models.NewDynamicFeeTx(
+			txm.config.ChainID(),
+			nonce,
+			to,
+			amount,
+			gasLimit,
+			fees.MaxPriorityFeePerGas,
+			fees.MaxFeePerGas,
+			data)
+		dynamicTx, err := txm.keyStore.SignDynamicFeeTx(account, dynamicTx)
+		if err != nil {
+			return nil, errors.Wrap(err, "TxManager keyStore.SignDynamicFeeTx")
+		}
+		return dynamicTx, nil
+	}
+
+	ethTx := types.NewTransaction(nonce, to, amount, gasLimit, fees.GasPrice, data)
 
 	ethTx, err := txm.keyStore.SignTx(account, ethTx, txm.config.ChainID())
 	if err != nil {
@@ -394,7 +615,15 @@ func (txm *EthTxManager) BumpGasUntilSafe(hash common.Hash) (*models.TxReceipt,
 		return nil, Unknown, errors.Wrap(err, "BumpGasUntilSafe FindTxByAttempt")
 	}
 
+	if tx.Confirmed && tx.Hash == utils.EmptyHash {
+		// Confirmed by nonce rather than by receipt, see checkAccountForConfirmation
+		return nil, Safe, fmt.Errorf("BumpGasUntilSafe a version of the Ethereum Transaction from %v with nonce %v", tx.From, tx.Nonce)
+	}
+
 	receipt, state, err := txm.checkChainForConfirmation(tx)
+	if tx.Cancelled && state == Safe {
+		return receipt, state, ErrTxCancelled
+	}
 	if err != nil || state != Unconfirmed {
 		return receipt, state, err
 	}
@@ -680,6 +909,7 @@ func (txm *EthTxManager) handleSafe(
 	if err := txm.orm.MarkTxSafe(tx, txAttempt); err != nil {
 		return errors.Wrap(err, "handleSafe MarkTxSafe failed")
 	}
+	promConfirmationLatency.Observe(time.Since(tx.Attempts[0].CreatedAt).Seconds())
 
 	minimumConfirmations := txm.config.MinOutgoingConfirmations()
 	ethBalance, linkBalance, balanceErr := txm.GetETHAndLINKBalances(tx.From)
@@ -696,39 +926,94 @@ func (txm *EthTxManager) handleSafe(
 	return nil
 }
 
-// bumpGas creates a new transaction attempt with an increased gas cost
+// bumpGas creates a new transaction attempt with an increased gas cost. The
+// gas price is raised by EthGasBumpWei, or to the oracle's gas price if the
+// market has moved further, but never beyond the maximum gas price. Dynamic
+// fee transactions have both their fees raised instead.
 func (txm *EthTxManager) bumpGas(tx *models.Tx, attemptIndex int, blockHeight uint64) error {
 	txAttempt := tx.Attempts[attemptIndex]
 
-	originalGasPrice := txAttempt.GasPrice.ToInt()
-	bumpedGasPrice := new(big.Int).Add(originalGasPrice, txm.config.EthGasBumpWei())
+	bumpedFees, ok := txm.bumpedFees(txAttempt)
+	if !ok {
+		logger.Warnw(
+			fmt.Sprintf("Tx #%d not bumped, its %v is already at the maximum", attemptIndex, txAttempt.Fees()),
+			"txHash", txAttempt.Hash)
+		return nil
+	}
 
-	bumpedTxAttempt, err := txm.createAttempt(tx, bumpedGasPrice, blockHeight)
+	bumpedTxAttempt, err := txm.createAttempt(tx, bumpedFees, blockHeight)
 	if err != nil {
 		return errors.Wrapf(err, "bumpGas from Tx #%s", txAttempt.Hash.Hex())
 	}
+	promGasBumps.Inc()
 
 	logger.Infow(
-		fmt.Sprintf("Tx #%d created with bumped gas %v", attemptIndex+1, bumpedGasPrice),
+		fmt.Sprintf("Tx #%d created with bumped gas %v", attemptIndex+1, bumpedFees),
 		"originalTxHash", txAttempt.Hash,
 		"newTxHash", bumpedTxAttempt.Hash)
 	return nil
 }
 
+// bumpedFees returns the fees of the attempt raised for a replacement, or
+// false if they are already at the maximum.
+func (txm *EthTxManager) bumpedFees(txAttempt *models.TxAttempt) (models.GasFees, bool) {
+	originalFees := txAttempt.Fees()
+	var bumpedFees models.GasFees
+	if originalFees.Dynamic() {
+		bumpedFees = txm.bumpDynamicFees(originalFees)
+	} else {
+		bumpedFees = models.GasFees{GasPrice: txm.bumpGasPrice(originalFees.GasPrice)}
+	}
+	return bumpedFees, bumpedFees.GasPrice.Cmp(originalFees.GasPrice) > 0
+}
+
+// bumpGasPrice returns the gas price raised by EthGasBumpWei, or to the
+// oracle's gas price if higher, within the maximum.
+func (txm *EthTxManager) bumpGasPrice(gasPriceWei *big.Int) *big.Int {
+	bumped := new(big.Int).Add(gasPriceWei, txm.config.EthGasBumpWei())
+	if marketGasPrice := txm.gasPriceOracle.GasPrice(); marketGasPrice.Cmp(bumped) > 0 {
+		bumped = marketGasPrice
+	}
+	return capGasPrice(bumped, txm.config)
+}
+
+// bumpDynamicFees raises both fees of a dynamic fee transaction by
+// EthGasBumpWei, or by the 10% nodes require of a replacement if more, with
+// the max fee within the maximum gas price.
+func (txm *EthTxManager) bumpDynamicFees(fees models.GasFees) models.GasFees {
+	maxFee := capGasPrice(bumpByAtLeastTenPercent(fees.MaxFeePerGas, txm.config.EthGasBumpWei()), txm.config)
+	tip := bumpByAtLeastTenPercent(fees.MaxPriorityFeePerGas, txm.config.EthGasBumpWei())
+	if tip.Cmp(maxFee) > 0 {
+		tip = maxFee
+	}
+	return models.GasFees{GasPrice: maxFee, MaxFeePerGas: maxFee, MaxPriorityFeePerGas: tip}
+}
+
+func bumpByAtLeastTenPercent(value, increase *big.Int) *big.Int {
+	tenPercent := new(big.Int).Div(new(big.Int).Add(value, big.NewInt(9)), big.NewInt(10))
+	if tenPercent.Cmp(increase) > 0 {
+		increase = tenPercent
+	}
+	return new(big.Int).Add(value, increase)
+}
+
 // createAttempt adds a new transaction attempt to a transaction record
 func (txm *EthTxManager) createAttempt(
 	tx *models.Tx,
-	gasPriceWei *big.Int,
+	fees models.GasFees,
 	blockHeight uint64,
 ) (*models.TxAttempt, error) {
 	ma := txm.getAccount(tx.From)
 	if ma == nil {
 		return nil, fmt.Errorf("Unable to locate %v as an available account in EthTxManager. Has TxManager been started or has the address been removed?", tx.From.Hex())
 	}
-	etx := tx.EthTx(gasPriceWei)
-	etx, err := txm.keyStore.SignTx(ma.Account, etx, txm.config.ChainID())
+	to, value, data := tx.To, tx.Value.ToInt(), tx.Data
+	if tx.Cancelled {
+		to, value, data = tx.From, big.NewInt(0), []byte{}
+	}
+	etx, err := txm.newEthTx(ma.Account, tx.Nonce, to, value, tx.GasLimit, fees, data)
 	if err != nil {
-		return nil, errors.Wrap(err, "createAttempt#SignTx failed")
+		return nil, errors.Wrap(err, "createAttempt#newEthTx failed")
 	}
 
 	logger.Debugw(fmt.Sprintf("Adding Tx attempt #%d", len(tx.Attempts)+1), "txID", tx.ID)
@@ -741,6 +1026,7 @@ func (txm *EthTxManager) createAttempt(
 	if _, err = txm.SendRawTx(txAttempt.SignedRawTx); err != nil {
 		return nil, errors.Wrap(err, "createAttempt#SendRawTx failed")
 	}
+	promTxAttempts.Inc()
 
 	return txAttempt, nil
 }
@@ -4,10 +4,12 @@ import (
 	"encoding/hex"
 	"errors"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/golang/mock/gomock"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/internal/mocks"
@@ -497,6 +499,59 @@ func TestTxManager_BumpGasUntilSafe_exceedsGasBumpThreshold(t *testing.T) {
 	ethMock.EventuallyAllCalled(t)
 }
 
+func TestTxManager_BumpGasUntilSafe_maxGasPrice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		maxGasPrice   string
+		wantAttempts  int
+		wantGasPrices []int64
+	}{
+		{"capped", "3", 2, []int64{1, 3}},
+		{"already at maximum", "1", 1, []int64{1}},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			app, cleanup := cltest.NewApplicationWithKey(t)
+			defer cleanup()
+
+			store := app.Store
+			config := store.Config
+			config.Set("ETH_MAX_GAS_PRICE_WEI", test.maxGasPrice)
+
+			txm := store.TxManager
+			from := cltest.GetAccountAddress(t, store)
+			sentAt := uint64(23456)
+			gasThreshold := sentAt + config.EthGasBumpThreshold()
+			ethMock := app.MockEthCallerSubscriber()
+			ethMock.Register("eth_getTransactionCount", "0x0")
+			ethMock.Register("eth_chainId", config.ChainID())
+			require.NoError(t, app.Store.ORM.CreateHead(cltest.Head(gasThreshold+1)))
+			require.NoError(t, app.StartAndConnect())
+
+			tx := cltest.CreateTx(t, store, from, sentAt)
+			require.Greater(t, len(tx.Attempts), 0)
+
+			ethMock.Register("eth_getTransactionReceipt", models.TxReceipt{})
+			ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+
+			_, state, err := txm.BumpGasUntilSafe(tx.Attempts[0].Hash)
+			assert.NoError(t, err)
+			assert.Equal(t, strpkg.Unconfirmed, state)
+
+			tx, err = store.FindTx(tx.ID)
+			require.NoError(t, err)
+			require.Len(t, tx.Attempts, test.wantAttempts)
+			for i, price := range test.wantGasPrices {
+				assert.Equal(t, big.NewInt(price), tx.Attempts[i].GasPrice.ToInt())
+			}
+		})
+	}
+}
+
 func TestTxManager_BumpGasUntilSafe_confirmed(t *testing.T) {
 	t.Parallel()
 
@@ -1078,6 +1133,8 @@ func TestTxManager_CreateTxWithGas(t *testing.T) {
 	customGasLimit := uint64(10009)
 
 	defaultGasPrice := models.NewBig(config.EthGasPriceDefault())
+	excessiveGasPrice := models.NewBig(new(big.Int).Add(config.EthMaxGasPriceWei(), big.NewInt(1)))
+	maxGasPrice := models.NewBig(config.EthMaxGasPriceWei())
 
 	tests := []struct {
 		name             string
@@ -1091,6 +1148,7 @@ func TestTxManager_CreateTxWithGas(t *testing.T) {
 		{"dev but not set", true, nil, 0, defaultGasPrice, strpkg.DefaultGasLimit},
 		{"not dev", false, customGasPrice, customGasLimit, defaultGasPrice, strpkg.DefaultGasLimit},
 		{"not dev not set", false, nil, 0, defaultGasPrice, strpkg.DefaultGasLimit},
+		{"dev above maximum", true, excessiveGasPrice, customGasLimit, maxGasPrice, customGasLimit},
 	}
 
 	for _, test := range tests {
@@ -1113,6 +1171,122 @@ func TestTxManager_CreateTxWithGas(t *testing.T) {
 	}
 }
 
+func TestTxManager_CreateTxWithFees(t *testing.T) {
+	t.Parallel()
+
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1000000000)) }
+
+	tests := []struct {
+		name            string
+		baseFee         *big.Int
+		maxFee          *big.Int
+		priorityFee     *big.Int
+		wantMaxFee      *big.Int
+		wantPriorityFee *big.Int
+	}{
+		{"derived from base fee", gwei(10), nil, nil, gwei(21), gwei(1)},
+		{"job priority fee", gwei(10), nil, gwei(3), gwei(23), gwei(3)},
+		{"job max fee", gwei(10), gwei(15), gwei(2), gwei(15), gwei(2)},
+		{"priority fee above max fee", gwei(10), gwei(2), gwei(3), gwei(2), gwei(2)},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			store, cleanup := cltest.NewStore(t)
+			defer cleanup()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			eth := mocks.NewMockEthClient(ctrl)
+			config := cltest.NewTestConfig(t)
+			keyStore := strpkg.NewKeyStore(config.KeysDir())
+			account, err := keyStore.NewAccount(cltest.Password)
+			require.NoError(t, err)
+			require.NoError(t, keyStore.Unlock(cltest.Password))
+			manager := strpkg.NewEthTxManager(eth, config, keyStore, store.ORM)
+			manager.Register(keyStore.Accounts())
+
+			eth.EXPECT().GetNonce(account.Address).Return(uint64(0), nil)
+			require.NoError(t, manager.Connect(cltest.Head(1)))
+
+			baseFee := hexutil.Big(*test.baseFee)
+			eth.EXPECT().GetBlockByNumber("latest").Return(models.BlockHeader{BaseFeePerGas: &baseFee}, nil)
+			var rawTx string
+			eth.EXPECT().SendRawTx(gomock.Any()).DoAndReturn(func(hex string) (common.Hash, error) {
+				rawTx = hex
+				return cltest.NewHash(), nil
+			})
+
+			tx, err := manager.CreateTxWithFees(null.String{}, cltest.NewAddress(), []byte{1}, test.maxFee, test.priorityFee, 0)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(rawTx, "0x02"), "expected a typed transaction but got %s", rawTx)
+
+			tx, err = store.FindTx(tx.ID)
+			require.NoError(t, err)
+			require.Len(t, tx.Attempts, 1)
+			attempt := tx.Attempts[0]
+			assert.Equal(t, test.wantMaxFee, attempt.MaxFeePerGas.ToInt())
+			assert.Equal(t, test.wantPriorityFee, attempt.MaxPriorityFeePerGas.ToInt())
+			assert.Equal(t, test.wantMaxFee, attempt.GasPrice.ToInt())
+			assert.Equal(t, rawTx, attempt.SignedRawTx)
+		})
+	}
+}
+
+func TestTxManager_BumpGasUntilSafe_dynamicFees(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eth := mocks.NewMockEthClient(ctrl)
+	config := cltest.NewTestConfig(t)
+	config.Set("ETH_DYNAMIC_FEES", true)
+	config.Set("ETH_GAS_BUMP_WEI", "2")
+	config.Set("ETH_PRIORITY_FEE_WEI", "50")
+	config.Set("ETH_MAX_GAS_PRICE_WEI", "300")
+	keyStore := strpkg.NewKeyStore(config.KeysDir())
+	account, err := keyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.Unlock(cltest.Password))
+	manager := strpkg.NewEthTxManager(eth, config, keyStore, store.ORM)
+	manager.Register(keyStore.Accounts())
+
+	eth.EXPECT().GetNonce(account.Address).Return(uint64(0), nil)
+	require.NoError(t, manager.Connect(cltest.Head(1)))
+
+	baseFee := hexutil.Big(*big.NewInt(100))
+	eth.EXPECT().GetBlockByNumber("latest").Return(models.BlockHeader{BaseFeePerGas: &baseFee}, nil)
+	eth.EXPECT().SendRawTx(gomock.Any()).Return(cltest.NewHash(), nil).Times(3)
+
+	tx, err := manager.CreateTx(cltest.NewAddress(), []byte{1})
+	require.NoError(t, err)
+
+	eth.EXPECT().GetTxReceipt(gomock.Any()).Return(&models.TxReceipt{}, nil).AnyTimes()
+	for i := 0; i < 3; i++ {
+		manager.OnNewHead(cltest.Head(2 + (i+1)*int(config.EthGasBumpThreshold())))
+		_, state, err := manager.BumpGasUntilSafe(tx.Attempts[0].Hash)
+		require.NoError(t, err)
+		assert.Equal(t, strpkg.Unconfirmed, state)
+	}
+
+	tx, err = store.FindTx(tx.ID)
+	require.NoError(t, err)
+	require.Len(t, tx.Attempts, 3)
+	wantFees := [][2]int64{{250, 50}, {275, 55}, {300, 61}}
+	for i, fees := range wantFees {
+		assert.Equal(t, big.NewInt(fees[0]), tx.Attempts[i].MaxFeePerGas.ToInt())
+		assert.Equal(t, big.NewInt(fees[1]), tx.Attempts[i].MaxPriorityFeePerGas.ToInt())
+	}
+}
+
 func TestGetContract(t *testing.T) {
 	t.Parallel()
 
@@ -1227,3 +1401,54 @@ func TestTxManager_RebroadcastUnconfirmedTxsOnReconnect(t *testing.T) {
 	err = manager.Connect(cltest.Head(sentAt))
 	require.NoError(t, err)
 }
+
+func TestTxManager_OnReorg_RebroadcastsOrphanedSafeTxs(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eth := mocks.NewMockEthClient(ctrl)
+	config := cltest.NewTestConfig(t)
+	keyStore := strpkg.NewKeyStore(config.KeysDir())
+	manager := strpkg.NewEthTxManager(eth, config, keyStore, store.ORM)
+
+	from := cltest.NewAddress()
+	orphanedTx := cltest.CreateTxWithNonce(t, store, from, 100, 0)
+	canonicalTx := cltest.CreateTxWithNonce(t, store, from, 100, 1)
+	require.NoError(t, store.MarkTxSafe(orphanedTx, orphanedTx.Attempts[0]))
+	require.NoError(t, store.MarkTxSafe(canonicalTx, canonicalTx.Attempts[0]))
+
+	orphanedHash := cltest.NewHash()
+	canonicalHash := cltest.NewHash()
+	reorg := &models.Reorg{
+		Head:     cltest.Head(103),
+		Orphaned: []models.Head{{Number: 102, Hash: orphanedHash}},
+	}
+
+	eth.EXPECT().GetTxReceipt(orphanedTx.Hash).Return(&models.TxReceipt{
+		Hash:        orphanedTx.Hash,
+		BlockHash:   &orphanedHash,
+		BlockNumber: cltest.Int(102),
+	}, nil)
+	eth.EXPECT().GetTxReceipt(canonicalTx.Hash).Return(&models.TxReceipt{
+		Hash:        canonicalTx.Hash,
+		BlockHash:   &canonicalHash,
+		BlockNumber: cltest.Int(101),
+	}, nil)
+	eth.EXPECT().SendRawTx(orphanedTx.SignedRawTx).Return(orphanedTx.Hash, nil)
+
+	manager.OnReorg(reorg)
+
+	found, err := store.FindTx(orphanedTx.ID)
+	require.NoError(t, err)
+	assert.False(t, found.Confirmed)
+	assert.False(t, found.Attempts[0].Confirmed)
+
+	found, err = store.FindTx(canonicalTx.ID)
+	require.NoError(t, err)
+	assert.True(t, found.Confirmed)
+}
@@ -0,0 +1,281 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	"go.uber.org/multierr"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// cancelGasLimit is the gas limit of a transaction cancelling a nonce that
+// has no transaction to replace, enough for a plain transfer.
+const cancelGasLimit = 21000
+
+// CancelTx replaces the transaction with a zero value transfer to its sender
+// at the same nonce, priced above its latest attempt, so that the nonce is
+// freed once the cancellation is mined.
+func (txm *EthTxManager) CancelTx(hash common.Hash) (*models.Tx, error) {
+	tx, err := txm.findReplaceableTx(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.Cancelled = true
+	if _, err := txm.replaceTx(tx, uint64(txm.currentHead.Number)); err != nil {
+		return nil, errors.Wrap(err, "CancelTx")
+	}
+	logger.Infow(fmt.Sprintf("Tx %v cancelled", hash.Hex()), "txID", tx.ID, "nonce", tx.Nonce)
+	return tx, nil
+}
+
+// SpeedUpTx bumps the gas of the transaction right away, rather than waiting
+// for EthGasBumpThreshold blocks.
+func (txm *EthTxManager) SpeedUpTx(hash common.Hash) (*models.Tx, error) {
+	tx, err := txm.findReplaceableTx(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := txm.replaceTx(tx, uint64(txm.currentHead.Number)); err != nil {
+		return nil, errors.Wrap(err, "SpeedUpTx")
+	}
+	return tx, nil
+}
+
+func (txm *EthTxManager) findReplaceableTx(hash common.Hash) (*models.Tx, error) {
+	if !txm.Connected() {
+		return nil, errors.Wrap(ErrPendingConnection, "EthTxManager#findReplaceableTx")
+	}
+
+	tx, _, err := txm.orm.FindTxByAttempt(hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Confirmed {
+		return nil, fmt.Errorf("Tx %v is already confirmed", hash.Hex())
+	}
+	return tx, nil
+}
+
+// replaceTx sends a new attempt of the transaction with its latest
+// attempt's fees bumped, as nodes only accept a replacement at the same
+// nonce if it pays more.
+func (txm *EthTxManager) replaceTx(tx *models.Tx, blockHeight uint64) (*models.TxAttempt, error) {
+	latest := tx.Attempts[len(tx.Attempts)-1]
+	fees, ok := txm.bumpedFees(latest)
+	if !ok {
+		return nil, fmt.Errorf("Tx %v cannot be replaced, its %v is already at the maximum", latest.Hash.Hex(), latest.Fees())
+	}
+	return txm.createAttempt(tx, fees, blockHeight)
+}
+
+// queueRecovery queues the head to have stuck transactions recovered in the
+// background, so that heads are not held up by the requests to the node;
+// while a recovery is running, only the latest of the heads that arrive in
+// the meantime is recovered next.
+func (txm *EthTxManager) queueRecovery(head *models.Head) {
+	txm.recoveryMutex.Lock()
+	defer txm.recoveryMutex.Unlock()
+	txm.pendingRecovery = head
+	if !txm.recovering {
+		txm.recovering = true
+		go txm.recoveryLoop()
+	}
+}
+
+func (txm *EthTxManager) recoveryLoop() {
+	for {
+		txm.recoveryMutex.Lock()
+		head := txm.pendingRecovery
+		txm.pendingRecovery = nil
+		if head == nil {
+			txm.recovering = false
+			txm.recoveryMutex.Unlock()
+			return
+		}
+		txm.recoveryMutex.Unlock()
+
+		txm.recoverStuckTxs(uint64(head.Number))
+	}
+}
+
+// recoverStuckTxs checks the nonces of every account with a transaction that
+// has not been mined EthStuckTxBlocks blocks after its first attempt against
+// the account's nonce on chain, and unwedges the account:
+//
+// - Transactions whose nonce was used by another transaction, such as one
+// broadcast by other software using the same key, are confirmed by nonce.
+// - A transaction holding up the account after exhausting TxAttemptLimit is
+// cancelled, and its cancellation bumped until mined.
+// - Nonces that were handed out without a transaction to mine them are
+// filled with cancellations.
+//
+// An account that stays stuck is only checked again EthGasBumpThreshold
+// blocks later, as its cancellations are not bumped any sooner.
+func (txm *EthTxManager) recoverStuckTxs(blockHeight uint64) {
+	stuckBlocks := txm.config.EthStuckTxBlocks()
+	if stuckBlocks == 0 || blockHeight < stuckBlocks || !txm.Connected() {
+		return
+	}
+
+	txs, err := txm.orm.UnconfirmedTxs()
+	if err != nil {
+		logger.Warnw("Unable to load unconfirmed transactions to recover stuck ones", "error", err)
+		return
+	}
+
+	byAccount := map[common.Address][]models.Tx{}
+	stuck := map[common.Address]bool{}
+	for _, tx := range txs {
+		byAccount[tx.From] = append(byAccount[tx.From], tx)
+		if len(tx.Attempts) > 0 && tx.Attempts[0].SentAt+stuckBlocks <= blockHeight {
+			stuck[tx.From] = true
+		}
+	}
+
+	for address, recoveredAt := range txm.recoveredAt {
+		if !stuck[address] || recoveredAt > blockHeight {
+			delete(txm.recoveredAt, address)
+		}
+	}
+
+	for address := range stuck {
+		ma := txm.getAccount(address)
+		if ma == nil {
+			continue
+		}
+		if recoveredAt, ok := txm.recoveredAt[address]; ok && blockHeight < recoveredAt+txm.config.EthGasBumpThreshold() {
+			continue
+		}
+		txm.recoveredAt[address] = blockHeight
+		if err := txm.recoverAccount(ma, byAccount[address], blockHeight); err != nil {
+			logger.Warnw("Unable to recover stuck transactions", "address", address.Hex(), "error", err)
+		}
+	}
+}
+
+// recoverAccount recovers the account, given its unconfirmed transactions in
+// nonce order.
+func (txm *EthTxManager) recoverAccount(ma *ManagedAccount, txs []models.Tx, blockHeight uint64) error {
+	chainNonce, err := txm.GetNonce(ma.Address)
+	if err != nil {
+		return errors.Wrap(err, "recoverAccount GetNonce")
+	}
+
+	if chainNonce > ma.Nonce() {
+		logger.Warnw(
+			fmt.Sprintf("Nonce %d of %v on chain is ahead of ours, it is being used by other software", chainNonce, ma.Address.Hex()),
+			"localNonce", ma.Nonce())
+		if err := ma.ReloadNonce(txm); err != nil {
+			return err
+		}
+	}
+
+	var merr error
+	nextNonce := chainNonce
+	for i := range txs {
+		tx := &txs[i]
+		switch {
+		case tx.Nonce < chainNonce:
+			merr = multierr.Append(merr, txm.confirmByNonce(tx))
+		case tx.Nonce == chainNonce:
+			merr = multierr.Append(merr, txm.unblockTx(tx, blockHeight))
+		}
+		if tx.Nonce >= nextNonce {
+			nextNonce = tx.Nonce + 1
+		}
+	}
+
+	// Nonces below our own that no transaction will mine hold up the account.
+	for nonce := chainNonce; nonce < ma.Nonce() && nonce < nextNonce; nonce++ {
+		if !hasTxWithNonce(txs, nonce) {
+			merr = multierr.Append(merr, txm.cancelNonce(ma, nonce, blockHeight))
+		}
+	}
+	return merr
+}
+
+// confirmByNonce confirms a transaction whose nonce is already used on chain,
+// unless one of its attempts was mined, which BumpGasUntilSafe handles.
+func (txm *EthTxManager) confirmByNonce(tx *models.Tx) error {
+	for _, attempt := range tx.Attempts {
+		receipt, err := txm.GetTxReceipt(attempt.Hash)
+		if err != nil {
+			return errors.Wrap(err, "confirmByNonce GetTxReceipt")
+		}
+		if !receipt.Unconfirmed() {
+			return nil
+		}
+	}
+
+	logger.Warnw(
+		fmt.Sprintf("Tx %v was replaced by another transaction with nonce %d, possibly sent by other software using the same key", tx.Hash.Hex(), tx.Nonce),
+		"txID", tx.ID)
+	txm.updateLastSafeNonce(tx)
+	tx.Confirmed = true
+	tx.Hash = utils.EmptyHash
+	return txm.orm.SaveTx(tx)
+}
+
+// unblockTx cancels the transaction holding up its account once it has
+// exhausted TxAttemptLimit, and keeps bumping its cancellation, which
+// BumpGasUntilSafe no longer does past that limit.
+func (txm *EthTxManager) unblockTx(tx *models.Tx, blockHeight uint64) error {
+	attemptIndex := len(tx.Attempts) - 1
+	if attemptIndex < int(txm.config.TxAttemptLimit()) || !txm.hasTxAttemptMetGasBumpThreshold(tx, attemptIndex, blockHeight) {
+		return nil
+	}
+
+	if !tx.Cancelled {
+		logger.Warnw(
+			fmt.Sprintf("Tx %v has exhausted its attempts, cancelling it to free nonce %d", tx.Hash.Hex(), tx.Nonce),
+			"txID", tx.ID)
+		tx.Cancelled = true
+	}
+	_, err := txm.replaceTx(tx, blockHeight)
+	return err
+}
+
+// cancelNonce sends a cancellation for a nonce that has no transaction.
+func (txm *EthTxManager) cancelNonce(ma *ManagedAccount, nonce uint64, blockHeight uint64) error {
+	fees, err := txm.suggestedFees()
+	if err != nil {
+		return err
+	}
+
+	ethTx, err := txm.newEthTx(ma.Account, nonce, ma.Address, big.NewInt(0), cancelGasLimit, fees, []byte{})
+	if err != nil {
+		return errors.Wrap(err, "cancelNonce newEthTx")
+	}
+
+	logger.Warnw(fmt.Sprintf("Nonce %d of %v has no transaction, cancelling it", nonce, ma.Address.Hex()))
+	tx, err := txm.orm.CreateTx(null.String{}, ethTx, &ma.Address, blockHeight)
+	if err != nil {
+		return errors.Wrap(err, "cancelNonce CreateTx")
+	}
+	tx.Cancelled = true
+	if err := txm.orm.SaveTx(tx); err != nil {
+		return errors.Wrap(err, "cancelNonce SaveTx")
+	}
+
+	if _, err := txm.SendRawTx(tx.SignedRawTx); err != nil {
+		return errors.Wrap(err, "cancelNonce SendRawTx")
+	}
+	promTxAttempts.Inc()
+	return nil
+}
+
+func hasTxWithNonce(txs []models.Tx, nonce uint64) bool {
+	for _, tx := range txs {
+		if tx.Nonce == nonce {
+			return true
+		}
+	}
+	return false
+}
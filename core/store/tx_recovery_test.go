@@ -0,0 +1,257 @@
+package store_test
+
+import (
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecoveringTxManager(t *testing.T, nonce uint64) (*strpkg.Store, *strpkg.EthTxManager, *mocks.MockEthClient, accounts.Account, func()) {
+	store, cleanup := cltest.NewStore(t)
+	ctrl := gomock.NewController(t)
+
+	eth := mocks.NewMockEthClient(ctrl)
+	config := store.Config
+	config.Set("ETH_STUCK_TX_BLOCKS", 100)
+	config.Set("CHAINLINK_TX_ATTEMPT_LIMIT", 1)
+	keyStore := strpkg.NewKeyStore(config.KeysDir())
+	account, err := keyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.Unlock(cltest.Password))
+	manager := strpkg.NewEthTxManager(eth, config, keyStore, store.ORM)
+	manager.Register(keyStore.Accounts())
+
+	eth.EXPECT().GetNonce(account.Address).Return(nonce, nil)
+	require.NoError(t, manager.Connect(cltest.Head(1)))
+
+	return store, manager, eth, account, func() {
+		ctrl.Finish()
+		cleanup()
+	}
+}
+
+func sendTx(t *testing.T, manager *strpkg.EthTxManager, eth *mocks.MockEthClient) *models.Tx {
+	eth.EXPECT().SendRawTx(gomock.Any()).Return(cltest.NewHash(), nil)
+	tx, err := manager.CreateTx(cltest.NewAddress(), []byte{1, 2, 3})
+	require.NoError(t, err)
+	return tx
+}
+
+// expectRawTx expects a transaction to be sent, and returns a channel
+// receiving it once it is.
+func expectRawTx(eth *mocks.MockEthClient) <-chan string {
+	sent := make(chan string, 1)
+	eth.EXPECT().SendRawTx(gomock.Any()).DoAndReturn(func(hex string) (common.Hash, error) {
+		sent <- hex
+		return cltest.NewHash(), nil
+	})
+	return sent
+}
+
+func decodeRawTx(t *testing.T, raw string) *big.Int {
+	decoded, err := utils.DecodeEthereumTx(raw)
+	require.NoError(t, err)
+	return decoded.Value()
+}
+
+func TestTxManager_CancelTx(t *testing.T) {
+	t.Parallel()
+
+	store, manager, eth, account, cleanup := newRecoveringTxManager(t, 0)
+	defer cleanup()
+
+	tx := sendTx(t, manager, eth)
+	originalHash := tx.Hash
+
+	var rawTx string
+	eth.EXPECT().SendRawTx(gomock.Any()).DoAndReturn(func(hex string) (common.Hash, error) {
+		rawTx = hex
+		return cltest.NewHash(), nil
+	})
+	cancelled, err := manager.CancelTx(originalHash)
+	require.NoError(t, err)
+	assert.True(t, cancelled.Cancelled)
+	assert.NotEqual(t, originalHash, cancelled.Hash)
+
+	decoded, err := utils.DecodeEthereumTx(rawTx)
+	require.NoError(t, err)
+	assert.Equal(t, &account.Address, decoded.To())
+	assert.Equal(t, big.NewInt(0), decoded.Value())
+	assert.Empty(t, decoded.Data())
+	assert.Equal(t, tx.Nonce, decoded.Nonce())
+	assert.True(t, decoded.GasPrice().Cmp(tx.GasPrice.ToInt()) > 0)
+
+	tx, err = store.FindTx(tx.ID)
+	require.NoError(t, err)
+	assert.True(t, tx.Cancelled)
+	require.Len(t, tx.Attempts, 2)
+
+	// The cancellation's receipt fails the original transaction
+	eth.EXPECT().GetTxReceipt(tx.Attempts[1].Hash).Return(&models.TxReceipt{Hash: tx.Attempts[1].Hash, BlockNumber: cltest.Int(1)}, nil)
+	eth.EXPECT().GetEthBalance(account.Address).Return(nil, nil)
+	eth.EXPECT().GetERC20Balance(account.Address, gomock.Any()).Return(nil, nil)
+	manager.OnNewHead(cltest.Head(1 + store.Config.MinOutgoingConfirmations()))
+	_, state, err := manager.BumpGasUntilSafe(originalHash)
+	assert.Equal(t, strpkg.ErrTxCancelled, err)
+	assert.Equal(t, strpkg.Safe, state)
+}
+
+func TestTxManager_SpeedUpTx(t *testing.T) {
+	t.Parallel()
+
+	store, manager, eth, _, cleanup := newRecoveringTxManager(t, 0)
+	defer cleanup()
+
+	tx := sendTx(t, manager, eth)
+
+	var rawTx string
+	eth.EXPECT().SendRawTx(gomock.Any()).DoAndReturn(func(hex string) (common.Hash, error) {
+		rawTx = hex
+		return cltest.NewHash(), nil
+	})
+	spedUp, err := manager.SpeedUpTx(tx.Hash)
+	require.NoError(t, err)
+	assert.False(t, spedUp.Cancelled)
+
+	decoded, err := utils.DecodeEthereumTx(rawTx)
+	require.NoError(t, err)
+	assert.Equal(t, &tx.To, decoded.To())
+	assert.Equal(t, tx.Data, decoded.Data())
+	wantGasPrice := new(big.Int).Add(tx.GasPrice.ToInt(), store.Config.EthGasBumpWei())
+	assert.Equal(t, wantGasPrice, decoded.GasPrice())
+
+	require.NoError(t, store.MarkTxSafe(spedUp, spedUp.Attempts[1]))
+	_, err = manager.SpeedUpTx(tx.Hash)
+	assert.EqualError(t, err, "Tx "+tx.Hash.Hex()+" is already confirmed")
+
+	_, err = manager.CancelTx(cltest.NewHash())
+	assert.Equal(t, orm.ErrorNotFound, err)
+}
+
+func TestTxManager_OnNewHead_CancelsExhaustedTx(t *testing.T) {
+	t.Parallel()
+
+	store, manager, eth, account, cleanup := newRecoveringTxManager(t, 0)
+	defer cleanup()
+
+	tx := sendTx(t, manager, eth)
+	eth.EXPECT().SendRawTx(gomock.Any()).Return(cltest.NewHash(), nil)
+	_, err := manager.SpeedUpTx(tx.Hash)
+	require.NoError(t, err)
+
+	// Not stuck yet
+	manager.OnNewHead(cltest.Head(100))
+
+	eth.EXPECT().GetNonce(account.Address).Return(uint64(0), nil)
+	sent := expectRawTx(eth)
+	manager.OnNewHead(cltest.Head(101))
+
+	assert.Equal(t, big.NewInt(0), decodeRawTx(t, <-sent))
+	gomega.NewGomegaWithT(t).Eventually(func() int {
+		tx, err = store.FindTx(tx.ID)
+		require.NoError(t, err)
+		return len(tx.Attempts)
+	}).Should(gomega.Equal(3))
+	assert.True(t, tx.Cancelled)
+}
+
+func TestTxManager_OnNewHead_ConfirmsTxReplacedByOtherSoftware(t *testing.T) {
+	t.Parallel()
+
+	store, manager, eth, account, cleanup := newRecoveringTxManager(t, 0)
+	defer cleanup()
+
+	tx := sendTx(t, manager, eth)
+
+	// Nonces 0 and 1 were used by another node sharing the key
+	eth.EXPECT().GetNonce(account.Address).Return(uint64(2), nil).Times(2)
+	eth.EXPECT().GetTxReceipt(tx.Hash).Return(&models.TxReceipt{}, nil)
+	manager.OnNewHead(cltest.Head(101))
+
+	gomega.NewGomegaWithT(t).Eventually(func() bool {
+		var err error
+		tx, err = store.FindTx(tx.ID)
+		require.NoError(t, err)
+		return tx.Confirmed
+	}).Should(gomega.BeTrue())
+	assert.Equal(t, uint64(2), manager.GetAvailableAccount(account.Address).Nonce())
+	assert.Equal(t, utils.EmptyHash, tx.Hash)
+
+	_, state, err := manager.BumpGasUntilSafe(tx.Attempts[0].Hash)
+	assert.Error(t, err)
+	assert.Equal(t, strpkg.Safe, state)
+}
+
+func TestTxManager_OnNewHead_CancelsNonceGap(t *testing.T) {
+	t.Parallel()
+
+	store, manager, eth, account, cleanup := newRecoveringTxManager(t, 0)
+	defer cleanup()
+
+	first := sendTx(t, manager, eth)
+	second := sendTx(t, manager, eth)
+	require.NoError(t, store.DB.Delete(first).Error)
+
+	eth.EXPECT().GetNonce(account.Address).Return(uint64(0), nil)
+	sent := expectRawTx(eth)
+	manager.OnNewHead(cltest.Head(101))
+
+	decoded, err := utils.DecodeEthereumTx(<-sent)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), decoded.Nonce())
+	assert.Equal(t, &account.Address, decoded.To())
+
+	var txs []models.Tx
+	gomega.NewGomegaWithT(t).Eventually(func() []models.Tx {
+		txs, err = store.UnconfirmedTxs()
+		require.NoError(t, err)
+		return txs
+	}).Should(gomega.HaveLen(2))
+	assert.Equal(t, uint64(0), txs[0].Nonce)
+	assert.True(t, txs[0].Cancelled)
+	assert.Equal(t, second.ID, txs[1].ID)
+}
+
+func TestTxManager_OnNewHead_ThrottlesStuckAccount(t *testing.T) {
+	t.Parallel()
+
+	store, manager, eth, account, cleanup := newRecoveringTxManager(t, 0)
+	defer cleanup()
+	store.Config.Set("ETH_GAS_BUMP_THRESHOLD", 5)
+	g := gomega.NewGomegaWithT(t)
+
+	// The transaction stays stuck, but has attempts left to be bumped
+	store.Config.Set("CHAINLINK_TX_ATTEMPT_LIMIT", 10)
+	sendTx(t, manager, eth)
+
+	var checks int32
+	eth.EXPECT().GetNonce(account.Address).DoAndReturn(func(common.Address) (uint64, error) {
+		atomic.AddInt32(&checks, 1)
+		return 0, nil
+	}).AnyTimes()
+	countChecks := func() int32 { return atomic.LoadInt32(&checks) }
+
+	manager.OnNewHead(cltest.Head(101))
+	g.Eventually(countChecks).Should(gomega.Equal(int32(1)))
+
+	for i := int64(102); i < 106; i++ {
+		manager.OnNewHead(cltest.Head(i))
+	}
+	g.Consistently(countChecks).Should(gomega.Equal(int32(1)))
+
+	manager.OnNewHead(cltest.Head(106))
+	g.Eventually(countChecks).Should(gomega.Equal(int32(2)))
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/chainlink/core/logger"
+)
+
+// revertErrorSelector is the selector of Error(string), which a contract
+// returns the reason of a revert or failed require encoded with.
+var revertErrorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// revertRegex matches the errors nodes return for a call that reverts:
+// geth's "execution reverted", Parity's "VM execution error" and the
+// "always failing transaction" of eth_estimateGas.
+var revertRegex = regexp.MustCompile("(?i)(revert|VM execution error|always failing transaction)")
+
+// TxRevertedError is returned for a transaction that reverts when simulated
+// against the pending state, in which case it is not sent.
+type TxRevertedError struct {
+	Reason string
+}
+
+// Error returns the error message, with the revert reason if there is one.
+func (e *TxRevertedError) Error() string {
+	if e.Reason == "" {
+		return "Transaction reverted in simulation"
+	}
+	return fmt.Sprintf("Transaction reverted in simulation: %s", e.Reason)
+}
+
+// simulateTx runs the transaction against the pending state with eth_call,
+// to catch a fulfillment for a request that was already fulfilled or
+// cancelled, or a callback that reverts, before paying for it. It returns
+// the gas limit to send the transaction with: when estimateGas is set, the
+// transaction's gas estimate plus EthGasLimitMargin percent, otherwise the
+// given gasLimit. Nothing is simulated unless EthTxSimulation is set.
+func (txm *EthTxManager) simulateTx(from, to common.Address, data []byte, gasLimit uint64, estimateGas bool) (uint64, error) {
+	if !txm.config.EthTxSimulation() {
+		return gasLimit, nil
+	}
+
+	msg := CallMsg{From: from, To: to, Data: data}
+
+	result, err := txm.CallContract(msg)
+	if err != nil {
+		return 0, simulationError(err, "CallContract")
+	}
+	if bytes.HasPrefix(result, revertErrorSelector) {
+		return 0, &TxRevertedError{Reason: decodeRevertReason(result)}
+	}
+	if !estimateGas {
+		return gasLimit, nil
+	}
+
+	estimate, err := txm.EstimateGas(msg)
+	if err != nil {
+		return 0, simulationError(err, "EstimateGas")
+	}
+
+	gasLimit = estimate + estimate*txm.config.EthGasLimitMargin()/100
+	logger.Debugw(fmt.Sprintf("Simulated tx to %v, estimated %d gas", to.Hex(), estimate), "gasLimit", gasLimit)
+	return gasLimit, nil
+}
+
+// simulationError returns a TxRevertedError if the node's error is that of a
+// revert, otherwise the error itself.
+func simulationError(err error, method string) error {
+	if !revertRegex.MatchString(err.Error()) {
+		return errors.Wrapf(err, "TxManager#simulateTx %s", method)
+	}
+
+	reason := err.Error()
+	if i := strings.Index(reason, "execution reverted"); i >= 0 {
+		reason = strings.TrimLeft(reason[i+len("execution reverted"):], ": ")
+	}
+	return &TxRevertedError{Reason: reason}
+}
+
+// decodeRevertReason returns the reason of an Error(string) encoded revert,
+// or nothing if it cannot be decoded.
+func decodeRevertReason(result []byte) string {
+	stringType, err := abi.NewType("string", nil)
+	if err != nil {
+		return ""
+	}
+
+	var reason string
+	args := abi.Arguments{{Type: stringType}}
+	if err := args.Unpack(&reason, result[len(revertErrorSelector):]); err != nil {
+		return ""
+	}
+	return reason
+}
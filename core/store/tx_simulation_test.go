@@ -0,0 +1,135 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func newSimulatingTxManager(t *testing.T) (*strpkg.Store, *strpkg.EthTxManager, *mocks.MockEthClient, common.Address, func()) {
+	store, cleanup := cltest.NewStore(t)
+	ctrl := gomock.NewController(t)
+
+	eth := mocks.NewMockEthClient(ctrl)
+	config := store.Config
+	config.Set("ETH_TX_SIMULATION", true)
+	config.Set("ETH_GAS_LIMIT_MARGIN", 20)
+	keyStore := strpkg.NewKeyStore(config.KeysDir())
+	account, err := keyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.Unlock(cltest.Password))
+	manager := strpkg.NewEthTxManager(eth, config, keyStore, store.ORM)
+	manager.Register(keyStore.Accounts())
+
+	eth.EXPECT().GetNonce(account.Address).Return(uint64(0), nil)
+	require.NoError(t, manager.Connect(cltest.Head(1)))
+
+	return store, manager, eth, account.Address, func() {
+		ctrl.Finish()
+		cleanup()
+	}
+}
+
+func revertData(t *testing.T, reason string) []byte {
+	stringType, err := abi.NewType("string", nil)
+	require.NoError(t, err)
+	encoded, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	require.NoError(t, err)
+	return append([]byte{0x08, 0xc3, 0x79, 0xa0}, encoded...)
+}
+
+func TestTxManager_CreateTxWithGas_SimulationSetsGasLimit(t *testing.T) {
+	t.Parallel()
+
+	store, manager, eth, from, cleanup := newSimulatingTxManager(t)
+	defer cleanup()
+
+	to := cltest.NewAddress()
+	data := []byte{1, 2, 3}
+	msg := strpkg.CallMsg{From: from, To: to, Data: data}
+	eth.EXPECT().CallContract(msg).Return([]byte{}, nil)
+	eth.EXPECT().EstimateGas(msg).Return(uint64(100000), nil)
+	var rawTx string
+	eth.EXPECT().SendRawTx(gomock.Any()).DoAndReturn(func(hex string) (common.Hash, error) {
+		rawTx = hex
+		return cltest.NewHash(), nil
+	})
+
+	tx, err := manager.CreateTxWithGas(null.String{}, to, data, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(120000), tx.GasLimit)
+
+	decoded, err := utils.DecodeEthereumTx(rawTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(120000), decoded.Gas())
+
+	_, err = store.FindTx(tx.ID)
+	require.NoError(t, err)
+}
+
+func TestTxManager_CreateTxWithGas_SimulationKeepsGivenGasLimit(t *testing.T) {
+	t.Parallel()
+
+	_, manager, eth, from, cleanup := newSimulatingTxManager(t)
+	defer cleanup()
+
+	to := cltest.NewAddress()
+	data := []byte{1, 2, 3}
+	eth.EXPECT().CallContract(strpkg.CallMsg{From: from, To: to, Data: data}).Return([]byte{}, nil)
+	eth.EXPECT().SendRawTx(gomock.Any()).Return(cltest.NewHash(), nil)
+
+	tx, err := manager.CreateTxWithGas(null.String{}, to, data, nil, 300000)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300000), tx.GasLimit)
+}
+
+func TestTxManager_CreateTxWithGas_SimulationReverts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		result      []byte
+		callErr     error
+		estimateErr error
+		wantErr     string
+	}{
+		{"reason returned", revertData(t, "Must have a valid requestId"), nil, nil,
+			"Transaction reverted in simulation: Must have a valid requestId"},
+		{"reason in error", nil, errors.New("execution reverted: Not an authorized node"), nil,
+			"Transaction reverted in simulation: Not an authorized node"},
+		{"no reason", nil, errors.New("execution reverted"), nil,
+			"Transaction reverted in simulation"},
+		{"estimate fails", []byte{}, nil, errors.New("gas required exceeds allowance or always failing transaction"),
+			"Transaction reverted in simulation: gas required exceeds allowance or always failing transaction"},
+		{"node unavailable", nil, errors.New("connection refused"), nil,
+			"TxManager#simulateTx CallContract: connection refused"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, manager, eth, _, cleanup := newSimulatingTxManager(t)
+			defer cleanup()
+
+			eth.EXPECT().CallContract(gomock.Any()).Return(test.result, test.callErr)
+			if test.estimateErr != nil {
+				eth.EXPECT().EstimateGas(gomock.Any()).Return(uint64(0), test.estimateErr)
+			}
+
+			_, err := manager.CreateTxWithGas(null.String{}, cltest.NewAddress(), []byte{1}, nil, 0)
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
@@ -5,6 +5,7 @@ package utils
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -68,8 +69,17 @@ func Uint64ToHex(i uint64) string {
 }
 
 // EncodeTxToHex converts the given Ethereum Transaction type and
-// returns its hex-value string.
-func EncodeTxToHex(tx *types.Transaction) (string, error) {
+// returns its hex-value string. Typed transactions are encoded as their
+// binary envelope, as eth_sendRawTransaction expects.
+func EncodeTxToHex(tx rlp.Encoder) (string, error) {
+	if typed, ok := tx.(encoding.BinaryMarshaler); ok {
+		b, err := typed.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return hexutil.Encode(b), nil
+	}
+
 	rlp := new(bytes.Buffer)
 	if err := tx.EncodeRLP(rlp); err != nil {
 		return "", err
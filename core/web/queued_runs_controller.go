@@ -0,0 +1,17 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/core/services"
+)
+
+// QueuedRunsController lists the runs waiting in the JobRunner's work queue.
+type QueuedRunsController struct {
+	App services.Application
+}
+
+// Index returns paginated queued runs, oldest first
+func (qrc *QueuedRunsController) Index(c *gin.Context, size, page, offset int) {
+	qrs, count, err := qrc.App.GetStore().QueuedRuns(offset, size)
+	paginatedResponse(c, "QueuedRuns", size, page, qrs, count, err)
+}
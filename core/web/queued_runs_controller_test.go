@@ -0,0 +1,52 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/assets"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueuedRunsController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+	store := app.GetStore()
+	client := app.NewHTTPClient()
+
+	j := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&j))
+	runs := []models.JobRun{}
+	for i := 0; i < 3; i++ {
+		jr := j.NewRun(j.Initiators[0])
+		jr.Payment = assets.NewLink(int64(i))
+		require.NoError(t, store.CreateJobRun(&jr))
+		qr := models.NewQueuedRun(jr)
+		require.NoError(t, store.CreateQueuedRun(&qr))
+		runs = append(runs, jr)
+	}
+
+	resp, cleanup := client.Get("/v2/queued_runs?size=2")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var links jsonapi.Links
+	var queued []models.QueuedRun
+	body := cltest.ParseResponseBody(t, resp)
+	require.NoError(t, web.ParsePaginatedResponse(body, &queued, &links))
+	assert.NotEmpty(t, links["next"].Href)
+	assert.Empty(t, links["prev"].Href)
+
+	require.Len(t, queued, 2)
+	assert.Equal(t, runs[0].ID, queued[0].JobRunID)
+	assert.Equal(t, j.ID, queued[0].JobSpecID)
+	assert.Equal(t, runs[1].ID, queued[1].JobRunID)
+	assert.Equal(t, assets.NewLink(1), queued[1].Payment)
+}
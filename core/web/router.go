@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +24,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gobuffalo/packr"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/store"
@@ -236,6 +239,19 @@ func metricRoutes(app services.Application, r *gin.RouterGroup) {
 	group := r.Group("/debug", sessionAuthRequired(app.GetStore()))
 	group.GET("/vars", expvar.Handler())
 
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		&linkEarnedCollector{store: app.GetStore()},
+		&ethEndpointsCollector{store: app.GetStore()},
+	)
+	metrics := promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, registry}, promhttp.HandlerOpts{})
+	if app.GetStore().Config.MetricsPublic() {
+		// No authentication so that Prometheus can scrape it
+		r.GET("/metrics", gin.WrapH(metrics))
+	} else {
+		r.GET("/metrics", sessionAuthRequired(app.GetStore()), gin.WrapH(metrics))
+	}
+
 	if app.GetStore().Config.Dev() {
 		// No authentication because `go tool pprof` doesn't support it
 		pprofGroup := r.Group("/debug/pprof")
@@ -254,6 +270,89 @@ func metricRoutes(app services.Application, r *gin.RouterGroup) {
 	}
 }
 
+var linkEarnedDesc = prometheus.NewDesc(
+	"job_link_earned",
+	"The total LINK earned by each job from its finished runs",
+	[]string{"job_spec_id"}, nil,
+)
+
+// linkEarnedCollector reports the LINK earned per job straight from the
+// database on every scrape, so that the totals survive restarts.
+type linkEarnedCollector struct {
+	store *store.Store
+}
+
+func (c *linkEarnedCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- linkEarnedDesc
+}
+
+func (c *linkEarnedCollector) Collect(ch chan<- prometheus.Metric) {
+	earned, err := c.store.LinkEarnedByJob()
+	if err != nil {
+		logger.Errorw("Unable to collect LINK earned", "err", err)
+		ch <- prometheus.NewInvalidMetric(linkEarnedDesc, err)
+		return
+	}
+	for jobSpecID, link := range earned {
+		value, err := strconv.ParseFloat(link.String(), 64)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(linkEarnedDesc, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(linkEarnedDesc, prometheus.GaugeValue, value, jobSpecID)
+	}
+}
+
+var (
+	ethEndpointHealthyDesc = prometheus.NewDesc(
+		"eth_endpoint_healthy",
+		"Whether each configured Ethereum endpoint is considered healthy",
+		[]string{"url"}, nil,
+	)
+	ethEndpointLatencyDesc = prometheus.NewDesc(
+		"eth_endpoint_latency_seconds",
+		"The moving average latency of requests to each configured Ethereum endpoint",
+		[]string{"url"}, nil,
+	)
+	ethEndpointHeadLagDesc = prometheus.NewDesc(
+		"eth_endpoint_head_lag",
+		"The number of blocks each configured Ethereum endpoint is behind the most advanced one",
+		[]string{"url"}, nil,
+	)
+	ethEndpointErrorsDesc = prometheus.NewDesc(
+		"eth_endpoint_errors_total",
+		"The total number of failed requests to each configured Ethereum endpoint",
+		[]string{"url"}, nil,
+	)
+)
+
+// ethEndpointsCollector reports the health the failover CallerSubscriber
+// keeps for each Ethereum endpoint. Nothing is reported when only a single
+// endpoint is configured.
+type ethEndpointsCollector struct {
+	store *store.Store
+}
+
+func (c *ethEndpointsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ethEndpointHealthyDesc
+	ch <- ethEndpointLatencyDesc
+	ch <- ethEndpointHeadLagDesc
+	ch <- ethEndpointErrorsDesc
+}
+
+func (c *ethEndpointsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, h := range c.store.EthEndpointsHealth() {
+		healthy := 0.0
+		if h.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(ethEndpointHealthyDesc, prometheus.GaugeValue, healthy, h.URL)
+		ch <- prometheus.MustNewConstMetric(ethEndpointLatencyDesc, prometheus.GaugeValue, h.Latency.Seconds(), h.URL)
+		ch <- prometheus.MustNewConstMetric(ethEndpointHeadLagDesc, prometheus.GaugeValue, float64(h.HeadLag), h.URL)
+		ch <- prometheus.MustNewConstMetric(ethEndpointErrorsDesc, prometheus.CounterValue, float64(h.TotalErrors), h.URL)
+	}
+}
+
 func pprofHandler(h http.HandlerFunc) gin.HandlerFunc {
 	handler := http.HandlerFunc(h)
 	return func(c *gin.Context) {
@@ -325,9 +424,14 @@ func v2Routes(app services.Application, r *gin.RouterGroup) {
 		tas := TxAttemptsController{app}
 		authv2.GET("/tx_attempts", paginatedRequest(tas.Index))
 
+		qrc := QueuedRunsController{app}
+		authv2.GET("/queued_runs", paginatedRequest(qrc.Index))
+
 		txs := TransactionsController{app}
 		authv2.GET("/transactions", paginatedRequest(txs.Index))
 		authv2.GET("/transactions/:TxHash", txs.Show)
+		authv2.POST("/transactions/:TxHash/cancel", txs.Cancel)
+		authv2.POST("/transactions/:TxHash/speedup", txs.SpeedUp)
 
 		bdc := BulkDeletesController{app}
 		authv2.DELETE("/bulk_delete_runs", bdc.Delete)
@@ -2,15 +2,21 @@ package web_test
 
 import (
 	"bytes"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/assets"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/web"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
 )
 
 func TestTokenAuthRequired_NoCredentials(t *testing.T) {
@@ -175,3 +181,52 @@ func TestRouter_GinHelmetHeaders(t *testing.T) {
 			"wrong header for helmet's %s handler", tt.HelmetName)
 	}
 }
+
+func TestRouter_Metrics(t *testing.T) {
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.GetStore().CreateJob(&job))
+	jr := job.NewRun(job.Initiators[0])
+	jr.Payment = assets.NewLink(1000000000)
+	jr.FinishedAt = null.TimeFrom(time.Now())
+	require.NoError(t, app.GetStore().CreateJobRun(&jr))
+
+	router := web.Router(app)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	unauthed, err := http.Get(ts.URL + "/metrics")
+	require.NoError(t, err)
+	defer unauthed.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, unauthed.StatusCode)
+
+	resp, cleanup := client.Get("/metrics")
+	defer cleanup()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	lines := strings.Split(string(body), "\n")
+	assert.Contains(t, lines, fmt.Sprintf(`job_link_earned{job_spec_id="%s"} 1e-09`, job.ID.String()))
+	assert.Contains(t, string(body), "head_tracker_current_head")
+}
+
+func TestRouter_Metrics_Public(t *testing.T) {
+	config, cfgCleanup := cltest.NewConfig(t)
+	defer cfgCleanup()
+	config.Set("METRICS_PUBLIC", true)
+	app, cleanup := cltest.NewApplicationWithConfigAndKey(t, config)
+	defer cleanup()
+
+	router := web.Router(app)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
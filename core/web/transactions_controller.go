@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
 )
@@ -40,3 +41,29 @@ func (tc *TransactionsController) Show(c *gin.Context) {
 		jsonAPIResponse(c, presenters.NewTxFromAttempt(*txAttempt), "transaction")
 	}
 }
+
+// Cancel replaces the transaction with a zero value transfer to its sender at
+// the same nonce, so that it is never mined.
+// Example:
+//  "<application>/transactions/:TxHash/cancel"
+func (tc *TransactionsController) Cancel(c *gin.Context) {
+	tc.replace(c, tc.App.GetStore().TxManager.CancelTx)
+}
+
+// SpeedUp bumps the gas of the transaction right away.
+// Example:
+//  "<application>/transactions/:TxHash/speedup"
+func (tc *TransactionsController) SpeedUp(c *gin.Context) {
+	tc.replace(c, tc.App.GetStore().TxManager.SpeedUpTx)
+}
+
+func (tc *TransactionsController) replace(c *gin.Context, replace func(common.Hash) (*models.Tx, error)) {
+	hash := common.HexToHash(c.Param("TxHash"))
+	if tx, err := replace(hash); errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("Transaction not found"))
+	} else if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+	} else {
+		jsonAPIResponse(c, presenters.NewTx(tx), "transaction")
+	}
+}
@@ -144,3 +144,52 @@ func TestTransactionsController_Show_NotFound(t *testing.T) {
 	defer cleanup()
 	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
 }
+
+func TestTransactionsController_CancelAndSpeedUp(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	store := app.GetStore()
+	ethMock := app.MockEthCallerSubscriber()
+	ethMock.Context("app.Start()", func(ethMock *cltest.EthMock) {
+		ethMock.Register("eth_getTransactionCount", "0x0")
+		ethMock.Register("eth_chainId", store.Config.ChainID())
+	})
+	require.NoError(t, app.StartAndConnect())
+	client := app.NewHTTPClient()
+	from := cltest.GetAccountAddress(t, store)
+	tx := cltest.CreateTx(t, store, from, 1)
+
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	resp, cleanup := client.Post("/v2/transactions/"+tx.Hash.Hex()+"/speedup", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	ptx := presenters.Tx{}
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &ptx))
+	assert.NotEqual(t, tx.Hash, ptx.Hash)
+	assert.False(t, ptx.Cancelled)
+
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	resp, cleanup = client.Post("/v2/transactions/"+tx.Hash.Hex()+"/cancel", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &ptx))
+	assert.True(t, ptx.Cancelled)
+	assert.Equal(t, from.Hex(), ptx.From.Hex())
+
+	tx, err := store.FindTx(tx.ID)
+	require.NoError(t, err)
+	assert.Len(t, tx.Attempts, 3)
+	assert.True(t, tx.Cancelled)
+
+	resp, cleanup = client.Post("/v2/transactions/"+cltest.NewHash().Hex()+"/cancel", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+
+	require.NoError(t, store.MarkTxSafe(tx, tx.Attempts[2]))
+	resp, cleanup = client.Post("/v2/transactions/"+tx.Hash.Hex()+"/speedup", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+}
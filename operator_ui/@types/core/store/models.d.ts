@@ -36,6 +36,21 @@ declare module 'core/store/models' {
     type: TaskType
     confirmations: clnull.Uint32
     params: T
+    retry?: RetryPolicy
+    name?: string
+    dependsOn?: string[]
+  }
+
+  /**
+   * RetryPolicy describes how many times and how often a failed task is
+   * attempted again before its run is marked as errored.
+   */
+  export interface RetryPolicy {
+    maxAttempts: number
+    backoff?: 'exponential' | 'linear' | 'constant'
+    initial?: string
+    max?: string
+    retryOn?: ('timeout' | '4xx' | '5xx' | 'other')[]
   }
 
   /**
@@ -81,6 +96,20 @@ declare module 'core/store/models' {
      * Solidity Coordinator contract and expect a payload from a log event.
      */
     SERVICE_AGREEMENT_EXECUTION_LOG = 'execagreement',
+    /**
+     * InitiatorFluxMonitor for tasks in a job to be ran when the value of
+     * polled feeds deviates from the one on chain, or it is too old.
+     */
+    FLUX_MONITOR = 'fluxmonitor',
+    /**
+     * InitiatorBlocks for tasks in a job to be ran every so many blocks.
+     */
+    BLOCKS = 'blocks',
+    /**
+     * InitiatorTxConfirmations for tasks in a job to be ran once a
+     * transaction has enough confirmations.
+     */
+    TX_CONFIRMATIONS = 'txconfirmations',
   }
 
   /**
@@ -108,8 +137,27 @@ declare module 'core/store/models' {
     ran?: boolean
     address?: common.Address
     requesters?: AddressCollection
+    eventABI?: string | JSONValue
+    feeds?: JSONValue
+    threshold?: number
+    precision?: number
+    pollingInterval?: string
+    heartbeat?: string
+    blockInterval?: number
+    txHash?: common.Hash
+    confirmations?: number
+    timeZone?: string
+    jitter?: string
+    catchUp?: CatchUpPolicy
+    lastFiredAt?: nullable.Time
   }
 
+  /**
+   * CatchUpPolicy is how a cron initiator makes up for the fires it missed
+   * while the node was down.
+   */
+  export type CatchUpPolicy = 'skip' | 'run-once' | 'run-all'
+
   /**
    * TaskSpec is the definition of work to be carried out. The
    * Type will be an adapter, and the Params will contain any
@@ -120,6 +168,9 @@ declare module 'core/store/models' {
     type: TaskType
     confirmations: clnull.Uint32
     params: T
+    retry?: RetryPolicy
+    name?: string
+    dependsOn?: string[]
   }
 
   /**
@@ -162,6 +213,9 @@ declare module 'core/store/models' {
     task: TaskSpec
     minimumConfirmations: clnull.Uint32
     confirmations: clnull.Uint32
+    attempts: number
+    lastError: nullable.String
+    retryAt: nullable.Time
   }
 
   /**
@@ -196,8 +250,11 @@ declare module 'core/store/models' {
     PENDING_CONNECTION = 'pending_connection',
     PENDING_BRIDGE = 'pending_bridge',
     PENDING_SLEEP = 'pending_sleep',
+    PENDING_RETRY = 'pending_retry',
     ERRORED = 'errored',
     COMPLETED = 'completed',
+    SKIPPED = 'skipped',
+    CANCELLED = 'cancelled',
   }
 
   /**
@@ -363,6 +420,12 @@ declare module 'core/store/models' {
     Value: Pointer<Big>
     GasLimit: number // FIXME -- possibly unsafe uint64
 
+    /**
+     * Cancelled is set once the transaction is replaced by a zero value
+     * transfer to its sender at the same nonce, which later attempts send.
+     */
+    Cancelled: boolean
+
     /**
      * TxAttempt fields manually included; can't embed another primary_key
      */
@@ -371,6 +434,12 @@ declare module 'core/store/models' {
     Confirmed: boolean
     SentAt: number // FIXME -- possibly unsafe uint64
     SignedRawTx: string
+
+    /**
+     * Fee caps of an EIP-1559 dynamic fee transaction, nil for legacy ones
+     */
+    MaxFeePerGas: Pointer<Big>
+    MaxPriorityFeePerGas: Pointer<Big>
   }
 
   /**
@@ -392,6 +461,12 @@ declare module 'core/store/models' {
     Confirmed: boolean
     SentAt: number // FIXME -- possibly unsafe uint64
     SignedRawTx: string
+
+    /**
+     * Fee caps of an EIP-1559 dynamic fee transaction, nil for legacy ones
+     */
+    MaxFeePerGas: Pointer<Big>
+    MaxPriorityFeePerGas: Pointer<Big>
   }
   //#endregion eth.go
   //#region external_initiator.go
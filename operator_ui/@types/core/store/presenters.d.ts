@@ -34,9 +34,19 @@ declare module 'core/store/presenters' {
     /**
      * FIXME -- precision loss
      */
+    ethDynamicFees: boolean
     ethGasBumpThreshold: number
     ethGasBumpWei: Pointer<big.Int>
+    ethGasLimitMargin: number
     ethGasPriceDefault: Pointer<big.Int>
+    ethGasPriceOracleBlocks: number
+    ethGasPricePercentile: number
+    ethLogBlockRange: number
+    ethMaxGasPriceWei: Pointer<big.Int>
+    ethPollInterval: time.Duration
+    ethPriorityFeeWei: Pointer<big.Int>
+    ethStuckTxBlocks: number
+    ethTxSimulation: boolean
     jsonConsole: boolean
     linkContractAddress: string
     explorerUrl: string
@@ -81,6 +91,9 @@ declare module 'core/store/presenters' {
     from?: Pointer<common.Address>
     gasLimit?: string
     gasPrice?: string
+    maxFeePerGas?: string
+    maxPriorityFeePerGas?: string
+    cancelled?: boolean
     hash?: common.Hash
     rawHex?: string
     nonce?: string